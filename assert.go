@@ -0,0 +1,50 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+// MustValidate calls the MustValidate method on the default validator.
+func MustValidate(v interface{}) {
+	defaultValidator.MustValidate(v)
+}
+
+// MustValidate validates v like Validate and panics with the error, such
+// as the ErrorMap listing every violation, if v is invalid. It is meant
+// for values built by the program itself, whose violations are bugs,
+// rather than for user input.
+func (mv *Validator) MustValidate(v interface{}) {
+	if err := mv.Validate(v); err != nil {
+		panic(err)
+	}
+}
+
+// Assert calls the Assert method on the default validator.
+func Assert(v interface{}) {
+	defaultValidator.Assert(v)
+}
+
+// Assert checks an invariant of the program: in builds with the
+// validator_debug tag, such as those of tests and staging, it calls
+// MustValidate, and in other builds it does nothing, not even validate
+// v, so that it costs nothing in production.
+//
+//	quote := pricing.Quote(cart)
+//	validator.Assert(quote) // panics in debug builds if the quote is invalid
+func (mv *Validator) Assert(v interface{}) {
+	if debug {
+		mv.MustValidate(v)
+	}
+}
@@ -0,0 +1,33 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build validator_debug
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestAssertDebug(c *C) {
+	type quote struct {
+		Total int `validate:"min=1"`
+	}
+	validator.Assert(quote{Total: 3})
+	c.Assert(func() { validator.Assert(quote{}) }, PanicMatches, "Total: less than min")
+	c.Assert(func() { validator.NewValidator().Assert(nil) }, PanicMatches, ".*")
+}
@@ -0,0 +1,251 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// AuditRecord proves that a payload was validated, for regulated flows
+// such as KYC or payments. It is encoded as JSON.
+type AuditRecord struct {
+	// Time is when the payload was validated.
+	Time time.Time `json:"time"`
+	// Type is the Go type of the validated value.
+	Type string `json:"type"`
+	// RulesVersion is the SHA-256 hash of the rules enforced, as
+	// exported by ExportRules with their aliases expanded, so that
+	// records tell which version of the rules validated the payload.
+	RulesVersion string `json:"rules_version"`
+	// RulesError is set, and RulesVersion empty, if the rules cannot
+	// be exported, such as for values other than structs and the
+	// slices and maps holding them.
+	RulesError string `json:"rules_error,omitempty"`
+	// PayloadHash is the SHA-256 hash of the payload given with
+	// WithAuditPayload, or else of the JSON encoding of the value.
+	PayloadHash string `json:"payload_hash"`
+	// PayloadError is set, and PayloadHash empty, if the value cannot
+	// be encoded as JSON, such as a channel or a value referring to
+	// itself, and no payload was given with WithAuditPayload.
+	PayloadError string `json:"payload_error,omitempty"`
+	// Valid, Errors and Warnings are the outcome, as given by Check.
+	Valid    bool         `json:"valid"`
+	Errors   []FieldError `json:"errors"`
+	Warnings []FieldError `json:"warnings,omitempty"`
+	// Signature is set by Sign.
+	Signature string `json:"signature,omitempty"`
+}
+
+// AuditFunc is called with the record of every validation of a
+// validator in audit mode, such as to append it to a log.
+type AuditFunc func(ctx context.Context, r AuditRecord)
+
+// Audit returns an Option putting a validator in audit mode, in which
+// the record of every call to Validate and ValidateContext is passed to
+// fn.
+//
+//	v := validator.WithOptions(validator.Audit(func(ctx context.Context, r validator.AuditRecord) {
+//		r, _ = r.Sign(auditKey)
+//		json.NewEncoder(auditLog).Encode(r)
+//	}))
+func Audit(fn AuditFunc) Option {
+	return func(mv *Validator) {
+		mv.audit = &auditor{fn: fn}
+	}
+}
+
+// auditor holds the audit function of a validator.
+type auditor struct {
+	fn AuditFunc
+}
+
+// rulesKey identifies the rules a validator enforces on a type.
+type rulesKey struct {
+	typ              reflect.Type
+	tagName, nameTag string
+}
+
+// rulesVersion is the version of the rules of a type, computed when the
+// rules were last changed.
+type rulesVersion struct {
+	gen     uint64
+	version string
+	err     string
+}
+
+// rulesGen is changed whenever the rules or functions of a validator are
+// changed other than by its tags, such as by SetFieldRules, SetAlias or
+// Reload, so that the versions of rules are computed again.
+var rulesGen uint64
+
+// rulesChanged marks the rules of validators as changed.
+func rulesChanged() {
+	atomic.AddUint64(&rulesGen, 1)
+}
+
+// rulesVersion returns the version of the rules mv enforces on the type t,
+// or the error exporting them.
+func (mv *Validator) rulesVersion(t reflect.Type) (string, string) {
+	gen := atomic.LoadUint64(&rulesGen)
+	key := rulesKey{t, mv.tagName, mv.nameTag}
+	if v, ok := mv.versions.Load(key); ok && v.(rulesVersion).gen == gen {
+		return v.(rulesVersion).version, v.(rulesVersion).err
+	}
+	v := rulesVersion{gen: gen}
+	rs, err := mv.ExportRules(reflect.Zero(t).Interface())
+	if err == nil {
+		err = mv.expandAliases(rs)
+	}
+	if err == nil {
+		var data []byte
+		if data, err = json.Marshal(rs); err == nil {
+			v.version = hash(data)
+		}
+	}
+	if err != nil {
+		v.err = err.Error()
+	}
+	mv.versions.Store(key, v)
+	return v.version, v.err
+}
+
+// expandAliases replaces the aliases used by the rules of rs with the
+// rules they stand for, so that changing an alias changes the version of
+// the rules using it.
+func (mv *Validator) expandAliases(rs *RuleSet) error {
+	expand := func(rules string) (string, error) {
+		if rules == "" {
+			return "", nil
+		}
+		tags, err := mv.parseTags(rules)
+		if err != nil {
+			return "", err
+		}
+		expanded := make([]string, len(tags))
+		for i, tg := range tags {
+			expanded[i] = tg.Name
+			if tg.Param != "" {
+				expanded[i] += "=" + strings.Replace(tg.Param, ",", `\,`, -1)
+			}
+		}
+		return strings.Join(expanded, ","), nil
+	}
+	for name, tr := range rs.Types {
+		var err error
+		if tr.Rules, err = expand(tr.Rules); err != nil {
+			return err
+		}
+		for key, fr := range tr.Fields {
+			if fr.Rules, err = expand(fr.Rules); err != nil {
+				return err
+			}
+			tr.Fields[key] = fr
+		}
+		rs.Types[name] = tr
+	}
+	return nil
+}
+
+type auditPayloadKey struct{}
+
+// WithAuditPayload returns a copy of ctx whose validations are recorded
+// with the hash of payload, such as the body of the request the value
+// was decoded from, rather than with that of the JSON encoding of the
+// value.
+func WithAuditPayload(ctx context.Context, payload []byte) context.Context {
+	return context.WithValue(ctx, auditPayloadKey{}, payload)
+}
+
+// record passes the record of the validation of v with ctx, which
+// returned err, to the audit function of mv.
+func (mv *Validator) record(ctx context.Context, v interface{}, err error) {
+	r := AuditRecord{Time: time.Now().UTC(), Errors: []FieldError{}}
+	if t := reflect.TypeOf(v); t != nil {
+		r.Type = t.String()
+		// the rules of a slice or map are those of its items
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		r.RulesVersion, r.RulesError = mv.rulesVersion(t)
+	} else {
+		r.RulesError = ErrInvalid.Error()
+	}
+	if payload, ok := ctx.Value(auditPayloadKey{}).([]byte); ok {
+		r.PayloadHash = hash(payload)
+	} else if payload, err := json.Marshal(v); err != nil {
+		r.PayloadError = err.Error()
+	} else {
+		r.PayloadHash = hash(payload)
+	}
+	res := newResult(err)
+	r.Valid = res.Ok()
+	r.Errors = append(r.Errors, res.errors...)
+	r.Warnings = res.warnings
+	mv.audit.fn(ctx, r)
+}
+
+// hash returns the SHA-256 hash of data, as in "sha256:<hex>".
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// Sign returns r signed with key, an HMAC-SHA256 of the JSON encoding of
+// r without its signature.
+func (r AuditRecord) Sign(key []byte) (AuditRecord, error) {
+	mac, err := r.mac(key)
+	if err != nil {
+		return r, err
+	}
+	r.Signature = "hmac-sha256:" + hex.EncodeToString(mac)
+	return r, nil
+}
+
+// Verify returns whether r, such as one decoded from an audit log, was
+// signed with key and left unchanged since.
+func (r AuditRecord) Verify(key []byte) bool {
+	const prefix = "hmac-sha256:"
+	if len(r.Signature) < len(prefix) || r.Signature[:len(prefix)] != prefix {
+		return false
+	}
+	sig, err := hex.DecodeString(r.Signature[len(prefix):])
+	if err != nil {
+		return false
+	}
+	mac, err := r.mac(key)
+	return err == nil && hmac.Equal(sig, mac)
+}
+
+// mac returns the HMAC-SHA256 of r without its signature.
+func (r AuditRecord) mac(key []byte) ([]byte, error) {
+	r.Signature = ""
+	data, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
@@ -0,0 +1,135 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestAudit(c *C) {
+	type kyc struct {
+		Name    string `json:"name" validate:"nonzero"`
+		Country string `json:"country" validate:"len=2"`
+	}
+	var records []validator.AuditRecord
+	v := validator.NewValidator().WithOptions(validator.Audit(func(ctx context.Context, r validator.AuditRecord) {
+		records = append(records, r)
+	}))
+
+	c.Assert(v.Validate(kyc{Name: "Jane", Country: "FR"}), IsNil)
+	body := []byte(`{"name":"","country":"FRA"}`)
+	c.Assert(v.ValidateContext(validator.WithAuditPayload(context.Background(), body), &kyc{Country: "FRA"}), NotNil)
+	c.Assert(records, HasLen, 2)
+
+	r := records[0]
+	c.Assert(r.Type, Equals, "validator_test.kyc")
+	c.Assert(r.Valid, Equals, true)
+	c.Assert(r.Errors, HasLen, 0)
+	sum := sha256.Sum256([]byte(`{"name":"Jane","country":"FR"}`))
+	c.Assert(r.PayloadHash, Equals, "sha256:"+hex.EncodeToString(sum[:]))
+	c.Assert(records[1].RulesVersion, Equals, r.RulesVersion)
+	c.Assert(r.RulesVersion, Matches, "sha256:[0-9a-f]{64}")
+
+	r = records[1]
+	c.Assert(r.Type, Equals, "*validator_test.kyc")
+	c.Assert(r.Valid, Equals, false)
+	sum = sha256.Sum256(body)
+	c.Assert(r.PayloadHash, Equals, "sha256:"+hex.EncodeToString(sum[:]))
+	c.Assert(r.Errors, DeepEquals, []validator.FieldError{
+		{Field: "Country", Err: validator.ErrLen},
+		{Field: "Name", Err: validator.ErrZeroValue},
+	})
+
+	// the rules version changes with the rules
+	c.Assert(v.SetFieldRules(kyc{}, "Country", "len=3"), IsNil)
+	c.Assert(v.Validate(kyc{Name: "Jane", Country: "FRA"}), IsNil)
+	c.Assert(records[2].RulesVersion, Not(Equals), records[1].RulesVersion)
+
+	key := []byte("audit key")
+	signed, err := r.Sign(key)
+	c.Assert(err, IsNil)
+	c.Assert(signed.Signature, Matches, "hmac-sha256:[0-9a-f]{64}")
+	data, err := json.Marshal(signed)
+	c.Assert(err, IsNil)
+	var decoded validator.AuditRecord
+	c.Assert(json.Unmarshal(data, &decoded), IsNil)
+	c.Assert(decoded.Verify(key), Equals, true)
+	c.Assert(decoded.Errors[0].Error(), Equals, "Country: invalid length")
+	c.Assert(decoded.Verify([]byte("other key")), Equals, false)
+	decoded.Valid = true
+	c.Assert(decoded.Verify(key), Equals, false)
+	c.Assert(r.Verify(key), Equals, false)
+}
+
+func (ms *MySuite) TestAuditErrors(c *C) {
+	type job struct {
+		Name string        `validate:"nonzero"`
+		Done chan struct{} `json:"done"`
+	}
+	var records []validator.AuditRecord
+	v := validator.NewValidator().WithOptions(validator.Audit(func(ctx context.Context, r validator.AuditRecord) {
+		records = append(records, r)
+	}))
+
+	c.Assert(v.Validate(job{Name: "a", Done: make(chan struct{})}), IsNil)
+	c.Assert(records[0].PayloadHash, Equals, "")
+	c.Assert(records[0].PayloadError, Matches, ".*unsupported type.*")
+	c.Assert(records[0].RulesVersion, Matches, "sha256:.*")
+	c.Assert(records[0].RulesError, Equals, "")
+
+	c.Assert(v.ValidateContext(validator.WithAuditPayload(context.Background(), []byte("{}")), job{Name: "a"}), IsNil)
+	c.Assert(records[1].PayloadHash, Matches, "sha256:.*")
+	c.Assert(records[1].PayloadError, Equals, "")
+
+	c.Assert(v.Validate([]int{1}), IsNil)
+	c.Assert(records[2].RulesVersion, Equals, "")
+	c.Assert(records[2].RulesError, Equals, validator.ErrUnsupported.Error())
+}
+
+func (ms *MySuite) TestAuditRulesVersions(c *C) {
+	type account struct {
+		Owner string `validate:"owner"`
+	}
+	versions := map[string]string{}
+	audit := func(name string) validator.Option {
+		return validator.Audit(func(ctx context.Context, r validator.AuditRecord) {
+			versions[name] = r.RulesVersion
+		})
+	}
+	base := validator.NewValidator()
+	c.Assert(base.SetAlias("owner", "nonzero"), IsNil)
+	parent := base.WithOptions(audit("parent"))
+	child := parent.WithOptions(audit("child"))
+	c.Assert(child.SetFieldRules(account{}, "Owner", "nonzero,max=10"), IsNil)
+
+	parent.Validate(account{Owner: "a"})
+	child.Validate(account{Owner: "a"})
+	c.Assert(versions["parent"], Matches, "sha256:.*")
+	c.Assert(versions["child"], Not(Equals), versions["parent"])
+
+	// versions follow the rules aliases stand for
+	before := versions["parent"]
+	c.Assert(parent.SetAlias("owner", "nonzero,max=20"), IsNil)
+	parent.Validate(account{Owner: "a"})
+	c.Assert(versions["parent"], Not(Equals), before)
+}
@@ -0,0 +1,199 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrBatchClosed is the error returned when an item is submitted to a
+// closed BatchValidator.
+var ErrBatchClosed = errors.New("validator: batch validator closed")
+
+// BatchConfig configures a BatchValidator.
+type BatchConfig struct {
+	// Workers is the number of items validated at once, GOMAXPROCS if
+	// zero.
+	Workers int
+	// QueueSize is the number of items submitted but not yet validated,
+	// and of results not yet received, beyond which Submit blocks. It is
+	// Workers if zero.
+	QueueSize int
+	// Timeout is the time allowed to validate an item, if not zero. It
+	// is the deadline of the context of the validation, which field
+	// validation functions calling remote services should honor:
+	// validation functions ignoring it run to completion, and the
+	// result of the item is then theirs.
+	Timeout time.Duration
+}
+
+// BatchResult is the outcome of the validation of an item submitted to
+// a BatchValidator.
+type BatchResult[T any] struct {
+	// Index is the number of items submitted before the item.
+	Index int
+	Item  T
+	// Err is the error returned by ValidateContext, or the error of
+	// the context of the validation, such as context.DeadlineExceeded,
+	// if the validation returned it as the context ended.
+	Err error
+}
+
+// BatchValidator validates the items submitted to it with a pool of
+// workers and streams the results, for pipelines validating many items
+// asynchronously. Its queue is bounded: once it is full, Submit blocks
+// until an item is taken, and items are only taken while results are
+// received, so that a slow consumer slows the producers down.
+type BatchValidator[T any] struct {
+	// next is the index of the next item submitted. It is first to be
+	// aligned for atomic operations on 32-bit platforms.
+	next int64
+
+	mv      *Validator
+	timeout time.Duration
+	queue   chan batchItem[T]
+	results chan BatchResult[T]
+	// slots holds a value for every item queued or being queued, so
+	// that items are given an index only once there is room for them.
+	slots chan struct{}
+	wg    sync.WaitGroup
+
+	// done is closed once Close is called, to release the items waiting
+	// for room in the queue.
+	done     chan struct{}
+	doneOnce sync.Once
+	// mu guards closed, and is held by Submit while it queues items so
+	// that Close does not close the queue under it.
+	mu     sync.RWMutex
+	closed bool
+}
+
+// batchItem is an item submitted to a BatchValidator.
+type batchItem[T any] struct {
+	ctx   context.Context
+	index int
+	item  T
+}
+
+// NewBatchValidator returns a BatchValidator validating items with mv, or
+// the default validator if mv is nil. Its workers run until it is
+// closed.
+//
+//	b := validator.NewBatchValidator[Event](nil, validator.BatchConfig{Workers: 8, Timeout: time.Second})
+//	go func() {
+//		for _, e := range events {
+//			b.Submit(ctx, e)
+//		}
+//		b.Close()
+//	}()
+//	for r := range b.Results() {
+//		...
+//	}
+func NewBatchValidator[T any](mv *Validator, c BatchConfig) *BatchValidator[T] {
+	if mv == nil {
+		mv = defaultValidator
+	}
+	if c.Workers <= 0 {
+		c.Workers = runtime.GOMAXPROCS(0)
+	}
+	if c.QueueSize <= 0 {
+		c.QueueSize = c.Workers
+	}
+	b := &BatchValidator[T]{
+		mv:      mv,
+		timeout: c.Timeout,
+		queue:   make(chan batchItem[T], c.QueueSize),
+		results: make(chan BatchResult[T], c.QueueSize),
+		slots:   make(chan struct{}, c.QueueSize),
+		done:    make(chan struct{}),
+	}
+	b.wg.Add(c.Workers)
+	for i := 0; i < c.Workers; i++ {
+		go b.work()
+	}
+	return b
+}
+
+// Submit queues item to be validated with ctx and returns its index. It
+// blocks while the queue is full, and returns the error of ctx if ctx
+// ends first, or ErrBatchClosed if b is or gets closed first, in which
+// case the item is given no index.
+func (b *BatchValidator[T]) Submit(ctx context.Context, item T) (int, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return 0, ErrBatchClosed
+	}
+	select {
+	case b.slots <- struct{}{}:
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	case <-b.done:
+		return 0, ErrBatchClosed
+	}
+	index := int(atomic.AddInt64(&b.next, 1) - 1)
+	b.queue <- batchItem[T]{ctx, index, item}
+	return index, nil
+}
+
+// Results returns the channel the results are sent on, in the order the
+// validations end, which is closed once b is closed and the items
+// submitted are validated. It must be read for the items to be taken.
+func (b *BatchValidator[T]) Results() <-chan BatchResult[T] {
+	return b.results
+}
+
+// Close stops b from taking more items. The items already submitted are
+// still validated, while those waiting for room in the queue are given
+// up with ErrBatchClosed. It is safe to call more than once.
+func (b *BatchValidator[T]) Close() {
+	b.doneOnce.Do(func() { close(b.done) })
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	b.closed = true
+	close(b.queue)
+	go func() {
+		b.wg.Wait()
+		close(b.results)
+	}()
+}
+
+// work validates the items of the queue until it is closed.
+func (b *BatchValidator[T]) work() {
+	defer b.wg.Done()
+	for it := range b.queue {
+		<-b.slots
+		ctx, cancel := it.ctx, context.CancelFunc(func() {})
+		if b.timeout > 0 {
+			ctx, cancel = context.WithTimeout(ctx, b.timeout)
+		}
+		err := b.mv.ValidateContext(ctx, it.item)
+		if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+			err = ctxErr
+		}
+		cancel()
+		b.results <- BatchResult[T]{Index: it.index, Item: it.item, Err: err}
+	}
+}
@@ -0,0 +1,141 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type batchEvent struct {
+	ID   int    `validate:"min=1"`
+	Slow bool   `validate:"slow"`
+	Late bool   `validate:"late"`
+	Name string `validate:"nonzero"`
+}
+
+func (ms *MySuite) TestBatchValidator(c *C) {
+	v := validator.NewValidator()
+	v.SetFieldValidationFunc("slow", func(i interface{}, f validator.Field, param string) error {
+		if !i.(bool) {
+			return nil
+		}
+		select {
+		case <-f.Context().Done():
+			return f.Context().Err()
+		case <-time.After(time.Second):
+		}
+		return nil
+	})
+	// late ignores the deadline of the validation
+	v.SetValidationFunc("late", func(i interface{}, param string) error {
+		if i.(bool) {
+			time.Sleep(50 * time.Millisecond)
+		}
+		return nil
+	})
+	b := validator.NewBatchValidator[batchEvent](v, validator.BatchConfig{Workers: 2, Timeout: 20 * time.Millisecond})
+	events := []batchEvent{{ID: 1, Name: "a"}, {ID: 0, Name: "b"}, {ID: 3, Slow: true, Name: "c"}, {ID: 4}, {ID: 0, Late: true, Name: "e"}, {ID: 6, Late: true, Name: "f"}}
+	go func() {
+		for i, e := range events {
+			index, err := b.Submit(context.Background(), e)
+			c.Check(err, IsNil)
+			c.Check(index, Equals, i)
+		}
+		b.Close()
+		b.Close()
+	}()
+
+	var results []validator.BatchResult[batchEvent]
+	for r := range b.Results() {
+		results = append(results, r)
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+	c.Assert(results, HasLen, 6)
+	c.Assert(results[0].Err, IsNil)
+	c.Assert(results[1].Item, Equals, events[1])
+	c.Assert(results[1].Err.(validator.ErrorMap)["ID"], HasError, validator.ErrMin)
+	c.Assert(results[2].Err, Equals, context.DeadlineExceeded)
+	c.Assert(results[3].Err.(validator.ErrorMap)["Name"], HasError, validator.ErrZeroValue)
+	// validations ending after their deadline keep their results
+	c.Assert(results[4].Err.(validator.ErrorMap)["ID"], HasError, validator.ErrMin)
+	c.Assert(results[5].Err, IsNil)
+
+	_, err := b.Submit(context.Background(), events[0])
+	c.Assert(err, Equals, validator.ErrBatchClosed)
+}
+
+func (ms *MySuite) TestBatchValidatorBackpressure(c *C) {
+	b := validator.NewBatchValidator[batchEvent](validator.NewValidator(), validator.BatchConfig{Workers: 1, QueueSize: 1})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	// with no one receiving the results, one result is buffered, one is
+	// being sent and one item is queued
+	var err error
+	submitted := 0
+	for err == nil && submitted < 10 {
+		_, err = b.Submit(ctx, batchEvent{ID: 1, Name: "a"})
+		if err == nil {
+			submitted++
+		}
+	}
+	c.Assert(err, Equals, context.DeadlineExceeded)
+	c.Assert(submitted, Equals, 3)
+
+	// cancelled submissions are given no index
+	done := make(chan []int)
+	go func() {
+		var indexes []int
+		for r := range b.Results() {
+			indexes = append(indexes, r.Index)
+		}
+		done <- indexes
+	}()
+	index, err := b.Submit(context.Background(), batchEvent{ID: 1, Name: "a"})
+	c.Assert(err, IsNil)
+	c.Assert(index, Equals, 3)
+	b.Close()
+	indexes := <-done
+	sort.Ints(indexes)
+	c.Assert(indexes, DeepEquals, []int{0, 1, 2, 3})
+}
+
+func (ms *MySuite) TestBatchValidatorCloseReleasesSubmit(c *C) {
+	b := validator.NewBatchValidator[batchEvent](validator.NewValidator(), validator.BatchConfig{Workers: 1, QueueSize: 1})
+	for i := 0; i < 3; i++ {
+		_, err := b.Submit(context.Background(), batchEvent{ID: 1, Name: "a"})
+		c.Assert(err, IsNil)
+	}
+	// the queue is full and no one receives the results
+	errs := make(chan error)
+	go func() {
+		_, err := b.Submit(context.Background(), batchEvent{ID: 1, Name: "a"})
+		errs <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	b.Close()
+	c.Assert(<-errs, Equals, validator.ErrBatchClosed)
+	n := 0
+	for range b.Results() {
+		n++
+	}
+	c.Assert(n, Equals, 3)
+}
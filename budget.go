@@ -0,0 +1,83 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+// Soft returns an Option marking the rules called names as soft: their
+// errors are reported as Warnings, which Check tells apart from errors
+// and ValidateWithBudget tolerates up to a budget.
+func Soft(names ...string) Option {
+	return func(mv *Validator) {
+		soft := make(map[string]bool, len(mv.soft)+len(names))
+		for name := range mv.soft {
+			soft[name] = true
+		}
+		for _, name := range names {
+			soft[name] = true
+		}
+		mv.soft = soft
+	}
+}
+
+// soften returns err, or the errors of err if it is an ErrorArray, as
+// warnings.
+func soften(err error) error {
+	arr, ok := err.(ErrorArray)
+	if !ok {
+		if _, ok := err.(Warning); ok {
+			return err
+		}
+		return Warning{err}
+	}
+	warnings := make(ErrorArray, len(arr))
+	for i, e := range arr {
+		warnings[i] = soften(e)
+	}
+	return warnings
+}
+
+// ValidateWithBudget calls the ValidateWithBudget method on the default
+// validator.
+func ValidateWithBudget(v interface{}, n int) (bool, error) {
+	return defaultValidator.ValidateWithBudget(v, n)
+}
+
+// ValidateWithBudget validates v like Validate, and returns whether v is
+// accepted along with the errors found. Up to n warnings, such as the
+// errors of the rules marked with Soft, are tolerated, so that records
+// which are slightly dirty can be accepted while those breaking any
+// other rule are rejected. The error lists all the violations, including
+// those tolerated.
+func (mv *Validator) ValidateWithBudget(v interface{}, n int) (bool, error) {
+	err := mv.Validate(v)
+	if err == nil {
+		return true, nil
+	}
+	m, ok := err.(ErrorMap)
+	if !ok {
+		return false, err
+	}
+	warnings := 0
+	for _, errs := range m {
+		for _, e := range errs {
+			if _, ok := e.(Warning); !ok {
+				return false, err
+			}
+			warnings++
+		}
+	}
+	return warnings <= n, err
+}
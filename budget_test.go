@@ -0,0 +1,58 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"errors"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestValidateWithBudget(c *C) {
+	type record struct {
+		ID    string `validate:"nonzero"`
+		Name  string `validate:"max=8"`
+		Email string `validate:"regexp=@"`
+	}
+	v := validator.NewValidator().WithOptions(validator.Soft("max", "regexp"))
+
+	ok, err := v.ValidateWithBudget(record{ID: "1", Name: "Jane", Email: "jane@example.com"}, 0)
+	c.Assert(ok, Equals, true)
+	c.Assert(err, IsNil)
+
+	dirty := record{ID: "2", Name: "Bartholomew", Email: "bart"}
+	ok, err = v.ValidateWithBudget(dirty, 2)
+	c.Assert(ok, Equals, true)
+	c.Assert(errors.Is(err.(validator.ErrorMap)["Name"][0], validator.ErrMax), Equals, true)
+	var w validator.Warning
+	c.Assert(errors.As(err.(validator.ErrorMap)["Email"][0], &w), Equals, true)
+	c.Assert(w.Err, Equals, validator.ErrRegexp)
+
+	ok, err = v.ValidateWithBudget(dirty, 1)
+	c.Assert(ok, Equals, false)
+	c.Assert(err, NotNil)
+
+	// hard rules are never tolerated
+	ok, err = v.ValidateWithBudget(record{Email: "a@b"}, 10)
+	c.Assert(ok, Equals, false)
+	c.Assert(err.(validator.ErrorMap)["ID"], HasError, validator.ErrZeroValue)
+
+	r := v.Check(dirty)
+	c.Assert(r.Ok(), Equals, true)
+	c.Assert(r.Warnings(), HasLen, 2)
+}
@@ -20,6 +20,7 @@ import (
 	"reflect"
 	"regexp"
 	"strconv"
+	"time"
 	"unicode/utf8"
 )
 
@@ -59,7 +60,8 @@ func nonzero(v interface{}, param string) error {
 
 // length tests whether a variable's length is equal to a given
 // value. For strings it tests the number of characters whereas
-// for maps and slices it tests the number of items.
+// for maps and slices it tests the number of items. Sizes such as
+// "2MiB" are compared with the number of bytes of strings and []byte.
 func length(v interface{}, param string) error {
 	st := reflect.ValueOf(v)
 	var valid bool
@@ -70,20 +72,14 @@ func length(v interface{}, param string) error {
 		st = st.Elem()
 	}
 	switch st.Kind() {
-	case reflect.String:
-		p, err := asInt(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		valid = int64(utf8.RuneCountInString(st.String())) == p
-	case reflect.Slice, reflect.Map, reflect.Array:
-		p, err := asInt(param)
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		n, p, err := lengthParam(st, param)
 		if err != nil {
 			return ErrBadParameter
 		}
-		valid = int64(st.Len()) == p
+		valid = n == p
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		p, err := asInt(param)
+		p, err := intParam(st, param)
 		if err != nil {
 			return ErrBadParameter
 		}
@@ -112,7 +108,8 @@ func length(v interface{}, param string) error {
 // min tests whether a variable value is larger or equal to a given
 // number. For number types, it's a simple lesser-than test; for
 // strings it tests the number of characters whereas for maps
-// and slices it tests the number of items.
+// and slices it tests the number of items. Sizes such as "64KB"
+// are compared with the number of bytes of strings and []byte.
 func min(v interface{}, param string) error {
 	st := reflect.ValueOf(v)
 	invalid := false
@@ -123,20 +120,14 @@ func min(v interface{}, param string) error {
 		st = st.Elem()
 	}
 	switch st.Kind() {
-	case reflect.String:
-		p, err := asInt(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		invalid = int64(utf8.RuneCountInString(st.String())) < p
-	case reflect.Slice, reflect.Map, reflect.Array:
-		p, err := asInt(param)
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		n, p, err := lengthParam(st, param)
 		if err != nil {
 			return ErrBadParameter
 		}
-		invalid = int64(st.Len()) < p
+		invalid = n < p
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		p, err := asInt(param)
+		p, err := intParam(st, param)
 		if err != nil {
 			return ErrBadParameter
 		}
@@ -165,7 +156,8 @@ func min(v interface{}, param string) error {
 // max tests whether a variable value is lesser than a given
 // value. For numbers, it's a simple lesser-than test; for
 // strings it tests the number of characters whereas for maps
-// and slices it tests the number of items.
+// and slices it tests the number of items. Sizes such as "64KB"
+// are compared with the number of bytes of strings and []byte.
 func max(v interface{}, param string) error {
 	st := reflect.ValueOf(v)
 	var invalid bool
@@ -176,20 +168,14 @@ func max(v interface{}, param string) error {
 		st = st.Elem()
 	}
 	switch st.Kind() {
-	case reflect.String:
-		p, err := asInt(param)
-		if err != nil {
-			return ErrBadParameter
-		}
-		invalid = int64(utf8.RuneCountInString(st.String())) > p
-	case reflect.Slice, reflect.Map, reflect.Array:
-		p, err := asInt(param)
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+		n, p, err := lengthParam(st, param)
 		if err != nil {
 			return ErrBadParameter
 		}
-		invalid = int64(st.Len()) > p
+		invalid = n > p
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		p, err := asInt(param)
+		p, err := intParam(st, param)
 		if err != nil {
 			return ErrBadParameter
 		}
@@ -250,6 +236,17 @@ func asInt(param string) (int64, error) {
 	return i, nil
 }
 
+// intParam returns the parameter of a constraint on the integer st, which
+// is a duration such as 1h30m for time.Duration values.
+func intParam(st reflect.Value, param string) (int64, error) {
+	if st.Type() == durationType {
+		if d, err := time.ParseDuration(param); err == nil {
+			return int64(d), nil
+		}
+	}
+	return asInt(param)
+}
+
 // asUint returns the parameter as a uint64
 // or panics if it can't convert
 func asUint(param string) (uint64, error) {
@@ -0,0 +1,118 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+// Bundle is a group of validation functions, and the aliases they use,
+// registered on a validator at once. Third parties may publish their own.
+type Bundle interface {
+	Register(mv *Validator) error
+}
+
+// BundleFunc adapts a function to the Bundle interface.
+type BundleFunc func(mv *Validator) error
+
+// Register implements the Bundle interface.
+func (f BundleFunc) Register(mv *Validator) error {
+	return f(mv)
+}
+
+// funcBundle is a builtin Bundle of validation functions.
+type funcBundle struct {
+	funcs      map[string]ValidationFunc
+	fieldFuncs map[string]FieldValidationFunc
+}
+
+// Register implements the Bundle interface.
+func (b funcBundle) Register(mv *Validator) error {
+	for name, fn := range b.funcs {
+		if err := mv.SetValidationFunc(name, fn); err != nil {
+			return err
+		}
+	}
+	for name, fn := range b.fieldFuncs {
+		if err := mv.SetFieldValidationFunc(name, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The builtin bundles, registered by NewValidator but not by
+// NewCoreValidator.
+var (
+	// Network holds the validation functions of HTTP headers and
+	// hosts: acceptlanguage, hostallow, idempotencykey, mediatype,
+	// traceparent and webhooksig.
+	Network Bundle = funcBundle{funcs: map[string]ValidationFunc{
+		"acceptlanguage": acceptlanguage,
+		"hostallow":      hostallow,
+		"idempotencykey": idempotencykey,
+		"mediatype":      mediatype,
+		"traceparent":    traceparent,
+		"webhooksig":     webhooksig,
+	}}
+	// Payments holds the validation functions of amounts and numeric
+	// strings: amount, floatstr and intstr.
+	Payments Bundle = funcBundle{fieldFuncs: map[string]FieldValidationFunc{
+		"amount":   amount,
+		"floatstr": floatstr,
+		"intstr":   intstr,
+	}}
+	// Geo holds the validation functions of coordinates and tracks:
+	// geoprecision, incountry, maxdistance and track.
+	Geo Bundle = funcBundle{
+		funcs: map[string]ValidationFunc{
+			"geoprecision": geoprecision,
+			"track":        track,
+		},
+		fieldFuncs: map[string]FieldValidationFunc{
+			"incountry":   incountry,
+			"maxdistance": maxdistance,
+		},
+	}
+	// Strings holds the validation functions of text and secrets:
+	// base64url, minentropy, notsimilar and printable.
+	Strings Bundle = funcBundle{
+		funcs: map[string]ValidationFunc{
+			"base64url":  base64url,
+			"minentropy": minentropy,
+			"printable":  printable,
+		},
+		fieldFuncs: map[string]FieldValidationFunc{
+			"notsimilar": notsimilar,
+		},
+	}
+)
+
+// Register calls the Register method on the default validator.
+func Register(bundles ...Bundle) error {
+	return setDefault(func() error {
+		return defaultValidator.Register(bundles...)
+	})
+}
+
+// Register registers the validation functions of bundles on mv, in
+// order, so that later bundles win over earlier ones. It stops at the
+// first error.
+func (mv *Validator) Register(bundles ...Bundle) error {
+	for _, b := range bundles {
+		if err := b.Register(mv); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"errors"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestBundles(c *C) {
+	v := validator.NewCoreValidator()
+	c.Assert(v.Valid("ab", "min=1"), IsNil)
+	c.Assert(v.Valid("1.5", "floatstr"), Equals, validator.ErrUnknownTag)
+
+	c.Assert(v.Register(validator.Payments, validator.Strings), IsNil)
+	c.Assert(v.Valid("1.5", "floatstr"), IsNil)
+	c.Assert(v.Valid("a\x00", "printable"), NotNil)
+	c.Assert(v.Valid("0,0", "geoprecision=2"), Equals, validator.ErrUnknownTag)
+
+	errSKU := errors.New("bad sku")
+	catalog := validator.BundleFunc(func(mv *validator.Validator) error {
+		if err := mv.SetValidationFunc("sku", func(v interface{}, _ string) error {
+			if s, _ := v.(string); len(s) != 8 {
+				return errSKU
+			}
+			return nil
+		}); err != nil {
+			return err
+		}
+		return mv.SetAlias("product", "nonzero,sku")
+	})
+	c.Assert(v.Register(catalog), IsNil)
+	c.Assert(v.Valid("ABC", "product"), HasError, errSKU)
+
+	errBroken := errors.New("broken")
+	broken := validator.BundleFunc(func(*validator.Validator) error { return errBroken })
+	c.Assert(v.Register(broken, validator.Geo), Equals, errBroken)
+	c.Assert(v.Valid("0,0", "geoprecision=2"), Equals, validator.ErrUnknownTag)
+}
@@ -0,0 +1,77 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+)
+
+// ErrCanonical is the error returned when a value differs from the
+// canonical value it is compared to.
+var ErrCanonical = TextErr{errors.New("differs from canonical value")}
+
+var (
+	canonicalsMu sync.RWMutex
+	canonicals   = map[string]interface{}{}
+)
+
+// RegisterCanonical registers value under name so that fields, such as
+// configuration structs that must not drift from an approved baseline,
+// can be compared to it with eq_canonical=name. Pointers are registered
+// as the values they point to.
+func RegisterCanonical[T any](name string, value T) error {
+	if name == "" {
+		return errors.New("name cannot be empty")
+	}
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	if !v.IsValid() || v.Kind() == reflect.Ptr {
+		return errors.New("value cannot be nil")
+	}
+	canonicalsMu.Lock()
+	canonicals[name] = v.Interface()
+	canonicalsMu.Unlock()
+	return nil
+}
+
+// eqCanonical is the builtin validation function that checks whether a
+// value is deeply equal, as told by reflect.DeepEqual, to the canonical
+// value registered under the name given as parameter. Nil values are
+// valid.
+func eqCanonical(v interface{}, param string) error {
+	canonicalsMu.RLock()
+	canonical, ok := canonicals[param]
+	canonicalsMu.RUnlock()
+	if !ok {
+		return ErrBadParameter
+	}
+	st := reflect.ValueOf(v)
+	if !st.IsValid() || (st.Kind() == reflect.Ptr || st.Kind() == reflect.Interface) && st.IsNil() {
+		return nil
+	}
+	if st.Type() != reflect.TypeOf(canonical) {
+		return ErrUnsupported
+	}
+	if !reflect.DeepEqual(v, canonical) {
+		return ErrCanonical
+	}
+	return nil
+}
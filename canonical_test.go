@@ -0,0 +1,55 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type tlsSettings struct {
+	MinVersion string
+	Ciphers    []string
+}
+
+func (ms *MySuite) TestEqCanonical(c *C) {
+	baseline := tlsSettings{MinVersion: "1.2", Ciphers: []string{"TLS_AES_128_GCM_SHA256"}}
+	c.Assert(validator.RegisterCanonical("tlsBaseline", &baseline), IsNil)
+	c.Assert(validator.RegisterCanonical("", baseline), NotNil)
+	c.Assert(validator.RegisterCanonical("nil", (*tlsSettings)(nil)), NotNil)
+
+	type config struct {
+		TLS      tlsSettings  `validate:"eq_canonical=tlsBaseline"`
+		Fallback *tlsSettings `validate:"eq_canonical=tlsBaseline"`
+	}
+	approved := config{TLS: tlsSettings{MinVersion: "1.2", Ciphers: []string{"TLS_AES_128_GCM_SHA256"}}}
+	c.Assert(validator.Validate(approved), IsNil)
+
+	drifted := approved
+	drifted.Fallback = &tlsSettings{MinVersion: "1.0", Ciphers: baseline.Ciphers}
+	errs, ok := validator.Validate(drifted).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Fallback"], HasError, validator.ErrCanonical)
+	c.Assert(errs["TLS"], IsNil)
+
+	// pointers are registered as the values they point to
+	baseline.MinVersion = "1.3"
+	c.Assert(validator.Valid(approved.TLS, "eq_canonical=tlsBaseline"), IsNil)
+
+	c.Assert(validator.Valid("1.2", "eq_canonical=tlsBaseline"), HasError, validator.ErrUnsupported)
+	c.Assert(validator.Valid(approved.TLS, "eq_canonical=nosuchvalue"), HasError, validator.ErrBadParameter)
+}
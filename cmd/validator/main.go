@@ -0,0 +1,260 @@
+// Command validator documents the constraints of validated types
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// Usage:
+//
+//	validator doc [-html] [-tag validate] [-name json] [-custom names] dir [Type...]
+//
+// The doc subcommand reads the Go package in dir and prints a table of
+// the constraints of each of the named struct types, or of every
+// exported struct type with constraints if none is named. Only the
+// builtin validation functions are known; the names of custom ones,
+// separated by commas, are given with -custom.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/validator.v2"
+)
+
+const usage = "usage: validator doc [-html] [-tag validate] [-name json] [-custom names] dir [Type...]"
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run runs the command with the given arguments and returns its exit
+// status.
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] != "doc" {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+	fs := flag.NewFlagSet("doc", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	asHTML := fs.Bool("html", false, "print HTML tables instead of Markdown")
+	tag := fs.String("tag", "validate", "name of the tag holding the constraints")
+	name := fs.String("name", "", "name of the tag holding the names of fields, such as json")
+	custom := fs.String("custom", "", "names of custom validation functions, separated by commas")
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fmt.Fprintln(stderr, usage)
+		return 2
+	}
+
+	mv := validator.NewValidator().WithTag(*tag).WithNameTag(*name)
+	for _, fn := range strings.Split(*custom, ",") {
+		if fn = strings.TrimSpace(fn); fn != "" {
+			if err := mv.SetValidationFunc(fn, func(interface{}, string) error { return nil }); err != nil {
+				fmt.Fprintln(stderr, "validator:", err)
+				return 2
+			}
+		}
+	}
+	docs, err := document(mv, *tag, fs.Arg(0), fs.Args()[1:])
+	if err != nil {
+		fmt.Fprintln(stderr, "validator:", err)
+		return 1
+	}
+	for i, d := range docs {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+		if *asHTML {
+			fmt.Fprint(stdout, d.HTML())
+		} else {
+			fmt.Fprint(stdout, d.Markdown())
+		}
+	}
+	return 0
+}
+
+// document returns the constraints tables of the named struct types of
+// the package in dir, or of all its exported struct types with
+// constraints if names is empty.
+func document(mv *validator.Validator, tag, dir string, names []string) ([]validator.TypeDoc, error) {
+	structs, order, err := parseStructs(dir)
+	if err != nil {
+		return nil, err
+	}
+	all := len(names) == 0
+	if all {
+		names = order
+	}
+	var docs []validator.TypeDoc
+	for _, name := range names {
+		st, ok := structs[name]
+		if !ok {
+			return nil, fmt.Errorf("no struct type %s in %s", name, dir)
+		}
+		d := validator.TypeDoc{Name: name}
+		err := describe(mv, tag, structs, st, "", map[*ast.StructType]bool{}, &d.Fields)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", name, err)
+		}
+		if all && (!ast.IsExported(name) || len(d.Fields) == 0) {
+			continue
+		}
+		docs = append(docs, d)
+	}
+	return docs, nil
+}
+
+// parseStructs returns the struct types declared in the package in dir,
+// by name, and their names in the order of the source.
+func parseStructs(dir string) (map[string]*ast.StructType, []string, error) {
+	fset := token.NewFileSet()
+	noTests := func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}
+	pkgs, err := parser.ParseDir(fset, dir, noTests, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(pkgs) == 0 {
+		return nil, nil, errors.New("no Go files in " + dir)
+	}
+	structs := map[string]*ast.StructType{}
+	var order []string
+	for _, pkg := range pkgs {
+		files := make([]string, 0, len(pkg.Files))
+		for name := range pkg.Files {
+			files = append(files, name)
+		}
+		sort.Strings(files)
+		for _, name := range files {
+			ast.Inspect(pkg.Files[name], func(n ast.Node) bool {
+				ts, ok := n.(*ast.TypeSpec)
+				if !ok {
+					return true
+				}
+				if st, ok := ts.Type.(*ast.StructType); ok {
+					structs[ts.Name.Name] = st
+					order = append(order, ts.Name.Name)
+				}
+				return false
+			})
+		}
+	}
+	return structs, order, nil
+}
+
+// describe appends the descriptions of the fields of st, keyed under
+// prefix, to ds. Fields of struct types declared in the package are
+// described after the field holding them.
+func describe(mv *validator.Validator, tag string, structs map[string]*ast.StructType, st *ast.StructType, prefix string, seen map[*ast.StructType]bool, ds *[]validator.FieldDescription) error {
+	if seen[st] {
+		return nil
+	}
+	seen[st] = true
+	defer delete(seen, st)
+
+	for _, f := range st.Fields.List {
+		var stag reflect.StructTag
+		if f.Tag != nil {
+			s, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return err
+			}
+			stag = reflect.StructTag(s)
+		}
+		if stag.Get(tag) == "-" {
+			continue
+		}
+		typ := types.ExprString(f.Type)
+		var names []string
+		if len(f.Names) == 0 {
+			names = []string{embeddedName(f.Type)}
+		} else {
+			for _, n := range f.Names {
+				if n.Name != "_" && ast.IsExported(n.Name) {
+					names = append(names, n.Name)
+				}
+			}
+		}
+		for _, n := range names {
+			d, err := mv.DescribeTag(n, typ, stag)
+			if err != nil {
+				return fmt.Errorf("field %s: %v", n, err)
+			}
+			key := joinKey(prefix, d.Name)
+			if len(d.Rules) > 0 {
+				d.Name = key
+				*ds = append(*ds, d)
+			}
+			if nested := structType(structs, f.Type); nested != nil {
+				if err := describe(mv, tag, structs, nested, key, seen, ds); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// embeddedName returns the field name of an embedded field of type t.
+func embeddedName(t ast.Expr) string {
+	switch t := t.(type) {
+	case *ast.StarExpr:
+		return embeddedName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.Ident:
+		return t.Name
+	}
+	return ""
+}
+
+// structType returns the struct type of a field of type t, if it is a
+// struct literal or a struct type declared in the package, or a pointer
+// to one.
+func structType(structs map[string]*ast.StructType, t ast.Expr) *ast.StructType {
+	switch t := t.(type) {
+	case *ast.StarExpr:
+		return structType(structs, t.X)
+	case *ast.StructType:
+		return t
+	case *ast.Ident:
+		return structs[t.Name]
+	}
+	return nil
+}
+
+// joinKey joins the key of a field to the key of the struct holding it.
+func joinKey(prefix, key string) string {
+	if prefix == "" {
+		return key
+	}
+	if key == "" {
+		return prefix
+	}
+	return prefix + "." + key
+}
@@ -0,0 +1,66 @@
+// Command validator documents the constraints of validated types
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+func (ms *MySuite) TestDoc(c *C) {
+	var stdout, stderr bytes.Buffer
+	status := run([]string{"doc", "-name", "json", "-custom", "sku", "testdata/account"}, &stdout, &stderr)
+	c.Assert(stderr.String(), Equals, "")
+	c.Assert(status, Equals, 0)
+	c.Assert(stdout.String(), Equals, "### Address\n\n"+
+		"| Field | Type | Rules | Errors |\n"+
+		"|-------|------|-------|--------|\n"+
+		"| city | string | nonzero | zero value |\n"+
+		"\n"+
+		"### Account\n\n"+
+		"| Field | Type | Rules | Errors |\n"+
+		"|-------|------|-------|--------|\n"+
+		"| name | string | nonzero, max=40 | zero value; greater than max |\n"+
+		"| code | string | sku |  |\n"+
+		"| address.city | string | nonzero | zero value |\n")
+
+	stdout.Reset()
+	status = run([]string{"doc", "-html", "-custom", "sku", "testdata/account", "Address"}, &stdout, &stderr)
+	c.Assert(status, Equals, 0)
+	c.Assert(stdout.String(), Matches, `(?s)<table>\n<caption>Address</caption>\n.*<td>City</td>.*`)
+
+	status = run([]string{"doc", "testdata/account"}, &stdout, &stderr)
+	c.Assert(status, Equals, 1)
+	c.Assert(stderr.String(), Matches, "validator: Account: field Code: unknown tag\n")
+
+	stderr.Reset()
+	status = run([]string{"doc", "-custom", "sku", "testdata/account", "Missing"}, &stdout, &stderr)
+	c.Assert(status, Equals, 1)
+	c.Assert(stderr.String(), Equals, "validator: no struct type Missing in testdata/account\n")
+
+	c.Assert(run(nil, &stdout, &stderr), Equals, 2)
+}
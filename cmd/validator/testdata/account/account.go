@@ -0,0 +1,17 @@
+package account
+
+type Address struct {
+	City string `json:"city" validate:"nonzero"`
+}
+
+type Account struct {
+	Name    string   `json:"name" validate:"nonzero,max=40"`
+	Code    string   `json:"code" validate:"sku"`
+	Address *Address `json:"address"`
+	Ignored string   `validate:"-"`
+	secret  string   `validate:"nonzero"`
+}
+
+type plain struct {
+	A int
+}
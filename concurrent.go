@@ -0,0 +1,101 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+)
+
+// Concurrent returns an Option validating the fields using the rules
+// called names, such as field validation functions calling remote
+// services with the context of the validation, concurrently with the
+// other fields of their struct. The validation of a struct then takes
+// as long as its slowest such field rather than the sum of them. Errors
+// are reported under the same keys and in the same order as otherwise.
+//
+// Validation functions, flag and limit providers and middleware must be
+// safe for concurrent use. With FailFast, the fields being validated
+// concurrently are all validated before the validation stops.
+func Concurrent(names ...string) Option {
+	return func(mv *Validator) {
+		// the set is shared by validators derived with WithOptions
+		concurrent := make(map[string]bool, len(mv.concurrent)+len(names))
+		for name := range mv.concurrent {
+			concurrent[name] = true
+		}
+		for _, name := range names {
+			concurrent[name] = true
+		}
+		mv.concurrent = concurrent
+	}
+}
+
+// pendingField is a field being validated concurrently with the other
+// fields of its struct.
+type pendingField struct {
+	name string
+	done chan struct{}
+	err  error
+}
+
+// concurrently reports whether the rules of tag include rules to run
+// concurrently.
+func (mv *Validator) concurrently(tag string) bool {
+	if len(mv.concurrent) == 0 {
+		return false
+	}
+	tags, err := mv.parseTags(tag)
+	if err != nil {
+		return false
+	}
+	for _, t := range tags {
+		if mv.concurrent[t.Name] {
+			return true
+		}
+	}
+	return false
+}
+
+// startField starts validating v, the value of the field f whose errors
+// are keyed by name, against tag.
+func (mv *Validator) startField(v reflect.Value, f Field, tag, name string) *pendingField {
+	p := &pendingField{name: name, done: make(chan struct{})}
+	go func() {
+		defer close(p.done)
+		p.err = mv.validValue(v, f, tag)
+	}()
+	return p
+}
+
+// awaitFields waits for the fields of the struct sv being validated
+// concurrently and adds their errors to m, as validateField does.
+func (mv *Validator) awaitFields(w *walk, sv reflect.Value, m ErrorMap) {
+	for _, p := range w.pending {
+		<-p.done
+		err := mv.moveErrorMaps(sv, p.err, m)
+		var errs ErrorArray
+		if errarr, ok := err.(ErrorArray); ok {
+			errs = errarr
+		} else if err != nil {
+			errs = ErrorArray{err}
+		}
+		if len(errs) > 0 {
+			m[p.name] = append(errs, m[p.name]...)
+		}
+	}
+	w.pending = nil
+}
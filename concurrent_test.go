@@ -0,0 +1,73 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+var errRemote = errors.New("rejected by remote check")
+
+func (ms *MySuite) TestConcurrent(c *C) {
+	// each check waits for the other, so that they only pass when run
+	// concurrently
+	var arrived sync.WaitGroup
+	arrived.Add(2)
+	remote := func(v interface{}, f validator.Field, param string) error {
+		arrived.Done()
+		done := make(chan struct{})
+		go func() {
+			arrived.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			return validator.ErrInvalid
+		}
+		if v.(string) == "bad" {
+			return errRemote
+		}
+		return nil
+	}
+	mv := validator.NewValidator().WithOptions(validator.Concurrent("fraud", "sanctions"))
+	c.Assert(mv.SetFieldValidationFunc("fraud", remote), IsNil)
+	c.Assert(mv.SetFieldValidationFunc("sanctions", remote), IsNil)
+	mv.SetNameTag("json")
+
+	type party struct {
+		Country string `json:"country" validate:"len=2"`
+	}
+	type payment struct {
+		Card  string `json:"card" validate:"min=4,fraud"`
+		Payee string `json:"payee" validate:"nonzero,sanctions"`
+		Party party  `json:"party"`
+		Memo  string `json:"memo" validate:"max=3"`
+	}
+	err := mv.Validate(payment{Card: "bad", Payee: "ok", Memo: "long"})
+	c.Assert(err, DeepEquals, validator.ErrorMap{
+		"card":          {validator.ErrMin, errRemote},
+		"party.country": {validator.ErrLen},
+		"memo":          {validator.ErrMax},
+	})
+
+}
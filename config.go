@@ -0,0 +1,261 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrConfigured is the error returned by Configure once the default
+// validator has been used.
+var ErrConfigured = errors.New("validator: default validator already in use")
+
+// Option configures a Validator. Any function changing a Validator,
+// such as one calling its Set methods, can be used as an Option.
+type Option func(mv *Validator)
+
+// Tag returns an Option setting the tag rules are read from, as SetTag.
+func Tag(tag string) Option {
+	return func(mv *Validator) {
+		mv.SetTag(tag)
+	}
+}
+
+// NameTag returns an Option setting the tag errors are keyed by, as
+// SetNameTag.
+func NameTag(tag string) Option {
+	return func(mv *Validator) {
+		mv.SetNameTag(tag)
+	}
+}
+
+// NameFunc returns an Option keying errors by the names fn gives fields,
+// such as names derived from several tags, rather than by those of the
+// tag set with SetNameTag.
+func NameFunc(fn func(sf reflect.StructField) string) Option {
+	return func(mv *Validator) {
+		mv.nameFunc = fn
+		// the versions of rules are cached by name tag
+		mv.versions = new(sync.Map)
+	}
+}
+
+// TranslateFunc returns the message shown to users for the error fe
+// found by a validation in the given locale, or the empty string to
+// keep the message of the error.
+type TranslateFunc func(locale string, fe FieldError) string
+
+// Translator returns an Option translating the messages of the errors
+// returned by Check with fn, in the locale of the context of the
+// validation or the one set with Locale.
+func Translator(fn TranslateFunc) Option {
+	return func(mv *Validator) {
+		mv.translate = fn
+	}
+}
+
+var (
+	defaultMu     sync.Mutex
+	defaultLocked uint32
+	// defaultConfigured is set once Configure has set up the default
+	// validator, whose package level setters are then locked as well.
+	defaultConfigured uint32
+)
+
+func init() {
+	defaultValidator.isDefault = true
+}
+
+// Configure applies opts to the default validator used by the package
+// level functions, so that programs need not pass a validator of their
+// own around. It may be called, such as from main, until the default
+// validator first validates a value, after which its configuration is
+// locked and Configure returns ErrConfigured. It is safe to call
+// Configure concurrently with validations.
+//
+// Once Configure has been called, the lock also covers the package level
+// functions changing the default validator, such as SetTag, SetAlias,
+// SetValidationFunc and Use: those returning an error return
+// ErrConfigured, and the others do nothing. Reload, meant to change rules
+// while a program runs, is not locked. Programs that do not call
+// Configure can still change the default validator with them at any
+// time, as they always could.
+func Configure(opts ...Option) error {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if atomic.LoadUint32(&defaultLocked) != 0 {
+		return ErrConfigured
+	}
+	for _, opt := range opts {
+		opt(defaultValidator)
+	}
+	atomic.StoreUint32(&defaultConfigured, 1)
+	return nil
+}
+
+// setDefault calls set, which changes the default validator, unless the
+// configuration set up with Configure is locked.
+func setDefault(set func() error) error {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	if atomic.LoadUint32(&defaultConfigured) != 0 && atomic.LoadUint32(&defaultLocked) != 0 {
+		return ErrConfigured
+	}
+	return set()
+}
+
+// lockDefault locks the configuration of the default validator when mv
+// is the default validator.
+func (mv *Validator) lockDefault() {
+	if !mv.isDefault || atomic.LoadUint32(&defaultLocked) != 0 {
+		return
+	}
+	defaultMu.Lock()
+	atomic.StoreUint32(&defaultLocked, 1)
+	defaultMu.Unlock()
+}
+
+// Locale returns an Option reading numbers in the format of the given
+// locale when the context of a validation has none, as WithLocale.
+func Locale(locale string) Option {
+	return func(mv *Validator) {
+		mv.locale = locale
+	}
+}
+
+// FailFast returns an Option stopping validations at the first field,
+// and the first rule of the field, found to be invalid.
+func FailFast(failFast bool) Option {
+	return func(mv *Validator) {
+		mv.failFast = failFast
+	}
+}
+
+// WithOptions calls the WithOptions method on the default validator.
+func WithOptions(opts ...Option) *Validator {
+	return defaultValidator.WithOptions(opts...)
+}
+
+// WithOptions returns a validator applying opts, such as Tag to select
+// the rules of a scenario or Locale for the user of a request, on top of
+// mv. Unlike WithTag it does not copy the validation functions and
+// aliases of mv, which both share until either changes them, so that it
+// is cheap enough to call for every request.
+func (mv *Validator) WithOptions(opts ...Option) *Validator {
+	mv.shared.Store(true)
+	v := *mv
+	v.isDefault = false
+	for _, opt := range opts {
+		opt(&v)
+	}
+	return &v
+}
+
+// own gives mv functions, aliases and middleware of its own before they
+// are changed, if it shares them with the validators derived from it
+// with WithOptions or with the one it was derived from. It is called by every
+// method changing them, and so marks the rules of validators as changed.
+func (mv *Validator) own() {
+	rulesChanged()
+	if !mv.shared.Load() {
+		return
+	}
+	c := mv.copy()
+	mv.validationFuncs = c.validationFuncs
+	mv.fieldValidationFuncs = c.fieldValidationFuncs
+	mv.structValidationFuncs = c.structValidationFuncs
+	mv.traverseFuncs = c.traverseFuncs
+	mv.wrappers = c.wrappers
+	mv.fieldRules = c.fieldRules
+	mv.labels = c.labels
+	mv.aliases = c.aliases
+	mv.custom = c.custom
+	mv.versions = c.versions
+	mv.compiled = c.compiled
+	mv.middleware = c.middleware
+	mv.shared = new(atomic.Bool)
+}
+
+// context returns ctx with the locale of mv if ctx has none.
+func (mv *Validator) context(ctx context.Context) context.Context {
+	if mv.locale == "" || ctx.Value(localeKey{}) != nil {
+		return ctx
+	}
+	return WithLocale(ctx, mv.locale)
+}
+
+// Merge returns a new validator combining the validation functions,
+// aliases, struct validation functions, traverse functions, wrappers,
+// field rules and labels of a and b, such as those registered by a
+// library and by the application using it. Where both set the same name
+// or type, b wins, except for the builtin functions and aliases b has
+// not set since it was created, which do not undo the changes of a.
+// Functions and aliases b has removed are removed from the result too. A
+// name set as a function in one and as an alias in the other resolves
+// to the function. The tags, options and providers are those of a, and
+// the middleware that of a followed by that of b.
+func Merge(a, b *Validator) *Validator {
+	v := a.copy()
+	for name := range b.custom {
+		_, vf := b.validationFuncs[name]
+		_, fvf := b.fieldValidationFuncs[name]
+		_, alias := b.aliases[name]
+		if !vf && !fvf && !alias {
+			v.SetValidationFunc(name, nil)
+			v.SetAlias(name, "")
+			continue
+		}
+		if fn, ok := b.validationFuncs[name]; ok {
+			v.SetValidationFunc(name, fn)
+		}
+		if fn, ok := b.fieldValidationFuncs[name]; ok {
+			v.SetFieldValidationFunc(name, fn)
+		}
+		if tags, ok := b.aliases[name]; ok {
+			v.SetAlias(name, tags)
+		}
+	}
+	for t, fn := range b.structValidationFuncs {
+		v.structValidationFuncs[t] = fn
+	}
+	for t, fn := range b.traverseFuncs {
+		v.traverseFuncs[t] = fn
+	}
+	for key, fn := range b.wrappers {
+		v.wrappers[key] = fn
+	}
+	for t, fields := range b.fieldRules {
+		merged := map[string]string{}
+		for name, rules := range v.fieldRules[t] {
+			merged[name] = rules
+		}
+		for name, rules := range fields {
+			merged[name] = rules
+		}
+		v.fieldRules[t] = merged
+	}
+	for name, label := range b.labels {
+		v.labels[name] = label
+	}
+	v.middleware = append(v.middleware, b.middleware...)
+	rulesChanged()
+	return v
+}
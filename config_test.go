@@ -0,0 +1,227 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestConfigure(c *C) {
+	type T struct {
+		A string `valid:"nonzero" json:"a"`
+	}
+	v := validator.NewValidator()
+	for _, opt := range []validator.Option{validator.Tag("valid"), validator.NameTag("json")} {
+		opt(v)
+	}
+	err := v.Validate(T{})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["a"], HasError, validator.ErrZeroValue)
+
+	// the default validator is locked by its first use
+	c.Assert(validator.Validate(T{}), IsNil)
+	err = validator.Configure(validator.Tag("valid"))
+	c.Assert(err, Equals, validator.ErrConfigured)
+	c.Assert(validator.Validate(T{}), IsNil)
+}
+
+func (ms *MySuite) TestConfigureLocksSetters(c *C) {
+	// the default validator of this process has already been used
+	if os.Getenv("VALIDATOR_CONFIGURE_TEST") == "" {
+		cmd := exec.Command(os.Args[0], "-test.run", "^Test$", "-check.f", "TestConfigureLocksSetters$")
+		cmd.Env = append(os.Environ(), "VALIDATOR_CONFIGURE_TEST=1")
+		out, err := cmd.CombinedOutput()
+		c.Assert(err, IsNil, Commentf("%s", out))
+		return
+	}
+	type T struct {
+		A string `valid:"nonzero" json:"a"`
+	}
+	c.Assert(validator.SetAlias("code", "len=3"), IsNil)
+	c.Assert(validator.Configure(validator.Tag("valid")), IsNil)
+	c.Assert(validator.SetValidationFunc("even", func(interface{}, string) error { return nil }), IsNil)
+	c.Assert(validator.Validate(T{}), NotNil)
+
+	c.Assert(validator.SetValidationFunc("odd", func(interface{}, string) error { return nil }), Equals, validator.ErrConfigured)
+	c.Assert(validator.SetAlias("code", "len=4"), Equals, validator.ErrConfigured)
+	validator.SetTag("validate")
+	c.Assert(validator.Validate(T{}), NotNil)
+	c.Assert(validator.Valid("abc", "code,even"), IsNil)
+	c.Assert(validator.Valid("abc", "odd"), Equals, validator.ErrUnknownTag)
+	// rules can still be reloaded
+	c.Assert(validator.Reload(strings.NewReader(`{}`)), IsNil)
+}
+
+func (ms *MySuite) TestTranslator(c *C) {
+	type T struct {
+		Name string `json:"name" label:"Full name" validate:"nonzero"`
+	}
+	v := validator.NewValidator().WithOptions(validator.Locale("fr"), validator.Translator(func(locale string, fe validator.FieldError) string {
+		if locale == "fr" && errors.Is(fe.Err, validator.ErrZeroValue) {
+			return fe.Label + " : obligatoire"
+		}
+		return ""
+	}))
+	errs := v.Check(T{}).Errors()
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs[0].Error(), Equals, "Full name : obligatoire")
+	errs = v.CheckContext(validator.WithLocale(context.Background(), "en"), T{}).Errors()
+	c.Assert(errs[0].Message, Equals, "")
+	c.Assert(errs[0].Error(), Equals, "Full name: zero value")
+}
+
+func (ms *MySuite) TestNameFunc(c *C) {
+	type T struct {
+		Name string `json:"name" form:"full_name" validate:"nonzero"`
+	}
+	v := validator.NewValidator().WithOptions(validator.NameFunc(func(sf reflect.StructField) string {
+		if name := sf.Tag.Get("form"); name != "" {
+			return name
+		}
+		return sf.Name
+	}))
+	c.Assert(v.Validate(T{}).(validator.ErrorMap)["full_name"], HasError, validator.ErrZeroValue)
+}
+
+func (ms *MySuite) TestWithOptions(c *C) {
+	type T struct {
+		A string `validate:"nonzero,min=2" create:"nonzero"`
+		B string `validate:"floatstr"`
+		C int    `validate:"min=1"`
+	}
+	v := validator.NewValidator()
+	err := v.Validate(T{B: "1,5"})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap), HasLen, 3)
+
+	fast := v.WithOptions(validator.FailFast(true), validator.Locale("de"))
+	err = fast.Validate(T{B: "1,5"})
+	c.Assert(err, DeepEquals, validator.ErrorMap{"A": {validator.ErrZeroValue}})
+	err = fast.Validate(T{A: "ab", B: "1,5"})
+	c.Assert(err, DeepEquals, validator.ErrorMap{"C": {validator.ErrMin}})
+	err = fast.ValidateContext(validator.WithLocale(context.Background(), "en"), T{A: "ab", B: "1,5", C: 1})
+	c.Assert(err, DeepEquals, validator.ErrorMap{"B": {validator.ErrNumber}})
+
+	err = v.WithOptions(validator.Tag("create")).Validate(T{A: "a"})
+	c.Assert(err, IsNil)
+
+	// functions are shared until changed
+	derived := v.WithOptions()
+	c.Assert(derived.SetValidationFunc("min", func(interface{}, string) error { return nil }), IsNil)
+	c.Assert(derived.Valid("", "min=1"), IsNil)
+	c.Assert(v.Valid("", "min=1"), HasError, validator.ErrMin)
+	c.Assert(v.WithOptions().Valid("", "min=1"), HasError, validator.ErrMin)
+}
+
+func (ms *MySuite) TestWithOptionsParentChanges(c *C) {
+	v := validator.NewValidator()
+	derived := v.WithOptions()
+	done := make(chan bool)
+	go func() {
+		for i := 0; i < 100; i++ {
+			derived.Valid("a", "min=1,short")
+		}
+		done <- true
+	}()
+	// changes to the validator derived from are not seen by derived
+	for i := 0; i < 100; i++ {
+		c.Assert(v.SetValidationFunc("short", func(interface{}, string) error { return validator.ErrMax }), IsNil)
+		c.Assert(v.SetAlias("tiny", "max=1"), IsNil)
+	}
+	<-done
+	c.Assert(v.Valid("a", "short"), HasError, validator.ErrMax)
+	c.Assert(derived.Valid("a", "short"), Equals, validator.ErrUnknownTag)
+	c.Assert(derived.Valid("a", "tiny"), Equals, validator.ErrUnknownTag)
+}
+
+func (ms *MySuite) TestMerge(c *C) {
+	errLib := errors.New("lib")
+	errApp := errors.New("app")
+	lib := validator.NewValidator()
+	lib.SetValidationFunc("sku", func(interface{}, string) error { return errLib })
+	lib.SetValidationFunc("min", func(interface{}, string) error { return errLib })
+	lib.SetAlias("code", "len=3")
+	lib.SetTag("lib")
+
+	app := validator.NewValidator()
+	app.SetValidationFunc("sku", func(interface{}, string) error { return errApp })
+	app.SetFieldValidationFunc("code", func(interface{}, validator.Field, string) error { return errApp })
+	app.SetAlias("name", "nonzero,max=10")
+	type order struct{ ID string }
+	app.SetStructValidationFunc(order{}, func(interface{}) error { return errApp })
+
+	v := validator.Merge(lib, app)
+	c.Assert(v.Valid("x", "sku"), HasError, errApp)
+	// builtins app left unchanged do not undo the changes of lib
+	c.Assert(v.Valid("x", "min=0"), HasError, errLib)
+	// functions take precedence over aliases
+	c.Assert(v.Valid("abc", "code"), HasError, errApp)
+	c.Assert(v.Valid("", "name"), HasError, validator.ErrZeroValue)
+	c.Assert(v.Validate(order{}), DeepEquals, validator.ErrorMap{"": {errApp}})
+
+	type T struct {
+		A string `lib:"sku"`
+	}
+	c.Assert(v.Validate(T{}).(validator.ErrorMap)["A"], HasError, errApp)
+
+	// a and b are unchanged
+	c.Assert(lib.Valid("x", "sku"), HasError, errLib)
+	c.Assert(app.Valid("x", "min=0"), IsNil)
+}
+
+func (ms *MySuite) TestMergeClosures(c *C) {
+	rejecting := func(err error) validator.ValidationFunc {
+		return func(interface{}, string) error { return err }
+	}
+	errLib := errors.New("lib")
+	errApp := errors.New("app")
+	lib := validator.NewValidator()
+	lib.SetValidationFunc("sku", rejecting(errLib))
+	app := validator.NewValidator()
+	app.SetValidationFunc("sku", rejecting(errApp))
+	// builtins set again by b are taken from b
+	app.SetValidationFunc("min", rejecting(errApp))
+
+	v := validator.Merge(lib, app)
+	c.Assert(v.Valid("x", "sku"), HasError, errApp)
+	c.Assert(v.Valid("x", "min=0"), HasError, errApp)
+	c.Assert(validator.Merge(v, validator.NewValidator()).Valid("x", "sku"), HasError, errApp)
+}
+
+func (ms *MySuite) TestMergeRemoved(c *C) {
+	lib := validator.NewValidator()
+	lib.SetValidationFunc("sku", func(interface{}, string) error { return nil })
+	lib.SetAlias("code", "len=3")
+	app := validator.NewValidator()
+	app.SetValidationFunc("sku", nil)
+	app.SetAlias("code", "")
+	app.SetValidationFunc("nonzero", nil)
+
+	v := validator.Merge(lib, app)
+	c.Assert(v.Valid("x", "sku"), Equals, validator.ErrUnknownTag)
+	c.Assert(v.Valid("x", "code"), Equals, validator.ErrUnknownTag)
+	c.Assert(v.Valid("", "nonzero"), Equals, validator.ErrUnknownTag)
+	c.Assert(lib.Valid("x", "sku"), IsNil)
+}
@@ -0,0 +1,70 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"fmt"
+)
+
+// Values holds request-scoped data, such as the country or plan of the
+// authenticated user, that validation functions read to parameterize
+// their checks.
+type Values map[string]interface{}
+
+type valuesKey struct{}
+
+// WithValues returns a copy of ctx carrying vals, added to the values
+// already set on ctx, so that field validation functions passed the
+// context by ValidateContext or ValidContext can read them with
+// FromContext or Field.Values instead of globals.
+func WithValues(ctx context.Context, vals Values) context.Context {
+	merged := Values{}
+	for k, v := range FromContext(ctx) {
+		merged[k] = v
+	}
+	for k, v := range vals {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, valuesKey{}, merged)
+}
+
+// FromContext returns the values set on ctx with WithValues, or nil if
+// there are none. The values must not be modified.
+func FromContext(ctx context.Context) Values {
+	vals, _ := ctx.Value(valuesKey{}).(Values)
+	return vals
+}
+
+// Values returns the values set with WithValues on the context the field
+// is validated with.
+func (f Field) Values() Values {
+	return FromContext(f.Context())
+}
+
+// String returns the value of key in its string form, or the empty
+// string if there is none.
+func (vals Values) String(key string) string {
+	switch v := vals[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
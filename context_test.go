@@ -0,0 +1,49 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestFromContext(c *C) {
+	c.Assert(validator.FromContext(context.Background()), IsNil)
+
+	ctx := validator.WithValues(context.Background(), validator.Values{"country": "FR", "plan": "free"})
+	ctx = validator.WithValues(ctx, validator.Values{"plan": "pro", "seats": 5})
+	vals := validator.FromContext(ctx)
+	c.Assert(vals, DeepEquals, validator.Values{"country": "FR", "plan": "pro", "seats": 5})
+	c.Assert(vals.String("seats"), Equals, "5")
+	c.Assert(vals.String("missing"), Equals, "")
+
+	mv := validator.NewValidator()
+	c.Assert(mv.SetFieldValidationFunc("shipsto", func(v interface{}, f validator.Field, param string) error {
+		if f.Values().String("country") != v.(string) {
+			return validator.ErrInvalid
+		}
+		return nil
+	}), IsNil)
+	type order struct {
+		Country string `validate:"shipsto"`
+	}
+	c.Assert(mv.ValidateContext(ctx, order{Country: "FR"}), IsNil)
+	c.Assert(mv.ValidateContext(ctx, order{Country: "DE"}), DeepEquals, validator.ErrorMap{"Country": {validator.ErrInvalid}})
+	c.Assert(mv.ValidContext(ctx, "DE", "shipsto"), DeepEquals, validator.ErrorArray{validator.ErrInvalid})
+}
@@ -0,0 +1,50 @@
+// Package countries holds the approximate regions of countries used by
+// the incountry rule of package validator. It is registered by default
+// and left out of builds with the validator_minimal tag, which may
+// import it and register the countries they need.
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package countries
+
+// Box is a region delimited by two parallels and two meridians, as
+// validator.BoundingBox, to which it converts.
+type Box struct {
+	MinLat, MinLong, MaxLat, MaxLong float64
+}
+
+// Bounds holds approximate bounding boxes of the mainland of a few
+// countries, indexed by their ISO 3166-1 alpha-2 code.
+var Bounds = map[string]Box{
+	"AR": {MinLat: -55.25, MinLong: -73.42, MaxLat: -21.83, MaxLong: -53.63},
+	"AU": {MinLat: -43.64, MinLong: 113.34, MaxLat: -10.67, MaxLong: 153.57},
+	"BR": {MinLat: -33.77, MinLong: -73.99, MaxLat: 5.24, MaxLong: -34.73},
+	"CA": {MinLat: 41.68, MinLong: -141.00, MaxLat: 83.23, MaxLong: -52.65},
+	"CN": {MinLat: 18.20, MinLong: 73.68, MaxLat: 53.46, MaxLong: 135.03},
+	"DE": {MinLat: 47.30, MinLong: 5.99, MaxLat: 54.98, MaxLong: 15.02},
+	"EG": {MinLat: 22.00, MinLong: 24.70, MaxLat: 31.59, MaxLong: 36.87},
+	"ES": {MinLat: 35.95, MinLong: -9.39, MaxLat: 43.75, MaxLong: 3.04},
+	"FR": {MinLat: 41.33, MinLong: -5.14, MaxLat: 51.15, MaxLong: 9.56},
+	"GB": {MinLat: 49.96, MinLong: -7.57, MaxLat: 58.64, MaxLong: 1.68},
+	"IN": {MinLat: 7.97, MinLong: 68.18, MaxLat: 35.49, MaxLong: 97.40},
+	"IT": {MinLat: 36.62, MinLong: 6.75, MaxLat: 47.12, MaxLong: 18.48},
+	"JP": {MinLat: 31.03, MinLong: 129.41, MaxLat: 45.55, MaxLong: 145.54},
+	"MX": {MinLat: 14.54, MinLong: -117.13, MaxLat: 32.72, MaxLong: -86.81},
+	"NG": {MinLat: 4.24, MinLong: 2.69, MaxLat: 13.87, MaxLong: 14.58},
+	"NL": {MinLat: 50.80, MinLong: 3.31, MaxLat: 53.51, MaxLong: 7.09},
+	"RU": {MinLat: 41.15, MinLong: 19.64, MaxLat: 81.25, MaxLong: -169.05},
+	"US": {MinLat: 18.91, MinLong: -171.79, MaxLat: 71.36, MaxLong: -66.96},
+	"ZA": {MinLat: -34.82, MinLong: 16.34, MaxLat: -22.09, MaxLong: 32.83},
+}
@@ -0,0 +1,233 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Coverage records the constraints evaluated by a validator, such as
+// across a test suite, so that rules no test exercises can be found.
+// It is safe for concurrent use.
+type Coverage struct {
+	mu    sync.Mutex
+	rules []*RuleCoverage
+	index map[coverageKey]*RuleCoverage
+	types map[reflect.Type]bool
+}
+
+// RuleCoverage is the coverage of one constraint of a field.
+type RuleCoverage struct {
+	// Type is the struct type holding the field, such as "api.Signup".
+	Type string `json:"type"`
+	// Field is the Go name of the field, or "_" for the constraints of
+	// the struct as a whole.
+	Field string `json:"field"`
+	// Rule is the constraint, with aliases expanded.
+	Rule Rule `json:"rule"`
+	// Hits is the number of times the constraint was evaluated.
+	Hits int `json:"hits"`
+	// Failures is the number of times it reported errors.
+	Failures int `json:"failures"`
+}
+
+// coverageKey identifies a constraint of a field.
+type coverageKey struct {
+	typ   reflect.Type
+	field string
+	rule  Rule
+}
+
+// NewCoverage returns an empty Coverage.
+func NewCoverage() *Coverage {
+	return &Coverage{
+		index: map[coverageKey]*RuleCoverage{},
+		types: map[reflect.Type]bool{},
+	}
+}
+
+// SetCoverage sets the coverage of the default validator.
+// See Validator.SetCoverage.
+func SetCoverage(c *Coverage) {
+	setDefault(func() error {
+		defaultValidator.SetCoverage(c)
+		return nil
+	})
+}
+
+// SetCoverage makes mv record the constraints it evaluates in c, or
+// stop recording them if c is nil. It is meant for tests, such as from
+// TestMain, as recording slows validations down.
+//
+// Only the constraints of struct fields are recorded, not those given
+// to Valid or to a RuleSet.
+func (mv *Validator) SetCoverage(c *Coverage) {
+	mv.coverage = c
+}
+
+// Track adds the constraints of the struct types of values, structs or
+// pointers to them, and of the struct types they hold, so that they are
+// reported even if no value of those types is validated. The tags are
+// read as mv, or the default validator if mv is nil, reads them. The
+// constraints of the types of the values a validator checks are added
+// the first time it does.
+func (c *Coverage) Track(mv *Validator, values ...interface{}) error {
+	if mv == nil {
+		mv = defaultValidator
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, v := range values {
+		t := reflect.TypeOf(v)
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			return ErrUnsupported
+		}
+		if err := c.track(mv, t, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// track adds the constraints of the struct type t, held by a value of
+// the named type owner, as given by the tags of mv, and of the struct
+// types of its fields.
+func (c *Coverage) track(mv *Validator, t, owner reflect.Type) error {
+	if c.types[t] {
+		return nil
+	}
+	c.types[t] = true
+	owner = ownerOf(owner, t)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.Anonymous && sf.PkgPath != "" && sf.Name != "_" {
+			continue
+		}
+		tag := mv.fieldTag(owner, t, sf)
+		if tag == "-" {
+			continue
+		}
+		if tag != "" {
+			tags, err := mv.parseTags(tag)
+			if err != nil {
+				return err
+			}
+			for _, tg := range tags {
+				c.rule(t, sf.Name, tg)
+			}
+		}
+		ft := sf.Type
+		for {
+			switch ft.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+				ft = ft.Elem()
+				continue
+			case reflect.Struct:
+				if err := c.track(mv, ft, owner); err != nil {
+					return err
+				}
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// rule returns the coverage of the constraint tg of the field called
+// name of the struct type t, adding it if needed.
+func (c *Coverage) rule(t reflect.Type, name string, tg tag) *RuleCoverage {
+	key := coverageKey{t, name, Rule{Name: tg.Name, Param: tg.Param}}
+	rc, ok := c.index[key]
+	if !ok {
+		rc = &RuleCoverage{Type: t.String(), Field: name, Rule: key.rule}
+		c.index[key] = rc
+		c.rules = append(c.rules, rc)
+	}
+	return rc
+}
+
+// record records the evaluation by mv of the constraint tg of the field
+// f, which reported err.
+func (c *Coverage) record(mv *Validator, f Field, tg tag, err error) {
+	if !f.Parent.IsValid() || f.Parent.Kind() != reflect.Struct {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := f.Parent.Type()
+	// unknown tags are reported by the validation itself
+	_ = c.track(mv, t, nil)
+	rc := c.rule(t, f.Name, tg)
+	rc.Hits++
+	if err != nil {
+		rc.Failures++
+	}
+}
+
+// Rules returns the coverage of the constraints of all the types
+// tracked, in the order they were found.
+func (c *Coverage) Rules() []RuleCoverage {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rules := make([]RuleCoverage, len(c.rules))
+	for i, rc := range c.rules {
+		rules[i] = *rc
+	}
+	return rules
+}
+
+// Missed returns the constraints that were never evaluated.
+func (c *Coverage) Missed() []RuleCoverage {
+	var missed []RuleCoverage
+	for _, rc := range c.Rules() {
+		if rc.Hits == 0 {
+			missed = append(missed, rc)
+		}
+	}
+	return missed
+}
+
+// String reports the constraints that were never evaluated, and those
+// that never failed, a line each, after the share of the constraints
+// evaluated.
+func (c *Coverage) String() string {
+	rules := c.Rules()
+	hit := 0
+	var b strings.Builder
+	for _, rc := range rules {
+		switch {
+		case rc.Hits == 0:
+			fmt.Fprintf(&b, "%s.%s: %s never evaluated\n", rc.Type, rc.Field, rulesText([]Rule{rc.Rule}))
+		case rc.Failures == 0:
+			hit++
+			fmt.Fprintf(&b, "%s.%s: %s never failed\n", rc.Type, rc.Field, rulesText([]Rule{rc.Rule}))
+		default:
+			hit++
+		}
+	}
+	percent := 100.0
+	if len(rules) > 0 {
+		percent = float64(hit) * 100 / float64(len(rules))
+	}
+	return fmt.Sprintf("%d of %d constraints evaluated (%.1f%%)\n", hit, len(rules), percent) + b.String()
+}
@@ -0,0 +1,69 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type CoverAddress struct {
+	City string `validate:"nonzero"`
+}
+
+type CoverSignup struct {
+	Name    string `validate:"nonzero,min=3"`
+	Age     int    `validate:"pagelimit"`
+	Address *CoverAddress
+}
+
+func (ms *MySuite) TestCoverage(c *C) {
+	cov := validator.NewCoverage()
+	mv := validator.NewValidator()
+	mv.SetCoverage(cov)
+
+	c.Assert(mv.Validate(CoverSignup{Name: "x", Age: 10}), NotNil)
+	c.Assert(mv.Validate(CoverSignup{Name: "ann"}), IsNil)
+	c.Assert(mv.Valid("", "nonzero"), NotNil)
+
+	rules := cov.Rules()
+	c.Assert(rules, HasLen, 5)
+	c.Assert(rules[0], Equals, validator.RuleCoverage{
+		Type: "validator_test.CoverSignup", Field: "Name",
+		Rule: validator.Rule{Name: "nonzero"}, Hits: 2,
+	})
+	c.Assert(rules[1].Failures, Equals, 1)
+	c.Assert(rules[3].Rule, Equals, validator.Rule{Name: "max", Param: "100"})
+	c.Assert(cov.Missed(), DeepEquals, []validator.RuleCoverage{{
+		Type: "validator_test.CoverAddress", Field: "City",
+		Rule: validator.Rule{Name: "nonzero"},
+	}})
+	c.Assert(cov.String(), Equals, "4 of 5 constraints evaluated (80.0%)\n"+
+		"validator_test.CoverSignup.Name: nonzero never failed\n"+
+		"validator_test.CoverSignup.Age: min=0 never failed\n"+
+		"validator_test.CoverSignup.Age: max=100 never failed\n"+
+		"validator_test.CoverAddress.City: nonzero never evaluated\n")
+
+	tracked := validator.NewCoverage()
+	c.Assert(tracked.Track(nil, &CoverAddress{}), IsNil)
+	c.Assert(tracked.Missed(), HasLen, 1)
+	c.Assert(tracked.Track(nil, 1), Equals, validator.ErrUnsupported)
+
+	mv.SetCoverage(nil)
+	c.Assert(mv.Validate(CoverAddress{}), NotNil)
+	c.Assert(cov.Missed(), HasLen, 1)
+}
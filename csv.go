@@ -0,0 +1,313 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrMissingColumn is the error returned when the header of a CSV
+	// file lacks a column mapped to a struct field
+	ErrMissingColumn = TextErr{errors.New("missing column")}
+	// ErrBoolean is the error returned when a CSV value is not a boolean
+	ErrBoolean = TextErr{errors.New("invalid boolean")}
+)
+
+// RowErrors holds the errors of a file by row number, starting at 1.
+// The errors of a row are keyed by column, or by the empty string for
+// rules that apply to the row as a whole.
+type RowErrors map[int]ErrorMap
+
+// Error implements the error interface, listing rows in order.
+func (err RowErrors) Error() string {
+	rows := make([]int, 0, len(err))
+	for row := range err {
+		rows = append(rows, row)
+	}
+	sort.Ints(rows)
+
+	var b bytes.Buffer
+	for _, row := range rows {
+		if len(err[row]) > 0 {
+			b.WriteString(fmt.Sprintf("row %d: %s; ", row, err[row].Error()))
+		}
+	}
+	return strings.TrimSuffix(b.String(), "; ")
+}
+
+// csvColumn maps a CSV column to a struct field.
+type csvColumn struct {
+	name  string
+	index int
+	field int
+}
+
+// ValidateCSV calls ValidateCSVContext with the background context.
+func ValidateCSV(r *csv.Reader, rows interface{}) error {
+	return defaultValidator.ValidateCSVContext(context.Background(), r, rows)
+}
+
+// ValidateCSVContext validates a CSV file using the default validator.
+// See Validator.ValidateCSVContext.
+func ValidateCSVContext(ctx context.Context, r *csv.Reader, rows interface{}) error {
+	return defaultValidator.ValidateCSVContext(ctx, r, rows)
+}
+
+// ValidateCSV calls ValidateCSVContext with the background context.
+func (mv *Validator) ValidateCSV(r *csv.Reader, rows interface{}) error {
+	return mv.ValidateCSVContext(context.Background(), r, rows)
+}
+
+// ValidateCSVContext reads the records of r into rows, a pointer to a
+// slice of structs or of struct pointers, and validates each of them.
+// Fields are mapped to the column named in their csv tag, to the
+// column at a zero-based index given as csv:"#2", or, without a tag,
+// to the column named after them. Fields tagged csv:"-" and unexported
+// fields are left alone. When any field is mapped by name, the first
+// record is the header; otherwise every record is a row and a header,
+// if any, must be read from r beforehand.
+//
+// Columns are converted to string, boolean, integer and float fields,
+// or pointers to them, which are left nil for empty values. Numbers may
+// be written in the format of the locale set on ctx with WithLocale.
+// Every record is appended to rows, valid or not, and errors are
+// returned as RowErrors keyed by row number, counting the header, and
+// column name, or #index for columns mapped by index. Errors reading r
+// are returned as is.
+func (mv *Validator) ValidateCSVContext(ctx context.Context, r *csv.Reader, rows interface{}) error {
+	rv := reflect.ValueOf(rows)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return ErrUnsupported
+	}
+	slice := rv.Elem()
+	elemType := slice.Type().Elem()
+	structType := elemType
+	if structType.Kind() == reflect.Ptr {
+		structType = structType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return ErrUnsupported
+	}
+	columns, header, err := csvColumns(structType)
+	if err != nil {
+		return err
+	}
+	keys := map[string]string{}
+	for _, col := range columns {
+		keys[mv.fieldName(structType.Field(col.field))] = col.name
+	}
+
+	errs := RowErrors{}
+	row := 0
+	if header {
+		record, err := r.Read()
+		if err != nil {
+			return err
+		}
+		row++
+		names := map[string]int{}
+		for i, name := range record {
+			if _, ok := names[strings.TrimSpace(name)]; !ok {
+				names[strings.TrimSpace(name)] = i
+			}
+		}
+		for i, col := range columns {
+			if col.index >= 0 {
+				continue
+			}
+			if idx, ok := names[col.name]; ok {
+				columns[i].index = idx
+				continue
+			}
+			if errs[row] == nil {
+				errs[row] = ErrorMap{}
+			}
+			errs[row][col.name] = ErrorArray{ErrMissingColumn}
+		}
+		if len(errs) > 0 {
+			return errs
+		}
+	}
+
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		row++
+
+		sv := reflect.New(structType).Elem()
+		m := ErrorMap{}
+		for _, col := range columns {
+			var s string
+			if col.index < len(record) {
+				s = record[col.index]
+			}
+			if err := setCSVField(ctx, sv.Field(col.field), s); err != nil {
+				m[col.name] = ErrorArray{err}
+			}
+		}
+		if err := mv.ValidateContext(ctx, sv.Addr().Interface()); err != nil {
+			verrs, ok := err.(ErrorMap)
+			if !ok {
+				return err
+			}
+			for key, e := range verrs {
+				name, rest := key, ""
+				if i := strings.IndexByte(key, '.'); i >= 0 {
+					name, rest = key[:i], key[i:]
+				}
+				if col, ok := keys[name]; ok {
+					key = col + rest
+				}
+				if _, ok := m[key]; !ok {
+					m[key] = e
+				}
+			}
+		}
+		if len(m) > 0 {
+			errs[row] = m
+		}
+
+		if elemType.Kind() == reflect.Ptr {
+			slice = reflect.Append(slice, sv.Addr())
+		} else {
+			slice = reflect.Append(slice, sv)
+		}
+	}
+	rv.Elem().Set(slice)
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// csvColumns returns the columns of the fields of t and whether any of
+// them is mapped by name.
+func csvColumns(t reflect.Type) ([]csvColumn, bool, error) {
+	var columns []csvColumn
+	header := false
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("csv")
+		if sf.PkgPath != "" || tag == "-" {
+			continue
+		}
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch ft.Kind() {
+		case reflect.String, reflect.Bool,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+			reflect.Float32, reflect.Float64:
+		default:
+			return nil, false, ErrUnsupported
+		}
+		col := csvColumn{name: tag, index: -1, field: i}
+		if col.name == "" {
+			col.name = sf.Name
+		}
+		if strings.HasPrefix(tag, "#") {
+			idx, err := strconv.Atoi(tag[1:])
+			if err != nil || idx < 0 {
+				return nil, false, ErrBadParameter
+			}
+			col.index = idx
+		} else {
+			header = true
+		}
+		columns = append(columns, col)
+	}
+	return columns, header, nil
+}
+
+// setCSVField sets fv to the value s of a CSV column.
+func setCSVField(ctx context.Context, fv reflect.Value, s string) error {
+	if fv.Kind() == reflect.Ptr {
+		if s == "" {
+			return nil
+		}
+		p := reflect.New(fv.Type().Elem())
+		if err := setCSVField(ctx, p.Elem(), s); err != nil {
+			return err
+		}
+		fv.Set(p)
+		return nil
+	}
+	if fv.Kind() == reflect.String {
+		fv.SetString(s)
+		return nil
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return ErrBoolean
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := plainNumber(ctx, s)
+		if !ok {
+			return ErrInteger
+		}
+		i, err := strconv.ParseInt(n, 10, fv.Type().Bits())
+		if err != nil {
+			return ErrInteger
+		}
+		fv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, ok := plainNumber(ctx, s)
+		if !ok {
+			return ErrInteger
+		}
+		u, err := strconv.ParseUint(n, 10, fv.Type().Bits())
+		if err != nil {
+			return ErrInteger
+		}
+		fv.SetUint(u)
+	case reflect.Float32, reflect.Float64:
+		if n, ok := plainNumber(ctx, s); ok {
+			s = n
+		}
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return ErrNumber
+		}
+		fv.SetFloat(f)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type importedUser struct {
+	Email string   `csv:"email" validate:"nonzero,max=64"`
+	Age   int      `csv:"age" validate:"min=18"`
+	Score *float64 `csv:"score" validate:"nonnil"`
+	Admin bool
+	Notes string `csv:"-"`
+}
+
+func (ms *MySuite) TestValidateCSV(c *C) {
+	data := "age,email,Admin,score\n" +
+		"30,jane@example.com,true,1.5\n" +
+		"12,,false,\n" +
+		"x,bob@example.com,maybe,2\n"
+	var users []importedUser
+	err := validator.ValidateCSV(csv.NewReader(strings.NewReader(data)), &users)
+	c.Assert(err, NotNil)
+	c.Assert(users, HasLen, 3)
+	c.Assert(users[0].Email, Equals, "jane@example.com")
+	c.Assert(users[0].Age, Equals, 30)
+	c.Assert(*users[0].Score, Equals, 1.5)
+	c.Assert(users[0].Admin, Equals, true)
+
+	errs, ok := err.(validator.RowErrors)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs[3], HasLen, 3)
+	c.Assert(errs[3]["email"], HasError, validator.ErrZeroValue)
+	c.Assert(errs[3]["age"], HasError, validator.ErrMin)
+	c.Assert(errs[3]["score"], HasError, validator.ErrZeroValue)
+	c.Assert(errs[4], HasLen, 2)
+	c.Assert(errs[4]["age"], HasError, validator.ErrInteger)
+	c.Assert(errs[4]["Admin"], HasError, validator.ErrBoolean)
+	c.Assert(err.Error(), Matches, "row 3: .*; row 4: .*")
+
+	err = validator.ValidateCSV(csv.NewReader(strings.NewReader("email,score\n")), &users)
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.RowErrors)[1]["age"], HasError, validator.ErrMissingColumn)
+
+	err = validator.ValidateCSV(csv.NewReader(strings.NewReader("a\n")), []importedUser{})
+	c.Assert(err, Equals, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestValidateCSVIndex(c *C) {
+	type entry struct {
+		SKU      string  `csv:"#0" validate:"nonzero"`
+		Price    *string `csv:"#2" validate:"amount=currencyfield:Currency"`
+		Qty      uint    `csv:"#1"`
+		Currency string  `csv:"-"`
+	}
+	data := "A1;1 000;12,50\n;2;3,999\n"
+	r := csv.NewReader(strings.NewReader(data))
+	r.Comma = ';'
+	var entries []*entry
+	ctx := validator.WithLocale(context.Background(), "fr")
+	err := validator.ValidateCSVContext(ctx, r, &entries)
+	c.Assert(err, NotNil)
+	c.Assert(entries, HasLen, 2)
+	c.Assert(entries[0].Qty, Equals, uint(1000))
+	errs := err.(validator.RowErrors)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs[1]["#2"], HasError, validator.ErrCurrency)
+	c.Assert(errs[2]["#0"], HasError, validator.ErrZeroValue)
+}
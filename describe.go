@@ -0,0 +1,260 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rule is a constraint of a tag, such as min=3.
+type Rule struct {
+	Name  string `json:"name"`
+	Param string `json:"param,omitempty"`
+	// Source tells where the rule was set, SourceTag, SourceFieldRules
+	// or SourceLoaded, in descriptions.
+	Source string `json:"source,omitempty"`
+	// Alias is the alias the rule was expanded from, as written where
+	// the rule was set, if any.
+	Alias string `json:"alias,omitempty"`
+}
+
+// Sources of rules, as given by Rule.Source.
+const (
+	// SourceTag is the source of the rules of struct tags.
+	SourceTag = "tag"
+	// SourceFieldRules is the source of the rules set with
+	// SetFieldRules.
+	SourceFieldRules = "field rules"
+	// SourceLoaded is the source of the rules read with Reload.
+	SourceLoaded = "loaded"
+)
+
+// FieldDescription describes the constraints of a field, such as to
+// generate a form field for it. Constraints that cannot be told from
+// the tag alone, such as those of iff or those naming a limit, are
+// listed in Rules only.
+type FieldDescription struct {
+	// Name is the key errors of the field are reported under, such as
+	// "Address.City" for the fields of nested structs.
+	Name string `json:"name"`
+	// Type is the Go type of the field.
+	Type string `json:"type"`
+	// Rules lists the constraints of the field, with aliases expanded,
+	// along with where they were set.
+	Rules []Rule `json:"rules"`
+	// Required is set by nonzero and nonnil.
+	Required bool `json:"required,omitempty"`
+	// MinLength and MaxLength are the number of characters of strings,
+	// or of items of slices, arrays and maps, set by len, min and max.
+	MinLength *int64 `json:"min_length,omitempty"`
+	MaxLength *int64 `json:"max_length,omitempty"`
+	// Min and Max are the bounds of numbers set by min and max.
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+	// Pattern is the regular expression set by regexp.
+	Pattern string `json:"pattern,omitempty"`
+	// Enum lists the values allowed by enum, in their string form.
+	Enum []string `json:"enum,omitempty"`
+}
+
+// Attrs returns the HTML attributes of an input element enforcing the
+// constraints of the field, such as required, minlength, maxlength,
+// pattern, min and max. Boolean attributes have empty values.
+func (d FieldDescription) Attrs() map[string]string {
+	attrs := map[string]string{}
+	if d.Required {
+		attrs["required"] = ""
+	}
+	if d.MinLength != nil {
+		attrs["minlength"] = strconv.FormatInt(*d.MinLength, 10)
+	}
+	if d.MaxLength != nil {
+		attrs["maxlength"] = strconv.FormatInt(*d.MaxLength, 10)
+	}
+	if d.Min != nil {
+		attrs["min"] = strconv.FormatFloat(*d.Min, 'f', -1, 64)
+	}
+	if d.Max != nil {
+		attrs["max"] = strconv.FormatFloat(*d.Max, 'f', -1, 64)
+	}
+	if d.Pattern != "" {
+		// patterns of input elements must match the whole value
+		p := d.Pattern
+		if strings.HasPrefix(p, "^") && strings.HasSuffix(p, "$") && !strings.HasSuffix(p, `\$`) {
+			p = "(?:" + p[1:len(p)-1] + ")"
+		} else {
+			p = ".*(?:" + p + ").*"
+		}
+		attrs["pattern"] = p
+	}
+	return attrs
+}
+
+// Describe describes the fields of v using the default validator.
+// See Validator.Describe.
+func Describe(v interface{}) ([]FieldDescription, error) {
+	return defaultValidator.Describe(v)
+}
+
+// Describe returns the constraints of the fields of the struct type of
+// v, a struct or a pointer to one, in the order of the fields. Fields of
+// nested structs are described after the field holding them, and fields
+// without constraints are left out. It returns an error if a tag is
+// unknown.
+func (mv *Validator) Describe(v interface{}) ([]FieldDescription, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, ErrUnsupported
+	}
+	var ds []FieldDescription
+	err := mv.describe(t, nil, "", map[reflect.Type]bool{}, &ds)
+	return ds, err
+}
+
+// describe appends the descriptions of the fields of the struct type t,
+// held by a value of the named type owner and keyed under prefix, to ds.
+func (mv *Validator) describe(t, owner reflect.Type, prefix string, seen map[reflect.Type]bool, ds *[]FieldDescription) error {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	defer delete(seen, t)
+	owner = ownerOf(owner, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := mv.fieldTag(owner, t, sf)
+		if sf.Name == "_" || tag == "-" || !sf.Anonymous && sf.PkgPath != "" {
+			continue
+		}
+		name := joinKey(prefix, mv.fieldName(sf))
+		if tag != "" {
+			tags, err := mv.parseTags(tag)
+			if err != nil {
+				return err
+			}
+			ft := sf.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			source := SourceTag
+			if _, ok := mv.ruleFile().rules(owner, t, sf.Name); ok {
+				source = SourceLoaded
+			} else if _, ok := mv.fieldRules[t][sf.Name]; ok {
+				source = SourceFieldRules
+			}
+			*ds = append(*ds, describeField(name, sf.Type.String(), ft.Kind(), source, tags))
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			if err := mv.describe(ft, owner, name, seen, ds); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// describeField returns the description of the field called name with
+// the given constraints, set in source, whose type is typ and whose
+// kind, once pointers are dereferenced, is kind.
+func describeField(name, typ string, kind reflect.Kind, source string, tags []tag) FieldDescription {
+	d := FieldDescription{Name: name, Type: typ, Rules: make([]Rule, len(tags))}
+	for i, tg := range tags {
+		d.Rules[i] = Rule{Name: tg.Name, Param: tg.Param, Source: source, Alias: tg.Alias}
+		switch tg.Name {
+		case "nonzero", "nonnil":
+			d.Required = true
+		case "len", "min", "max":
+			d.describeBound(kind, tg.Name, tg.Param)
+		case "regexp":
+			d.Pattern = tg.Param
+		case "enum":
+			d.Enum = enumOptions(tg.Param)
+		}
+	}
+	return d
+}
+
+// describeBound sets the bounds of d given by the len, min or max
+// constraint with the given parameter on a value of the given kind.
+func (d *FieldDescription) describeBound(kind reflect.Kind, name, param string) {
+	switch kind {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		n, err := asInt(param)
+		if err != nil {
+			// sizes and limits are not numbers of characters
+			return
+		}
+		if name != "max" {
+			d.MinLength = &n
+		}
+		if name != "min" {
+			d.MaxLength = &n
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		n, err := asFloat(param)
+		if err != nil {
+			return
+		}
+		if name != "max" {
+			d.Min = &n
+		}
+		if name != "min" {
+			d.Max = &n
+		}
+	}
+}
+
+// enumOptions returns the string forms of the values of the enumeration
+// registered under name, sorted.
+func enumOptions(name string) []string {
+	enumsMu.RLock()
+	es, ok := enums[name]
+	enumsMu.RUnlock()
+	if !ok {
+		return nil
+	}
+	var options []string
+	if len(es.strings) > 0 {
+		for s := range es.strings {
+			options = append(options, s)
+		}
+		sort.Strings(options)
+		return options
+	}
+	ints := make([]int64, 0, len(es.ints))
+	for n := range es.ints {
+		ints = append(ints, n)
+	}
+	sort.Slice(ints, func(i, j int) bool { return ints[i] < ints[j] })
+	for _, n := range ints {
+		options = append(options, strconv.FormatInt(n, 10))
+	}
+	return options
+}
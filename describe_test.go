@@ -0,0 +1,89 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type formColor string
+
+func (ms *MySuite) TestDescribe(c *C) {
+	c.Assert(validator.RegisterEnum("form_color", formColor("red"), formColor("blue")), IsNil)
+	type address struct {
+		City string `json:"city" validate:"nonzero,max=40"`
+	}
+	type signup struct {
+		Username string   `json:"username" validate:"nonzero,min=3,max=20,regexp=^[a-z]+$"`
+		Age      *int     `json:"age" validate:"min=13,max=120"`
+		Code     string   `json:"code" validate:"len=6"`
+		Color    string   `json:"color" validate:"enum=form_color"`
+		Avatar   []byte   `json:"avatar" validate:"max=64KB"`
+		Tags     []string `json:"tags" validate:"max=5"`
+		Bio      string   `json:"bio"`
+		Address  address  `json:"address"`
+	}
+	ds, err := validator.WithNameTag("json").Describe(&signup{})
+	c.Assert(err, IsNil)
+	c.Assert(ds, HasLen, 7)
+
+	c.Assert(ds[0].Name, Equals, "username")
+	c.Assert(ds[0].Type, Equals, "string")
+	c.Assert(ds[0].Rules, HasLen, 4)
+	c.Assert(ds[0].Rules[1], Equals, validator.Rule{Name: "min", Param: "3", Source: validator.SourceTag})
+	c.Assert(ds[0].Attrs(), DeepEquals, map[string]string{
+		"required": "", "minlength": "3", "maxlength": "20", "pattern": "(?:[a-z]+)",
+	})
+	c.Assert(ds[1].Attrs(), DeepEquals, map[string]string{"min": "13", "max": "120"})
+	c.Assert(ds[2].Attrs(), DeepEquals, map[string]string{"minlength": "6", "maxlength": "6"})
+	c.Assert(ds[3].Enum, DeepEquals, []string{"blue", "red"})
+	c.Assert(ds[4].Attrs(), HasLen, 0)
+	c.Assert(*ds[5].MaxLength, Equals, int64(5))
+	c.Assert(ds[6].Name, Equals, "address.city")
+
+	c.Assert(validator.FieldDescription{Pattern: "[0-9]"}.Attrs()["pattern"], Equals, ".*(?:[0-9]).*")
+
+	type bad struct {
+		A string `validate:"nosuchtag"`
+	}
+	_, err = validator.Describe(bad{})
+	c.Assert(err, Equals, validator.ErrUnknownTag)
+	_, err = validator.Describe("x")
+	c.Assert(err, Equals, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestDescribeProvenance(c *C) {
+	type page struct {
+		Limit  int `validate:"nonzero,pagelimit"`
+		Offset int `validate:"min=0"`
+	}
+	v := validator.NewValidator()
+	c.Assert(v.SetFieldRules(page{}, "Offset", "max=1000"), IsNil)
+
+	ds, err := v.Describe(page{})
+	c.Assert(err, IsNil)
+	c.Assert(ds, HasLen, 2)
+	c.Assert(ds[0].Rules, DeepEquals, []validator.Rule{
+		{Name: "nonzero", Source: validator.SourceTag},
+		{Name: "min", Param: "0", Source: validator.SourceTag, Alias: "pagelimit"},
+		{Name: "max", Param: "100", Source: validator.SourceTag, Alias: "pagelimit"},
+	})
+	c.Assert(ds[1].Rules, DeepEquals, []validator.Rule{
+		{Name: "max", Param: "1000", Source: validator.SourceFieldRules},
+	})
+}
@@ -49,6 +49,17 @@ Then validating a variable of type NewUserRequest becomes trivial.
 		// do something
 	}
 
+Slices, arrays and maps of structs can be validated as well. Errors are then
+indexed by the position or key of the items, as are errors found in fields
+holding collections.
+
+	errs = validator.Validate([]NewUserRequest{nur, other})
+	// errs: validator.ErrorMap{"[1].Age": {validator.ErrMin}}
+
+Validate accepts values of any type and does not panic, unless a custom
+validation function does. Nil values, such as a nil *NewUserRequest, return
+ErrInvalid, and values that refer to themselves are validated once.
+
 Builtin validator functions
 
 Here is the list of validator functions builtin in the package.
@@ -57,19 +68,54 @@ Here is the list of validator functions builtin in the package.
 		For numeric numbers, len will simply make sure that the value is
 		equal to the parameter given. For strings, it checks that
 		the string length is exactly that number of characters. For slices,
-		arrays, and maps, validates the number of items. (Usage: len=10)
+		arrays, and maps, validates the number of items. For strings and
+		[]byte, a size such as 64KB or 2MiB is compared with the number of
+		bytes instead. (Usage: len=10, len=64KB)
 
 	max
 		For numeric numbers, max will simply make sure that the value is
 		lesser or equal to the parameter given. For strings, it checks that
 		the string length is at most that number of characters. For slices,
-		arrays, and maps, validates the number of items. (Usage: max=10)
+		arrays, and maps, validates the number of items. For strings and
+		[]byte, a size such as 64KB or 2MiB is compared with the number of
+		bytes instead. (Usage: max=10, max=64KB)
 
 	min
 		For numeric numbers, min will simply make sure that the value is
 		greater or equal to the parameter given. For strings, it checks that
 		the string length is at least that number of characters. For slices,
-		arrays, and maps, validates the number of items. (Usage: min=10)
+		arrays, and maps, validates the number of items. For strings and
+		[]byte, a size such as 64KB or 2MiB is compared with the number of
+		bytes instead. (Usage: min=10, min=64KB)
+
+	maxbytes
+		Only valid for strings and []byte, it validates that the value is at
+		most the given number of bytes, a plain number or a size in B, KB,
+		MB, GB, TB, KiB, MiB, GiB or TiB. (Usage: maxbytes=2MiB)
+
+	minbytes
+		Only valid for strings and []byte, it validates that the value is at
+		least the given number of bytes, a plain number or a size in B, KB,
+		MB, GB, TB, KiB, MiB, GiB or TiB. (Usage: minbytes=1KB)
+
+	before
+		Only valid for time.Time, it validates that the time is before the
+		given time, in RFC 3339 format, a date, or now followed by an
+		optional duration. Zero times are left to nonzero.
+		(Usage: before=2030-01-01, before=now+24h)
+
+	after
+		Only valid for time.Time, it validates that the time is after the
+		given time, in RFC 3339 format, a date, or now followed by an
+		optional duration. Zero times are left to nonzero.
+		(Usage: after=2020-01-01T00:00:00Z, after=now-1h)
+
+	eq_canonical
+		It validates that the value is deeply equal, as told by
+		reflect.DeepEqual, to the canonical value registered with
+		RegisterCanonical under the given name, such as the approved
+		baseline of a configuration. Nil values are valid.
+		(Usage: eq_canonical=defaultSettings)
 
 	nonzero
 		This validates that the value is not zero. The appropriate zero value
@@ -85,6 +131,211 @@ Here is the list of validator functions builtin in the package.
 	nonnil
 		Validates that the given value is not nil. Usage: nonnil
 
+	amount
+		Only valid for string types, it validates that the value is a
+		plain decimal amount (e.g. "-12.50") with no more fraction digits
+		than allowed by the ISO 4217 currency held in the named field.
+		Optional minimum and maximum bounds are compared exactly, without
+		floating point rounding. Amounts may also be written in the number
+		format of the locale set with WithLocale.
+		(Usage: amount=currencyfield:Currency,
+		amount=currencyfield:Currency:0.01:1000)
+
+	floatstr
+		Only valid for string types, it validates that the value is a
+		finite decimal number, such as "-12.50", without exponent, written
+		plainly or in the number format of the locale set with WithLocale.
+		(Usage: floatstr)
+
+	intstr
+		Only valid for string types, it validates that the value is a
+		64-bit decimal integer, optionally written in the number format of
+		the locale set with WithLocale. (Usage: intstr)
+
+	geoprecision
+		For floats and decimal strings holding a latitude or longitude, it
+		validates that the value has at most the given number of decimal
+		places. (Usage: geoprecision=6)
+
+	maxdistance
+		A struct level rule validating that the great-circle distance
+		between two point fields is at most the given distance, in m, km
+		or mi. Point types must be registered with RegisterPoint and nil
+		points are considered valid. The commas separating the parameters
+		must be escaped. (Usage: maxdistance=Pickup\\,Dropoff\\,100km)
+
+	track
+		For slices and arrays of points registered with RegisterTimedPoint,
+		it validates that timestamps are strictly increasing and, when a
+		maximum speed in m/s, km/h or mph is given, that the speed implied
+		by consecutive points is at most that speed. (Usage: track, track=300km/h)
+
+	incountry
+		Validates that a point lies within the region of the country whose
+		ISO 3166-1 alpha-2 code is held in the named field. It is set either
+		on a registered point field or, naming the latitude and longitude
+		fields, as a struct level rule. Only a few countries have a builtin
+		bounding box; RegisterCountry sets the region of others, and countries
+		without a region are not checked.
+		(Usage: incountry=Country, incountry=Lat Long Country)
+
+	enum
+		Validates that the value is one of the values registered under the
+		given name with RegisterEnum. Fields of the enumeration's type must
+		hold one of the values, while string and integer fields must hold
+		the underlying value or, for fmt.Stringer values, the string form of
+		one of the values. Integer enumerations, such as protobuf generated
+		enums, can be registered from their name maps with RegisterEnumNames.
+		Without a name, it validates that a protobuf generated enum holds one
+		of the values declared in its descriptor. Combine with nonzero to
+		reject the zero (UNSPECIFIED) value. (Usage: enum=ride_state, enum)
+
+	flags
+		For integer bitmasks, it validates that only the bits of the given
+		flags are set. Flags are given as names registered with
+		RegisterFlags or as integers. Combine with nonzero to require at
+		least one flag. (Usage: flags=read write admin, flags=1 2 4)
+
+	acyclic
+		For slices and arrays of structs holding their own ID and the ID
+		of their parent, it validates that they describe a forest: IDs are
+		unique, parents exist within the slice and no item is its own
+		ancestor. Items with a zero or nil parent ID are roots.
+		(Usage: acyclic=ID ParentID)
+
+	base64url
+		Only valid for string types, it validates that the value is URL-safe
+		base64, with or without padding, such as opaque pagination cursors.
+		(Usage: base64url)
+
+	exclusive
+		Validates that at most one of the field and the named fields holds
+		a value other than its zero value. (Usage: exclusive=Offset Page)
+
+	filter
+		Only valid for string types, it validates that the value is a filter
+		expression, such as `age >= 18 AND (status = "active" OR admin = true)`,
+		that only uses the fields, operators and value types allowed by the
+		FilterSchema registered under the given name with RegisterFilter.
+		(Usage: filter=users)
+
+	idempotencykey
+		Only valid for string types, it validates that the value is an
+		idempotency key made of 1 to 255 letters, digits and _ . : - characters.
+		The key can be restricted to the uuid or ulid forms.
+		(Usage: idempotencykey, idempotencykey=uuid ulid)
+
+	traceparent
+		Only valid for string types, it validates that the value is a W3C
+		Trace Context traceparent header. (Usage: traceparent)
+
+	webhooksig
+		Only valid for string types, it validates that the value is a
+		webhook signature header such as "t=1492774577,v1=5257a8...", with a
+		unix timestamp and one or more hex SHA-256 v1 signatures, whose
+		timestamp is within the given tolerance, 5 minutes by default, of
+		the current time. NewWebhookSignatureFunc returns a version using
+		another clock. (Usage: webhooksig, webhooksig=10m)
+
+	acceptlanguage
+		Only valid for string types, it validates that the value is an
+		Accept-Language header value, such as "fr-CH, fr;q=0.9, *;q=0.5".
+		(Usage: acceptlanguage)
+
+	mediatype
+		Only valid for string types, it validates that the value is a media
+		type with optional parameters, as found in Content-Type headers. The
+		media type can be restricted to the given ones, which may use
+		wildcards. (Usage: mediatype, mediatype=application/json text/*)
+
+	printable
+		Only valid for string types, it validates that the value is valid
+		UTF-8 made of printable characters only, without control or
+		invisible formatting characters, as expected of free text headers
+		such as User-Agent. It optionally limits the length in bytes and
+		restricts the value to ASCII. (Usage: printable, printable=512 ascii)
+
+	hostallow
+		Only valid for string types, it validates that the host of the value,
+		a URL or a host with an optional port, matches one of the given
+		patterns, in which a * label matches exactly one label.
+		(Usage: hostallow=*.example.com api.partner.io)
+
+	notsimilar
+		Only valid for string types, it validates that the value is more
+		than a number of edits, 2 by default, away from the string values
+		of the named fields, ignoring case. The local part of email
+		addresses is compared as well. Commas between field names must be
+		escaped. (Usage: notsimilar=Username Email:3)
+
+	minentropy
+		Only valid for string types, it validates that the estimated
+		entropy of the value is at least the given number of bits. The
+		estimate is the number of characters times log2 of the size of the
+		character classes used, not counting repeated characters and runs
+		such as "abc" or "321". (Usage: minentropy=40)
+
+	implements
+		For interface fields, it validates that the value held, or a pointer
+		to it, implements the interface type registered under the given name
+		with RegisterType. Nil values are valid. (Usage: implements=plugin)
+
+	typeoneof
+		For interface fields, it validates that the value held, or the value
+		it points to, has one of the types registered under the given names
+		with RegisterType. The value is then validated with the tags of its
+		own type, as for any interface field. Nil values are valid.
+		(Usage: typeoneof=Card Wallet Cash)
+
+	discriminates
+		Only valid for string types, it validates the payload held in the
+		named field against the type registered with RegisterType under the
+		value of the field, such as the type of an event. The payload is JSON
+		held in a json.RawMessage, []byte or string, or a map, and its errors
+		are reported under the payload field. (Usage: discriminates=Data)
+
+	iff
+		Enforces the constraints given after the name of a feature flag only
+		when the flag provider set with SetFlagProvider reports the flag as
+		enabled for the context of the validation, so that stricter rules
+		can be rolled out gradually. Commas between the constraints must be
+		escaped. (Usage: iff=strict_names:min=3\\,max=40)
+
+	sample
+		Enforces the constraints given after a rate between 0 and 1 on that
+		fraction of the values only, so that costly checks, such as regular
+		expressions on large values or remote validators, can be monitored
+		while cheap ones always run. Commas between the constraints must be
+		escaped. (Usage: sample=0.01:regexp=^[a-z]+$)
+
+Rules that apply to a struct as a whole, rather than to one of its fields,
+are set on blank (_) fields. The validation functions then receive the struct
+itself and their errors are reported under the name of the struct, or under
+the empty name for the value passed to Validate.
+
+	type Ride struct {
+		_       struct{} `validate:"maxdistance=Pickup\\,Dropoff\\,100km"`
+		Pickup  Point
+		Dropoff Point
+	}
+
+Rules that are easier to express in Go, such as when the items of one slice
+must refer to the items of another, can be set for a struct type with
+SetStructValidationFunc. The function receives the whole struct once its
+fields have been validated and may return an ErrorMap to report errors under
+the names of the struct's fields.
+
+	validator.SetStructValidationFunc(Order{}, func(v interface{}) error {
+		o := v.(Order)
+		errs := validator.ErrorMap{}
+		for i, l := range o.Lines {
+			if !o.HasProduct(l.ProductID) {
+				errs[fmt.Sprintf("Lines[%d].ProductID", i)] = validator.ErrorArray{ErrNoProduct}
+			}
+		}
+		return errs
+	})
+
 Note that there are no tests to prevent conflicting validator parameters. For
 instance, these fields will never be valid.
 
@@ -93,6 +344,15 @@ instance, these fields will never be valid.
 	B string  `validate:"len=10,regexp=^$"
 	...
 
+Besides the core functions such as nonzero, len, min, max and regexp, the
+builtin functions come in bundles: Network, Payments, Geo and Strings.
+NewValidator registers all of them, while NewCoreValidator starts with the
+core functions only, to which bundles are added with Register. Third parties
+can publish their own bundles by implementing the Bundle interface.
+
+	v := validator.NewCoreValidator()
+	err := v.Register(validator.Payments, acme.Catalog)
+
 Custom validation functions
 
 It is possible to define custom validation functions by using SetValidationFunc.
@@ -159,6 +419,135 @@ And you can delete a validation function by setting it to nil.
 	validate.SetValidationFunc("notzz", nil)
 	validate.SetValidationFunc("nonzero", nil)
 
+Validation functions that need the values of other fields of the struct can
+be registered with SetFieldValidationFunc. They receive a Field describing the
+field being validated, whose Sibling method looks up other fields by name.
+
+	func eqField(v interface{}, f validator.Field, param string) error {
+		other, ok := f.Sibling(param)
+		if !ok {
+			return validator.ErrBadParameter
+		}
+		if other.Interface() != v {
+			return errors.New("must be equal to " + param)
+		}
+		return nil
+	}
+
+	validator.SetFieldValidationFunc("eqfield", eqField)
+
+Field validation functions may also return an ErrorMap, whose errors are
+keyed relative to the struct holding the field, to report errors found in
+other fields, and use Field.Validate to validate values of their own, such
+as payloads decoded from the field.
+
+Validation functions that talk to other services should use the context
+returned by Field.Context. It is the one given to ValidateContext or
+ValidContext, and context.Background() for Validate and Valid.
+
+	err := validator.ValidateContext(r.Context(), signup)
+
+One such function checks passwords against the Pwned Passwords range API,
+sending only the first five characters of their SHA-1 hash. It is not
+registered by default.
+
+	validator.SetFieldValidationFunc("pwned", validator.NewPwnedFunc(validator.PwnedConfig{
+		Range:    validator.NewPwnedRange(nil, ""),
+		CacheTTL: time.Hour,
+		FailOpen: true,
+	}))
+
+	type Signup struct {
+		Password string `validate:"min=12,pwned"`
+	}
+
+Numeric strings read by amount, floatstr and intstr may be written in the
+number format of a locale given to ValidateContext. Formats of other locales
+can be set with RegisterNumberFormat.
+
+	ctx := validator.WithLocale(r.Context(), "fr-FR")
+	err := validator.ValidateContext(ctx, row) // accepts "1 234,56"
+
+Request-scoped data, such as the country or plan of the authenticated user,
+is given to field validation functions with WithValues and read back with
+FromContext or Field.Values, without globals.
+
+	validator.SetFieldValidationFunc("shipsto", func(v interface{}, f validator.Field, param string) error {
+		if !shipping.Allowed(f.Values().String("country"), v.(string)) {
+			return ErrNoShipping
+		}
+		return nil
+	})
+	ctx := validator.WithValues(r.Context(), validator.Values{"country": user.Country})
+	err := validator.ValidateContext(ctx, order)
+
+The parameters of len, min, max, minbytes and maxbytes may name a limit, such as one set by the
+plan of the customer, which is looked up for each validation by the provider
+set with SetLimitProvider. Limits the provider does not know are not enforced.
+
+	validator.SetLimitProvider(func(ctx context.Context, name string) (string, bool) {
+		return plans.Limit(tenant.FromContext(ctx), name)
+	})
+
+	type Message struct {
+		Attachments []Attachment `validate:"max=@attachmentLimit"`
+	}
+
+Errors are keyed by the names of struct fields unless another struct tag is
+chosen with SetNameTag or WithNameTag, so that they match what clients sent.
+Any tag using the json syntax, such as msgpack, can be chosen. The protobuf
+tag gives the JSON names of protobuf generated fields, from their json_name
+option, with the fields of oneofs named as if they were not in a oneof.
+ValidateXML decodes an XML document and validates it with the names of its
+xml tags.
+
+	err := validator.WithNameTag("json").Validate(req)
+	err = validator.ValidateXML(body, &order)
+	// err: validator.ErrorMap{"customer.name": {validator.ErrMin}}
+
+ValidateYAML does the same for YAML documents and wraps errors in a
+PositionErr holding the line and column of the failing node.
+
+ErrorMap and ErrorArray unwrap to the errors they hold, so errors.Is and
+errors.As find individual errors, including errors joined with errors.Join
+by validation functions. ErrorMap errors are wrapped in a FieldError naming
+their field.
+
+	if errors.Is(err, validator.ErrZeroValue) {
+		// a required field is missing
+	}
+
+Check validates a value like Validate but returns a Result, which lists
+errors and warnings by field without type assertions. Validation functions
+report warnings by returning a Warning, which Validate reports as an error.
+
+	r := validator.Check(req)
+	if !r.Ok() {
+		json.NewEncoder(w).Encode(r) // {"valid":false,"errors":[...],"warnings":[...]}
+	}
+
+Bulk imports can be read and validated with ValidateCSV, which maps columns
+to struct fields by header name or index and reports errors by row number
+and column.
+
+	type Row struct {
+		Email string `csv:"email" validate:"nonzero"`
+		Age   int    `csv:"age" validate:"min=18"`
+	}
+
+	var rows []Row
+	err := validator.ValidateCSV(csv.NewReader(f), &rows)
+	// err: validator.RowErrors{3: {"age": {validator.ErrMin}}}
+
+The errors can then be summarized for the user who sent the file, with the
+share of failed rows and, for each distinct error, its count and the first
+rows it was found in.
+
+	if errs, ok := err.(validator.RowErrors); ok {
+		report := validator.NewImportReport(len(rows), errs, 5)
+		json.NewEncoder(w).Encode(report)
+	}
+
 Using a non-existing validation func in a field tag will always return
 false and with error validate.ErrUnknownTag.
 
@@ -174,6 +563,32 @@ to validate simple variables/values.
 	// errs: [validate.ErrMin,validate.ErrMax]
 	errs = validator.Valid("hi", "nonzero,min=3,max=2")
 
+Enumerations are registered from the typed Go constants themselves, so that
+the list of allowed values is not duplicated in struct tags.
+
+	validator.RegisterEnum("ride_state", RideStatePending, RideStateOngoing)
+
+	type Ride struct {
+		State RideState `validate:"enum=ride_state"`
+	}
+
+Aliases
+
+A set of rules used in many places can be given a name with SetAlias and
+then be used in tags like any validation function.
+
+	validator.SetAlias("username", "min=3,max=40,regexp=^[a-z]+$")
+
+	type User struct {
+		Name string `validate:"username"`
+	}
+
+The Pagination type, meant to be embedded in list requests, uses the
+pagelimit alias to validate its limit, which can be changed to suit each
+validator.
+
+	validator.SetAlias("pagelimit", "min=1,max=500")
+
 Custom tag name
 
 In case there is a reason why one would not wish to use tag 'validate' (maybe due to
@@ -197,6 +612,315 @@ with a new call to SetTag. A way to temporarily use a different tag exists.
 	// But this will go back to using 'validate'
 	validator.Validate(t)
 
+Programs using the package level functions can set up the default validator
+once with Configure, before its first validation. Its configuration is then
+locked: later calls to Configure, and to the package level functions
+changing the default validator such as SetTag or SetValidationFunc, return
+ErrConfigured or do nothing. Field names can be given by a function with
+NameFunc, and messages translated for users by Check with Translator.
+
+	func main() {
+		err := validator.Configure(
+			validator.Tag("valid"),
+			validator.NameTag("json"),
+			validator.Translator(messages.Translate),
+		)
+		...
+	}
+
+Options can also be applied to a single request with WithOptions, which
+derives a validator sharing the validation functions of the one it is called
+on, so that it is cheap to call for every request.
+
+	v := validator.WithOptions(validator.Locale(user.Locale), validator.FailFast(true))
+	err := v.ValidateContext(r.Context(), req)
+
+Fields using slow rules, such as field validation functions calling remote
+services, can be validated concurrently with the other fields of their
+struct with the Concurrent option, so that a struct takes as long as its
+slowest check rather than the sum of them.
+
+	err := validator.Configure(validator.Concurrent("fraudcheck", "sanctions"))
+
+Validation functions whose result depends only on the value and the
+parameter can be marked pure with the Pure option, so that within one
+validation, such as of a batch in which every line holds the same currency
+code, they are called once per distinct value.
+
+	err := validator.Configure(validator.Pure("currency"))
+
+The errors of map elements are keyed by the form of their map key given
+by FormatKey, as in "Cells[{X:1 Y:2}](value)", which formats pointers by the
+values they point to so that paths do not change between runs. Another
+form can be set with the KeyFormatter option.
+
+	err := validator.Configure(validator.KeyFormatter(func(key reflect.Value) string {
+		p := key.Interface().(Point)
+		return fmt.Sprintf("%d,%d", p.X, p.Y) // Cells[1,2](value)
+	}))
+
+Ingestion pipelines accepting slightly dirty records can mark rules as soft
+with the Soft option, whose errors are then reported as warnings, and
+validate records with ValidateWithBudget, which accepts up to a number of
+warnings but rejects records breaking any other rule.
+
+	v := validator.WithOptions(validator.Soft("max", "regexp"))
+	accepted, err := v.ValidateWithBudget(record, 2)
+
+Batches can be split with Quarantine into their valid items and the
+rejected ones with their errors, so that import jobs process the valid
+rows rather than failing the whole batch. Rows with warnings only are valid.
+
+	valid, rejected := validator.Quarantine(nil, rows)
+
+Regulated flows, such as KYC or payments, that must prove their input was
+validated can use the Audit option, which passes a record of every
+validation to a function: the hashes of the payload and of the rules
+enforced, and the outcome. Records can be signed with Sign, encoded as
+JSON and checked later with Verify.
+
+	v := validator.WithOptions(validator.Audit(func(ctx context.Context, r validator.AuditRecord) {
+		r, _ = r.Sign(auditKey)
+		json.NewEncoder(auditLog).Encode(r)
+	}))
+	err := v.ValidateContext(validator.WithAuditPayload(ctx, body), &req)
+
+High-throughput pipelines can validate items asynchronously with a
+BatchValidator, a pool of workers with a bounded queue and a timeout for
+each item, whose results are streamed over a channel. Submit blocks while
+the queue is full, so that slow consumers slow the producers down.
+
+	b := validator.NewBatchValidator[Event](nil, validator.BatchConfig{Workers: 8, Timeout: time.Second})
+	go func() {
+		for e := range events {
+			b.Submit(ctx, e)
+		}
+		b.Close()
+	}()
+	for r := range b.Results() {
+		if r.Err != nil {
+			log.Printf("event %d: %v", r.Index, r.Err)
+		}
+	}
+
+The rules of a struct type can be exported with ExportRules, encoded as
+JSON and loaded back with LoadRules, such as by a gateway which does not
+import the struct definitions. The loaded RuleSet validates the maps decoded
+from JSON documents.
+
+	v := validator.NewValidator()
+	v.SetNameTag("json")
+	rs, _ := v.ExportRules(Order{})
+	data, _ := json.Marshal(rs)
+
+	// in the gateway
+	rs, err := validator.LoadRules(data)
+	var doc map[string]interface{}
+	json.Unmarshal(body, &doc)
+	err = rs.Validate(doc) // err: validator.ErrorMap{"lines[1].sku": {validator.ErrZeroValue}}
+
+A RuleSet is a schema of its own, decoupled from Go types: it can also be
+built with NewRuleSet and SetField or converted from a JSON Schema with
+LoadJSONSchema, and validates structs of any type whose fields are keyed as
+in its rules as well as decoded maps, so that one definition of an entity
+serves its internal and external forms.
+
+	rs, err := validator.WithNameTag("json").LoadJSONSchema(schema)
+	err = rs.Validate(orderRow)  // a struct read from the database
+	err = rs.Validate(orderJSON) // a map decoded from a request
+
+Describe lists the constraints of the fields of a struct type in a form
+suited to generating HTML forms, whose attributes are given by Attrs.
+
+	ds, err := validator.WithNameTag("json").Describe(Signup{})
+	for _, d := range ds {
+		fmt.Println(d.Name, d.Attrs()) // username map[maxlength:20 minlength:3 required:]
+	}
+
+Each rule of a description tells where it was set, in the tag of the field
+or with SetFieldRules, and the alias it was expanded from, if any, to help
+find out why a field fails in a large code base.
+
+	fmt.Println(d.Rules[1]) // {min 0 tag pagelimit}
+
+Doc renders the same constraints as a table of fields, types, rules and
+the messages of the errors they may report, for API documentation and
+runbooks. The errors of custom validation functions are recorded with
+SetRuleErrors.
+
+	d, err := validator.Doc(Signup{})
+	fmt.Print(d.Markdown()) // or d.HTML()
+
+The validator command prints such tables from source, for types a program
+cannot load:
+
+	go run gopkg.in/validator.v2/cmd/validator doc -name json ./api Signup
+
+Rules no test exercises can be found by recording, such as from TestMain,
+the constraints a validator evaluates across a test suite:
+
+	cov := validator.NewCoverage()
+	validator.SetCoverage(cov)
+	code := m.Run()
+	fmt.Print(cov) // 41 of 43 constraints evaluated (95.3%), then the rules missed
+
+Package validatortest checks the rules of a type by generating values
+that satisfy them and values that violate each of them, in the style of
+testing/quick, and reporting those Validate disagrees about, such as the
+rules of a field that no value can satisfy:
+
+	func TestSignupRules(t *testing.T) {
+		validatortest.Check(t, nil, Signup{}, nil)
+	}
+
+Its Golden helper compares errors to a golden file in testdata, in a
+canonical form of a line per error sorted by key, so that changes to the
+errors reported are reviewed like any other change. Running the tests
+with -validatortest.update rewrites the golden files.
+
+	validatortest.Golden(t, validator.Validate(Signup{}))
+
+Containers the package does not know, such as ordered maps, sets or generic
+option types, are validated as opaque structs unless a function returning
+their elements is set with SetTraverseFunc. The elements are then validated
+like the items of slices, and their errors keyed under the given keys.
+
+	validator.SetTraverseFunc(orderedmap.Map{}, func(v reflect.Value) []validator.Element {
+		m := v.Interface().(orderedmap.Map)
+		var es []validator.Element
+		for _, k := range m.Keys() {
+			es = append(es, validator.Element{Key: "[" + k + "]", Value: reflect.ValueOf(m.Get(k))})
+		}
+		return es
+	})
+
+Wrappers holding a value or nothing, such as generic Optional[T] and
+Nullable[T] types or sql.NullString, are unwrapped by the function set
+with SetWrapper for all the instantiations of a generic type. For their
+fields nonzero means the field holds a value, and the other constraints
+apply to the value held, if any, with errors keyed under the field.
+
+	validator.SetWrapper(Optional[int]{}, func(v reflect.Value) (reflect.Value, bool) {
+		return v.FieldByName("Value"), v.FieldByName("Set").Bool()
+	})
+
+	type Profile struct {
+		Nickname Optional[string] `validate:"nonzero,min=3"`
+	}
+
+The protobuf well-known types used by generated messages are unwrapped
+without registering them: wrappers such as google.protobuf.StringValue
+hold their value, Timestamp a time.Time and Duration a time.Duration, whose
+min and max take durations such as 1h30m.
+
+	type CreateRideRequest struct {
+		Note     *wrapperspb.StringValue `validate:"max=140"`
+		PickupAt *timestamppb.Timestamp  `validate:"nonzero,after=now"`
+		Timeout  *durationpb.Duration    `validate:"max=1h"`
+	}
+
+Rules can also be set without tags with SetFieldRules, such as for the
+fields of anonymous struct types, which cannot be referred to by name but
+are reached by the path of Go field names from a named type holding them.
+
+	type Order struct {
+		Lines []struct {
+			SKU string
+		}
+	}
+	err := validator.SetFieldRules(Order{}, "Lines.SKU", "nonzero,max=12")
+
+Override does the same for the fields of types whose tags cannot be
+edited, such as generated clients or vendored models, but returns a
+derived validator holding the rules, leaving the one it is called on
+unchanged, so that they win over the tags only where it is used. Describe
+and ExportRules honor the rules set either way.
+
+	v, err := validator.Override(petstore.Pet{}, "Name", "min=3,max=40")
+	if err == nil {
+		err = v.Validate(pet)
+	}
+
+Rules tuned while a program runs, such as limits kept in a configuration
+file, can be read with Reload, which swaps them for those read before
+without disturbing the validations in flight. They are keyed by package
+path and type name and by the same paths, and win over tags and SetFieldRules.
+
+	signal.Notify(hup, syscall.SIGHUP)
+	for range hup {
+		f, _ := os.Open("rules.json") // {"example.com/shop/api.Order": {"Note": "max=140"}}
+		if err := validator.Reload(f); err != nil {
+			log.Print(err) // the rules read before are kept
+		}
+		f.Close()
+	}
+
+Messages meant for users can name fields by labels, set with SetFieldLabel
+or with the label tag, rather than by their keys. Check labels the errors
+it returns, which keep their keys in Field.
+
+	type Ride struct {
+		OriginLatitude float64 `json:"origin_latitude" validate:"min=-90,max=90"`
+	}
+	validator.SetFieldLabel("origin_latitude", "Pickup latitude")
+	for _, e := range validator.Check(ride).Errors() {
+		fmt.Println(e) // Pickup latitude: less than min
+	}
+
+Middleware
+
+Cross-cutting concerns, such as timing validations or limiting how deeply
+nested values are validated, can be added with Use, without changing any
+validation function. Middleware wraps the validation of every struct and is
+given its path and depth, and may skip it, call the next function with
+another context or return errors of its own.
+
+	validator.Use(func(next validator.StructFunc) validator.StructFunc {
+		return func(ctx context.Context, s validator.Struct) error {
+			if s.Depth > 10 {
+				return errTooDeep // reported under s.Path
+			}
+			return next(ctx, s)
+		}
+	})
+
+Stricter rules can be tried on production traffic before they are enforced
+by setting them, such as under another tag, as the shadow of a validator.
+Values are then validated with both rule sets, the errors of the active rules
+are returned as usual and the disagreements are reported to a function.
+Both rule sets run before Validate returns, each with its own configuration,
+so shadowing doubles the cost of validating.
+
+	type User struct {
+		Name string `validate:"nonzero" next:"nonzero,min=3"`
+	}
+
+	validator.SetShadow(validator.WithTag("next"), func(ctx context.Context, d validator.Disagreement) {
+		log.Printf("candidate rules would reject %v", d.Added)
+	})
+
+Small builds
+
+Building with the validator_minimal tag, such as for TinyGo or WASM, leaves
+out the pieces most programs do not need: the YAML decoder, the HTTP client
+of NewPwnedRange and the tables of the countries and locales packages. The
+tables can still be registered, all of them or those needed, since they
+convert to the types of this package.
+
+	for code, b := range countries.Bounds {
+		validator.RegisterCountry(code, validator.BoundingBox(b))
+	}
+	for locale, nf := range locales.NumberFormats {
+		validator.RegisterNumberFormat(locale, validator.NumberFormat(nf))
+	}
+
+Values built by the program itself, whose violations are bugs rather than
+bad input, can be checked with MustValidate, which panics with the errors
+found, or with Assert, which does so only in builds with the validator_debug
+tag, such as those of tests and staging, and does nothing in others.
+
+	go test -tags validator_debug ./...
+
 Multiple validators
 
 You may often need to have a different set of validation
@@ -261,6 +985,14 @@ You might use two different validators.
 		// save the new password
 	}
 
+Validators set up by different parts of a program, such as a library and
+the application using it, can be combined with Merge. The functions and
+aliases set on the second validator win over those of the first, while the
+builtin ones it did not set again are left as the first has them. Those
+the second has removed are removed from the result.
+
+	v := validator.Merge(payments.Validator(), appValidator)
+
 It is also possible to do all of that using only the default validator as long
 as SetTag is always called before calling validator.Validate() or you chain the
 with WithTag().
@@ -0,0 +1,252 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"html"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// TypeDoc is the table of constraints of a struct type, for inclusion
+// in API documentation and runbooks.
+type TypeDoc struct {
+	// Name is the name of the type.
+	Name string `json:"name"`
+	// Fields describes the fields of the type that have constraints.
+	Fields []FieldDescription `json:"fields"`
+}
+
+var (
+	ruleErrorsMu sync.RWMutex
+	// ruleErrors holds the errors returned by validation functions, by
+	// tag name.
+	ruleErrors = map[string][]error{
+		"nonzero":    {ErrZeroValue},
+		"nonnil":     {ErrZeroValue},
+		"len":        {ErrLen},
+		"min":        {ErrMin},
+		"max":        {ErrMax},
+		"regexp":     {ErrRegexp},
+		"enum":       {ErrEnum},
+		"flags":      {ErrFlags},
+		"acyclic":    {ErrDuplicate, ErrUnknownParent, ErrCycle},
+		"filter":     {ErrFilterSyntax, ErrFilterField, ErrFilterOperator, ErrFilterValue},
+		"implements": {ErrImplements},
+		"typeoneof":  {ErrType},
+		"minbytes":   {ErrMinBytes},
+		"maxbytes":   {ErrMaxBytes},
+		"before":     {ErrBefore},
+		"after":      {ErrAfter},
+
+		"eq_canonical": {ErrCanonical, ErrUnsupported},
+
+		"discriminates": {ErrType, ErrPayload},
+		"exclusive":     {ErrExclusive},
+
+		"acceptlanguage": {ErrAcceptLanguage},
+		"hostallow":      {ErrHostNotAllowed},
+		"idempotencykey": {ErrIdempotencyKey},
+		"mediatype":      {ErrMediaType},
+		"traceparent":    {ErrTraceparent},
+		"webhooksig":     {ErrSignatureHeader, ErrSignatureTimestamp},
+
+		"amount":   {ErrAmount, ErrCurrency, ErrPrecision, ErrMin, ErrMax},
+		"floatstr": {ErrNumber},
+		"intstr":   {ErrInteger},
+
+		"geoprecision": {ErrCoordinate, ErrPrecision},
+		"incountry":    {ErrCoordinate, ErrOutsideCountry},
+		"maxdistance":  {ErrMaxDistance},
+		"track":        {ErrCoordinate, ErrTrackOrder, ErrMaxSpeed},
+
+		"base64url":  {ErrBase64},
+		"minentropy": {ErrEntropy},
+		"notsimilar": {ErrSimilar},
+		"printable":  {ErrEncoding, ErrPrintable, ErrMax},
+	}
+)
+
+// SetRuleErrors records the errors returned by the validation function
+// registered under name, so that they are listed by Doc. The errors of
+// the builtin validation functions are already known.
+func SetRuleErrors(name string, errs ...error) {
+	ruleErrorsMu.Lock()
+	ruleErrors[name] = errs
+	ruleErrorsMu.Unlock()
+}
+
+// RuleErrors returns the errors that the validation function registered
+// under name may return, or nil if they are not known. Errors about bad
+// parameters and unsupported types, which any function may return, are
+// not listed.
+func RuleErrors(name string) []error {
+	ruleErrorsMu.RLock()
+	defer ruleErrorsMu.RUnlock()
+	return ruleErrors[name]
+}
+
+// Doc returns the constraints table of the type of v using the default
+// validator. See Validator.Doc.
+func Doc(v interface{}) (TypeDoc, error) {
+	return defaultValidator.Doc(v)
+}
+
+// Doc returns the constraints table of the struct type of v, a struct or
+// a pointer to one, as described by Describe.
+func (mv *Validator) Doc(v interface{}) (TypeDoc, error) {
+	ds, err := mv.Describe(v)
+	if err != nil {
+		return TypeDoc{}, err
+	}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return TypeDoc{Name: t.Name(), Fields: ds}, nil
+}
+
+// DescribeTag describes a field from its source using the default
+// validator. See Validator.DescribeTag.
+func DescribeTag(name, typ string, tag reflect.StructTag) (FieldDescription, error) {
+	return defaultValidator.DescribeTag(name, typ, tag)
+}
+
+// DescribeTag describes a field from its Go source rather than from a
+// value: name is the Go name of the field, typ its type as written in
+// the source, such as "*int" or "[]string", and tag its whole struct
+// tag. It lets tools that read source, such as the validator command,
+// describe types they cannot load. The description has no rules if the
+// tag has no constraints.
+func (mv *Validator) DescribeTag(name, typ string, tag reflect.StructTag) (FieldDescription, error) {
+	key := mv.fieldName(reflect.StructField{Name: name, Tag: tag})
+	t := tag.Get(mv.tagName)
+	if t == "" || t == "-" {
+		return FieldDescription{Name: key, Type: typ}, nil
+	}
+	tags, err := mv.parseTags(t)
+	if err != nil {
+		return FieldDescription{}, err
+	}
+	return describeField(key, typ, sourceKind(typ), SourceTag, tags), nil
+}
+
+// sourceKind returns the kind of the type written typ in Go source, once
+// pointers are dereferenced. Named types other than the predeclared
+// ones are reported as structs.
+func sourceKind(typ string) reflect.Kind {
+	typ = strings.TrimLeft(typ, "*")
+	switch {
+	case strings.HasPrefix(typ, "[]"):
+		return reflect.Slice
+	case strings.HasPrefix(typ, "["):
+		return reflect.Array
+	case strings.HasPrefix(typ, "map["):
+		return reflect.Map
+	}
+	switch typ {
+	case "string":
+		return reflect.String
+	case "bool":
+		return reflect.Bool
+	case "int", "int8", "int16", "int32", "int64", "rune":
+		return reflect.Int64
+	case "uint", "uint8", "uint16", "uint32", "uint64", "uintptr", "byte":
+		return reflect.Uint64
+	case "float32", "float64":
+		return reflect.Float64
+	case "interface{}", "any":
+		return reflect.Interface
+	}
+	return reflect.Struct
+}
+
+// String returns the table in Markdown.
+func (d TypeDoc) String() string {
+	return d.Markdown()
+}
+
+// Markdown returns the table as a Markdown section headed by the name of
+// the type, with a row per field listing its type, its rules and the
+// messages of the errors they may report.
+func (d TypeDoc) Markdown() string {
+	var b strings.Builder
+	b.WriteString("### " + d.Name + "\n\n")
+	b.WriteString("| Field | Type | Rules | Errors |\n")
+	b.WriteString("|-------|------|-------|--------|\n")
+	for _, f := range d.Fields {
+		b.WriteString("| " + markdownCell(f.Name))
+		b.WriteString(" | " + markdownCell(f.Type))
+		b.WriteString(" | " + markdownCell(rulesText(f.Rules)))
+		b.WriteString(" | " + markdownCell(strings.Join(errorMessages(f.Rules), "; ")))
+		b.WriteString(" |\n")
+	}
+	return b.String()
+}
+
+// HTML returns the table as an HTML table captioned with the name of the
+// type, with the same columns as Markdown.
+func (d TypeDoc) HTML() string {
+	var b strings.Builder
+	b.WriteString("<table>\n<caption>" + html.EscapeString(d.Name) + "</caption>\n")
+	b.WriteString("<tr><th>Field</th><th>Type</th><th>Rules</th><th>Errors</th></tr>\n")
+	for _, f := range d.Fields {
+		b.WriteString("<tr><td>" + html.EscapeString(f.Name))
+		b.WriteString("</td><td>" + html.EscapeString(f.Type))
+		b.WriteString("</td><td>" + html.EscapeString(rulesText(f.Rules)))
+		b.WriteString("</td><td>" + html.EscapeString(strings.Join(errorMessages(f.Rules), "; ")))
+		b.WriteString("</td></tr>\n")
+	}
+	b.WriteString("</table>\n")
+	return b.String()
+}
+
+// rulesText returns rules the way they are written in tags.
+func rulesText(rules []Rule) string {
+	s := make([]string, len(rules))
+	for i, r := range rules {
+		s[i] = r.Name
+		if r.Param != "" {
+			s[i] += "=" + r.Param
+		}
+	}
+	return strings.Join(s, ", ")
+}
+
+// errorMessages returns the messages of the errors rules may report,
+// without duplicates.
+func errorMessages(rules []Rule) []string {
+	var msgs []string
+	seen := map[string]bool{}
+	for _, r := range rules {
+		for _, err := range RuleErrors(r.Name) {
+			if msg := err.Error(); !seen[msg] {
+				seen[msg] = true
+				msgs = append(msgs, msg)
+			}
+		}
+	}
+	return msgs
+}
+
+// markdownCell escapes s for a cell of a Markdown table.
+func markdownCell(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, "|", `\|`, -1)
+	return strings.Replace(s, "\n", " ", -1)
+}
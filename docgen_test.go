@@ -0,0 +1,76 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"errors"
+	"reflect"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type DocAccount struct {
+	Name  string `validate:"nonzero,max=40"`
+	Plan  string `validate:"regexp=^(free|pro)$"`
+	Quota int    `validate:"pagelimit"`
+	Notes string
+}
+
+func (ms *MySuite) TestDoc(c *C) {
+	d, err := validator.Doc(&DocAccount{})
+	c.Assert(err, IsNil)
+	c.Assert(d.Name, Equals, "DocAccount")
+	c.Assert(d.Fields, HasLen, 3)
+	c.Assert(d.Markdown(), Equals, "### DocAccount\n\n"+
+		"| Field | Type | Rules | Errors |\n"+
+		"|-------|------|-------|--------|\n"+
+		"| Name | string | nonzero, max=40 | zero value; greater than max |\n"+
+		"| Plan | string | regexp=^(free\\|pro)$ | regular expression mismatch |\n"+
+		"| Quota | int | min=0, max=100 | less than min; greater than max |\n")
+	c.Assert(d.String(), Equals, d.Markdown())
+	c.Assert(d.HTML(), Matches, `(?s)<table>\n<caption>DocAccount</caption>\n.*<tr><td>Name</td><td>string</td><td>nonzero, max=40</td><td>zero value; greater than max</td></tr>\n.*</table>\n`)
+
+	errCode := errors.New("invalid code")
+	validator.SetRuleErrors("doccode", errCode)
+	c.Assert(validator.RuleErrors("doccode"), DeepEquals, []error{errCode})
+	c.Assert(validator.RuleErrors("nosuchrule"), IsNil)
+
+	_, err = validator.Doc(1)
+	c.Assert(err, Equals, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestDescribeTag(c *C) {
+	mv := validator.NewValidator().WithNameTag("json")
+	d, err := mv.DescribeTag("Tags", "[]string", reflect.StructTag(`json:"tags" validate:"nonzero,max=5"`))
+	c.Assert(err, IsNil)
+	c.Assert(d.Name, Equals, "tags")
+	c.Assert(d.Required, Equals, true)
+	c.Assert(*d.MaxLength, Equals, int64(5))
+
+	d, err = mv.DescribeTag("Age", "*int", reflect.StructTag(`validate:"min=13"`))
+	c.Assert(err, IsNil)
+	c.Assert(d.Name, Equals, "Age")
+	c.Assert(*d.Min, Equals, float64(13))
+
+	d, err = mv.DescribeTag("Bio", "string", reflect.StructTag(`json:"bio"`))
+	c.Assert(err, IsNil)
+	c.Assert(d.Rules, HasLen, 0)
+
+	_, err = mv.DescribeTag("Bad", "string", reflect.StructTag(`validate:"nosuchtag"`))
+	c.Assert(err, Equals, validator.ErrUnknownTag)
+}
@@ -0,0 +1,56 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// PositionErr is a validation error located in a source document.
+type PositionErr struct {
+	Err    error
+	Line   int
+	Column int
+}
+
+// Error implements the error interface.
+func (e PositionErr) Error() string {
+	return fmt.Sprintf("%s (line %d, column %d)", e.Err.Error(), e.Line, e.Column)
+}
+
+// Unwrap returns the located error.
+func (e PositionErr) Unwrap() error {
+	return e.Err
+}
+
+// ValidateXML decodes an XML document into v and validates it using
+// the default validator. See Validator.ValidateXML.
+func ValidateXML(data []byte, v interface{}) error {
+	return defaultValidator.ValidateXML(data, v)
+}
+
+// ValidateXML decodes an XML document into v, which must be a pointer
+// as for xml.Unmarshal, and validates it. Errors are keyed by the names
+// given in xml struct tags, so that they match the elements and
+// attributes of the document. Decoding errors are returned as is.
+func (mv *Validator) ValidateXML(data []byte, v interface{}) error {
+	if err := xml.Unmarshal(data, v); err != nil {
+		return err
+	}
+	return mv.WithNameTag("xml").Validate(v)
+}
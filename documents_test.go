@@ -0,0 +1,130 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"encoding/xml"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type xmlOrder struct {
+	XMLName  xml.Name  `xml:"urn:orders order"`
+	ID       string    `xml:"id,attr" validate:"nonzero"`
+	Customer string    `xml:"customer>name" validate:"min=2"`
+	Items    []xmlItem `xml:"items>item" validate:"min=1"`
+	Note     string    `xml:",chardata" validate:"max=10"`
+}
+
+type xmlItem struct {
+	SKU      string `xml:"sku" validate:"nonzero"`
+	Quantity int    `xml:"urn:orders qty" validate:"min=1"`
+}
+
+func (ms *MySuite) TestValidateXML(c *C) {
+	var order xmlOrder
+	err := validator.ValidateXML([]byte(`<order xmlns="urn:orders" id="42">
+		<customer><name>Jane</name></customer>
+		<items><item><sku>A1</sku><qty>2</qty></item></items>
+	</order>`), &order)
+	c.Assert(err, IsNil)
+	c.Assert(order.ID, Equals, "42")
+	c.Assert(order.Items, HasLen, 1)
+
+	order = xmlOrder{}
+	err = validator.ValidateXML([]byte(`<order xmlns="urn:orders">
+		<customer><name>J</name></customer>
+		<items><item><qty>0</qty></item></items>
+		long enough text
+	</order>`), &order)
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true, Commentf("%v", err))
+	c.Assert(errs, HasLen, 5, Commentf("%v", errs))
+	c.Assert(errs["id"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["customer.name"], HasError, validator.ErrMin)
+	c.Assert(errs["items.item[0].sku"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["items.item[0].qty"], HasError, validator.ErrMin)
+	c.Assert(errs["Note"], HasError, validator.ErrMax)
+
+	err = validator.ValidateXML([]byte(`<order`), &order)
+	c.Assert(err, FitsTypeOf, &xml.SyntaxError{})
+}
+
+func (ms *MySuite) TestNameTag(c *C) {
+	type T struct {
+		A string `json:"a" xml:"x>a" validate:"nonzero"`
+		B string `json:"b" validate:"nonzero"`
+	}
+	err := validator.WithNameTag("xml").Validate(T{})
+	c.Assert(err, NotNil)
+	errs := err.(validator.ErrorMap)
+	c.Assert(errs["x.a"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["B"], HasError, validator.ErrZeroValue)
+
+	err = validator.WithNameTag("xml").WithPrintJSON(true).Validate(T{})
+	c.Assert(err.(validator.ErrorMap)["a"], HasError, validator.ErrZeroValue)
+	err = validator.WithNameTag("json").WithNameTag("").Validate(T{})
+	c.Assert(err.(validator.ErrorMap)["A"], HasError, validator.ErrZeroValue)
+}
+
+type protoProfile struct {
+	DisplayName string `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty" validate:"nonzero"`
+	Age         int32  `protobuf:"varint,2,opt,name=age,proto3" json:"age,omitempty" validate:"min=13"`
+	// Types that are assignable to Contact:
+	//	*protoProfile_PhoneNumber
+	Contact isProtoProfileContact `protobuf_oneof:"contact"`
+	Note    string                `validate:"max=3"`
+}
+
+type isProtoProfileContact interface{ isProtoProfileContact() }
+
+type protoProfile_PhoneNumber struct {
+	PhoneNumber string `protobuf:"bytes,3,opt,name=phone_number,json=phoneNumber,proto3,oneof" validate:"min=5"`
+}
+
+func (*protoProfile_PhoneNumber) isProtoProfileContact() {}
+
+func (ms *MySuite) TestMessageNameTags(c *C) {
+	p := protoProfile{
+		Age:     9,
+		Contact: &protoProfile_PhoneNumber{PhoneNumber: "123"},
+		Note:    "long",
+	}
+	err := validator.WithNameTag("protobuf").Validate(p)
+	c.Assert(err, NotNil)
+	errs := err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 4, Commentf("%v", errs))
+	c.Assert(errs["displayName"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["age"], HasError, validator.ErrMin)
+	c.Assert(errs["phoneNumber"], HasError, validator.ErrMin)
+	c.Assert(errs["Note"], HasError, validator.ErrMax)
+
+	type packed struct {
+		ID    string `msgpack:"id" validate:"nonzero"`
+		Label string `msgpack:"label,omitempty" validate:"nonzero"`
+		Raw   string `validate:"nonzero"`
+	}
+	err = validator.WithNameTag("msgpack").Validate(packed{})
+	c.Assert(err, NotNil)
+	errs = err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 3, Commentf("%v", errs))
+	c.Assert(errs["id"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["label"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["Raw"], HasError, validator.ErrZeroValue)
+}
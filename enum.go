@@ -0,0 +1,301 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"sync"
+)
+
+var (
+	// ErrEnum is the error returned when a value is not one of
+	// the values of the enumeration specified
+	ErrEnum = TextErr{errors.New("not an enumerated value")}
+	// ErrFlags is the error returned when a bitmask has bits set
+	// other than the ones of the flags specified
+	ErrFlags = TextErr{errors.New("unknown flags set")}
+)
+
+// enumSet holds the values of a registered enumeration.
+type enumSet struct {
+	// typ is the type of the enumeration's values.
+	typ reflect.Type
+	// values holds the values themselves.
+	values map[interface{}]bool
+	// strings holds the string forms of the values: the underlying
+	// value of string enumerations and the result of the String
+	// method of fmt.Stringer values.
+	strings map[string]bool
+	// ints holds the underlying value of integer enumerations.
+	ints map[int64]bool
+}
+
+var (
+	enumsMu sync.RWMutex
+	enums   = map[string]*enumSet{}
+)
+
+// RegisterEnum registers the given values under name so that fields
+// can be validated against them with enum=name. Fields of type T must
+// hold one of the values, while string and integer fields, such as the
+// ones decoded from JSON, must hold the underlying value or, if T is a
+// fmt.Stringer, the string form of one of the values.
+func RegisterEnum[T comparable](name string, values ...T) error {
+	if name == "" {
+		return errors.New("name cannot be empty")
+	}
+	es := &enumSet{
+		typ:     reflect.TypeOf((*T)(nil)).Elem(),
+		values:  map[interface{}]bool{},
+		strings: map[string]bool{},
+		ints:    map[int64]bool{},
+	}
+	for _, v := range values {
+		var val interface{} = v
+		if s, ok := val.(fmt.Stringer); ok {
+			es.strings[s.String()] = true
+		}
+		if e, ok := val.(interface{ enumValue() interface{} }); ok {
+			val = e.enumValue()
+			es.typ = reflect.TypeOf(val)
+		}
+		es.values[val] = true
+		rv := reflect.ValueOf(val)
+		switch rv.Kind() {
+		case reflect.String:
+			es.strings[rv.String()] = true
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			es.ints[rv.Int()] = true
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if rv.Uint() > math.MaxInt64 {
+				return fmt.Errorf("enum value %v out of range", v)
+			}
+			es.ints[int64(rv.Uint())] = true
+		}
+	}
+	enumsMu.Lock()
+	enums[name] = es
+	enumsMu.Unlock()
+	return nil
+}
+
+// Integer is the set of integer types, which is the
+// set of underlying types of integer enumerations.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// RegisterEnumNames registers the integer enumeration described by
+// names, which maps each value to its name, so that fields can be
+// validated against it with enum=name. Integer fields must hold one of
+// the values and string fields one of the names. It accepts the name
+// maps of protobuf generated enums, such as RideState_name.
+func RegisterEnumNames[T Integer](name string, names map[T]string) error {
+	values := make([]enumName[T], 0, len(names))
+	for v, n := range names {
+		values = append(values, enumName[T]{v, n})
+	}
+	return RegisterEnum(name, values...)
+}
+
+// enumName is a value of an integer enumeration along with its name,
+// which is registered as the underlying value and string form.
+type enumName[T Integer] struct {
+	value T
+	name  string
+}
+
+// enumValue returns the value to register.
+func (e enumName[T]) enumValue() interface{} {
+	return e.value
+}
+
+// String implements fmt.Stringer.
+func (e enumName[T]) String() string {
+	return e.name
+}
+
+// contains reports whether v is one of the values of the enumeration.
+func (es *enumSet) contains(v reflect.Value) (bool, error) {
+	if v.Type() == es.typ {
+		return es.values[v.Interface()], nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return es.strings[v.String()], nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return es.ints[v.Int()], nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() <= math.MaxInt64 && es.ints[int64(v.Uint())], nil
+	}
+	return false, ErrUnsupported
+}
+
+// protoEnumDeclared reports whether v, a value of a protobuf generated
+// enum type, is one of the values declared in the enum's descriptor,
+// that is whether v.Descriptor().Values().ByNumber(v) is not nil. The
+// second result is false if v does not have such methods.
+func protoEnumDeclared(v reflect.Value) (declared, ok bool) {
+	if v.Kind() != reflect.Int32 {
+		return false, false
+	}
+	d, ok := callMethod(v, "Descriptor")
+	if !ok || d.Kind() == reflect.Interface && d.IsNil() {
+		return false, false
+	}
+	values, ok := callMethod(d, "Values")
+	if !ok || values.Kind() == reflect.Interface && values.IsNil() {
+		return false, false
+	}
+	value, ok := callMethod(values, "ByNumber", reflect.ValueOf(v.Int()))
+	if !ok {
+		return false, false
+	}
+	switch value.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		return !value.IsNil(), true
+	}
+	return true, true
+}
+
+// callMethod calls the method of v with the given name, which must
+// return one value, converting args to the types of its parameters. It
+// returns false if v has no such method.
+func callMethod(v reflect.Value, method string, args ...reflect.Value) (reflect.Value, bool) {
+	m := v.MethodByName(method)
+	if !m.IsValid() || m.Type().NumIn() != len(args) || m.Type().NumOut() != 1 {
+		return reflect.Value{}, false
+	}
+	for i, a := range args {
+		if !a.Type().ConvertibleTo(m.Type().In(i)) {
+			return reflect.Value{}, false
+		}
+		args[i] = a.Convert(m.Type().In(i))
+	}
+	return m.Call(args)[0], true
+}
+
+// enum is the builtin validation function that checks whether
+// a value is one of the values registered with RegisterEnum or,
+// when no name is given, one of the values declared by its
+// protobuf generated enum type.
+func enum(v interface{}, param string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return ErrUnsupported
+	}
+	if param == "" {
+		declared, ok := protoEnumDeclared(rv)
+		if !ok {
+			return ErrBadParameter
+		}
+		if !declared {
+			return ErrEnum
+		}
+		return nil
+	}
+	enumsMu.RLock()
+	es, ok := enums[param]
+	enumsMu.RUnlock()
+	if !ok {
+		return ErrBadParameter
+	}
+	found, err := es.contains(rv)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrEnum
+	}
+	return nil
+}
+
+var (
+	flagsMu sync.RWMutex
+	flags   = map[string]uint64{}
+)
+
+// RegisterFlags registers the bits of each named flag, so that they can
+// be referred to by name when validating bitmasks with flags. A flag may
+// be made of several bits.
+func RegisterFlags[T Integer](bits map[string]T) error {
+	for name, b := range bits {
+		if name == "" || b == 0 {
+			return fmt.Errorf("invalid flag %q", name)
+		}
+	}
+	flagsMu.Lock()
+	defer flagsMu.Unlock()
+	for name, b := range bits {
+		flags[name] = uint64(b)
+	}
+	return nil
+}
+
+// flagmask is the builtin validation function that checks whether an
+// integer bitmask only has bits of the given flags set. Flags are given
+// by their registered names or as integers: flags=read write 0x8.
+func flagmask(v interface{}, param string) error {
+	var mask uint64
+	flagsMu.RLock()
+	for _, name := range splitParams(param) {
+		b, ok := flags[name]
+		if !ok {
+			var err error
+			if b, err = asUint(name); err != nil {
+				flagsMu.RUnlock()
+				return ErrBadParameter
+			}
+		}
+		mask |= b
+	}
+	flagsMu.RUnlock()
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	var bits uint64
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Int() < 0 {
+			return ErrFlags
+		}
+		bits = uint64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		bits = rv.Uint()
+	default:
+		return ErrUnsupported
+	}
+	if bits&^mask != 0 {
+		return ErrFlags
+	}
+	return nil
+}
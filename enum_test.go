@@ -0,0 +1,219 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type RideState string
+
+const (
+	RideStatePending  RideState = "pending"
+	RideStateOngoing  RideState = "ongoing"
+	RideStateFinished RideState = "finished"
+)
+
+type Weekday int
+
+const (
+	Monday Weekday = iota + 1
+	Tuesday
+)
+
+func (d Weekday) String() string {
+	return [...]string{"", "monday", "tuesday"}[d]
+}
+
+func init() {
+	if err := validator.RegisterEnum("ride_state", RideStatePending, RideStateOngoing, RideStateFinished); err != nil {
+		panic(err)
+	}
+	if err := validator.RegisterEnum("weekday", Monday, Tuesday); err != nil {
+		panic(err)
+	}
+}
+
+func (ms *MySuite) TestEnum(c *C) {
+	type test struct {
+		State    RideState  `validate:"enum=ride_state"`
+		StateStr string     `validate:"enum=ride_state"`
+		StatePtr *RideState `validate:"enum=ride_state"`
+		Day      Weekday    `validate:"enum=weekday"`
+		DayName  string     `validate:"enum=weekday"`
+		DayNum   uint8      `validate:"enum=weekday"`
+	}
+	err := validator.Validate(test{
+		State:    RideStateOngoing,
+		StateStr: "finished",
+		Day:      Tuesday,
+		DayName:  "monday",
+		DayNum:   2,
+	})
+	c.Assert(err, IsNil)
+
+	bad := RideState("lost")
+	err = validator.Validate(test{
+		State:    "",
+		StateStr: "Pending",
+		StatePtr: &bad,
+		Day:      Weekday(3),
+		DayName:  "1",
+		DayNum:   0,
+	})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 6)
+	for _, f := range []string{"State", "StateStr", "StatePtr", "Day", "DayName", "DayNum"} {
+		c.Assert(errs[f], HasError, validator.ErrEnum)
+	}
+
+	err = validator.Valid(1.5, "enum=weekday")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrUnsupported)
+	err = validator.Valid("monday", "enum=month")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+}
+
+// ProtoState mimics a protobuf generated enum type.
+type ProtoState int32
+
+const (
+	ProtoState_UNSPECIFIED ProtoState = 0
+	ProtoState_ACTIVE      ProtoState = 1
+	ProtoState_CLOSED      ProtoState = 2
+)
+
+var (
+	ProtoState_name = map[int32]string{
+		0: "UNSPECIFIED",
+		1: "ACTIVE",
+		2: "CLOSED",
+	}
+)
+
+// protoDescriptor mimics the protoreflect.EnumDescriptor interfaces.
+type protoDescriptor struct{}
+
+func (protoDescriptor) Values() protoDescriptor { return protoDescriptor{} }
+
+func (protoDescriptor) ByNumber(n int32) *string {
+	if name, ok := ProtoState_name[n]; ok {
+		return &name
+	}
+	return nil
+}
+
+func (ProtoState) Descriptor() protoDescriptor { return protoDescriptor{} }
+
+func init() {
+	if err := validator.RegisterEnumNames("proto_state", ProtoState_name); err != nil {
+		panic(err)
+	}
+}
+
+func (ms *MySuite) TestIntegerEnum(c *C) {
+	type test struct {
+		State     ProtoState `validate:"enum=proto_state"`
+		Specified ProtoState `validate:"enum=proto_state,nonzero"`
+		Known     ProtoState `validate:"enum"`
+		Raw       int32      `validate:"enum=proto_state"`
+		Name      string     `validate:"enum=proto_state"`
+	}
+	err := validator.Validate(test{
+		Specified: ProtoState_ACTIVE,
+		Known:     ProtoState_CLOSED,
+		Raw:       2,
+		Name:      "CLOSED",
+	})
+	c.Assert(err, IsNil)
+
+	err = validator.Validate(test{
+		State: ProtoState(7),
+		Known: ProtoState(7),
+		Raw:   3,
+		Name:  "closed",
+	})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 5)
+	c.Assert(errs["State"], HasError, validator.ErrEnum)
+	c.Assert(errs["Specified"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["Known"], HasError, validator.ErrEnum)
+	c.Assert(errs["Raw"], HasError, validator.ErrEnum)
+	c.Assert(errs["Name"], HasError, validator.ErrEnum)
+
+	err = validator.Valid(Weekday(1), "enum")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+}
+
+type Permission uint8
+
+const (
+	PermRead Permission = 1 << iota
+	PermWrite
+	PermAdmin
+	PermDelete
+)
+
+func init() {
+	if err := validator.RegisterFlags(map[string]Permission{
+		"read":  PermRead,
+		"write": PermWrite,
+		"admin": PermAdmin,
+	}); err != nil {
+		panic(err)
+	}
+}
+
+func (ms *MySuite) TestFlags(c *C) {
+	type test struct {
+		Perms    Permission  `validate:"flags=read write admin"`
+		NonEmpty Permission  `validate:"flags=read write,nonzero"`
+		Numeric  int         `validate:"flags=1 2 0x8"`
+		Ptr      *Permission `validate:"flags=read"`
+	}
+	err := validator.Validate(test{
+		Perms:    PermRead | PermAdmin,
+		NonEmpty: PermWrite,
+		Numeric:  9,
+	})
+	c.Assert(err, IsNil)
+
+	p := PermWrite
+	err = validator.Validate(test{
+		Perms:   PermRead | PermDelete,
+		Numeric: -1,
+		Ptr:     &p,
+	})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 4)
+	c.Assert(errs["Perms"], HasError, validator.ErrFlags)
+	c.Assert(errs["NonEmpty"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["Numeric"], HasError, validator.ErrFlags)
+	c.Assert(errs["Ptr"], HasError, validator.ErrFlags)
+
+	err = validator.Valid(1, "flags=read execute")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+	err = validator.Valid("read", "flags=read")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrUnsupported)
+	c.Assert(validator.RegisterFlags(map[string]int{"none": 0}), NotNil)
+}
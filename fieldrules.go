@@ -0,0 +1,155 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SetFieldRules calls the SetFieldRules method on the default validator.
+func SetFieldRules(typ interface{}, path, rules string) error {
+	return setDefault(func() error {
+		return defaultValidator.SetFieldRules(typ, path, rules)
+	})
+}
+
+// SetFieldRules sets the rules of the field found at path from the
+// struct type of typ, used instead of the rules of its tag. Path is made
+// of the Go names of fields separated by dots, and goes through
+// pointers, slices, arrays and maps, so that the fields of anonymous
+// struct types, which cannot be referred to by name, can be reached
+// from a named type holding them:
+//
+//	type Order struct {
+//		Lines []struct {
+//			SKU string
+//		}
+//	}
+//	err := validator.SetFieldRules(Order{}, "Lines.SKU", "nonzero,max=12")
+//
+// Rules set to "-" disable the validation of the field, and empty rules
+// restore the rules of its tag. It returns ErrUnknownTag if the rules
+// use unknown validation functions.
+func (mv *Validator) SetFieldRules(typ interface{}, path, rules string) error {
+	t, name, err := fieldAt(reflect.TypeOf(typ), path)
+	if err != nil {
+		return err
+	}
+	if rules != "" && rules != "-" {
+		if _, err := mv.parseTags(rules); err != nil {
+			return err
+		}
+	}
+	mv.own()
+	// the rules of a type are replaced, never changed, as copies of mv
+	// share them
+	fields := map[string]string{}
+	for k, r := range mv.fieldRules[t] {
+		if k != name {
+			fields[k] = r
+		}
+	}
+	if rules != "" {
+		fields[name] = rules
+	}
+	if len(fields) == 0 {
+		delete(mv.fieldRules, t)
+		return nil
+	}
+	mv.fieldRules[t] = fields
+	return nil
+}
+
+// Override calls the Override method on the default validator.
+func Override(typ interface{}, path, rules string) (*Validator, error) {
+	return defaultValidator.Override(typ, path, rules)
+}
+
+// Override returns a validator derived from mv, as with WithOptions, in
+// which the rules of the field found at path from the struct type of typ
+// win over those of its tag, as if set with SetFieldRules. Unlike
+// SetFieldRules it leaves mv unchanged, so that the rules of a type
+// whose tags cannot be edited, such as one of generated code or of a
+// vendored package, can be changed for a single call site. The rules
+// are honored wherever those of tags are, such as by Describe and
+// ExportRules.
+//
+//	v, err := validator.Override(petstore.Pet{}, "Name", "min=3,max=40")
+func (mv *Validator) Override(typ interface{}, path, rules string) (*Validator, error) {
+	v := mv.WithOptions()
+	if err := v.SetFieldRules(typ, path, rules); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// fieldAt returns the struct type holding the field found at path from
+// the type t, and the name of the field.
+func fieldAt(t reflect.Type, path string) (reflect.Type, string, error) {
+	parts := strings.Split(path, ".")
+	for i, part := range parts {
+		for t != nil && t.Kind() != reflect.Struct {
+			switch t.Kind() {
+			case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+				t = t.Elem()
+			default:
+				t = nil
+			}
+		}
+		if t == nil {
+			return nil, "", errors.New("type must be a struct")
+		}
+		sf, ok := t.FieldByName(part)
+		if !ok || part == "_" {
+			return nil, "", fmt.Errorf("no field %s in %s", part, t)
+		}
+		if i == len(parts)-1 {
+			// promoted fields are held by embedded structs
+			for len(sf.Index) > 1 {
+				t = t.Field(sf.Index[0]).Type
+				for t.Kind() == reflect.Ptr {
+					t = t.Elem()
+				}
+				sf, _ = t.FieldByName(part)
+			}
+			return t, part, nil
+		}
+		t = sf.Type
+	}
+	return nil, "", errors.New("path cannot be empty")
+}
+
+// fieldTag returns the rules of the field sf of the struct type t, held
+// by a value of the named type owner: those read with Reload, if any, or
+// else those set with SetFieldRules, or else those of its tag.
+func (mv *Validator) fieldTag(owner, t reflect.Type, sf reflect.StructField) string {
+	return mv.ruleTag(mv.ruleFile(), owner, t, sf)
+}
+
+// ruleTag is fieldTag with the rules read with Reload given by rf.
+func (mv *Validator) ruleTag(rf *ruleFile, owner, t reflect.Type, sf reflect.StructField) string {
+	if rules, ok := rf.rules(ownerOf(owner, t), t, sf.Name); ok {
+		return rules
+	}
+	if rules, ok := mv.fieldRules[t][sf.Name]; ok {
+		return rules
+	}
+	return sf.Tag.Get(mv.tagName)
+}
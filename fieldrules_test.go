@@ -0,0 +1,119 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type anonymousOrder struct {
+	Lines []struct {
+		SKU     string `validate:"nonzero"`
+		Options map[string]*struct {
+			Qty int
+		}
+	}
+	Shipping struct {
+		Carrier string
+	}
+}
+
+func (ms *MySuite) TestSetFieldRules(c *C) {
+	var o anonymousOrder
+	o.Lines = make([]struct {
+		SKU     string `validate:"nonzero"`
+		Options map[string]*struct {
+			Qty int
+		}
+	}, 1)
+	o.Lines[0].Options = map[string]*struct{ Qty int }{"gift": {}}
+
+	mv := validator.NewValidator()
+	c.Assert(mv.Validate(o), DeepEquals, validator.ErrorMap{"Lines[0].SKU": {validator.ErrZeroValue}})
+
+	c.Assert(mv.SetFieldRules(anonymousOrder{}, "Lines.Options.Qty", "min=1"), IsNil)
+	c.Assert(mv.SetFieldRules(&anonymousOrder{}, "Shipping.Carrier", "nonzero"), IsNil)
+	c.Assert(mv.SetFieldRules(anonymousOrder{}, "Lines.SKU", "-"), IsNil)
+	c.Assert(mv.Validate(o), DeepEquals, validator.ErrorMap{
+		"Lines[0].Options[gift](value).Qty": {validator.ErrMin},
+		"Shipping.Carrier":                  {validator.ErrZeroValue},
+	})
+
+	ds, err := mv.Describe(o)
+	c.Assert(err, IsNil)
+	c.Assert(ds, HasLen, 1)
+	c.Assert(ds[0].Name, Equals, "Shipping.Carrier")
+
+	rs, err := mv.ExportRules(o)
+	c.Assert(err, IsNil)
+	c.Assert(rs.Types["validator_test.anonymousOrder.Lines"].Fields["Options"].Type, Equals, "validator_test.anonymousOrder.Lines.Options")
+	c.Assert(rs.Types["validator_test.anonymousOrder.Shipping"].Fields["Carrier"].Rules, Equals, "nonzero")
+
+	// empty rules restore those of the tags
+	c.Assert(mv.SetFieldRules(anonymousOrder{}, "Lines.SKU", ""), IsNil)
+	c.Assert(mv.SetFieldRules(anonymousOrder{}, "Lines.Options.Qty", ""), IsNil)
+	c.Assert(mv.SetFieldRules(anonymousOrder{}, "Shipping.Carrier", ""), IsNil)
+	c.Assert(mv.Validate(o), DeepEquals, validator.ErrorMap{"Lines[0].SKU": {validator.ErrZeroValue}})
+
+	c.Assert(mv.SetFieldRules(anonymousOrder{}, "Lines.Missing", "nonzero"), ErrorMatches, "no field Missing in .*")
+	c.Assert(mv.SetFieldRules(anonymousOrder{}, "Lines.SKU.Len", "nonzero"), ErrorMatches, "type must be a struct")
+	c.Assert(mv.SetFieldRules(anonymousOrder{}, "Lines.SKU", "nosuchtag"), Equals, validator.ErrUnknownTag)
+	c.Assert(validator.NewValidator().Validate(o), DeepEquals, validator.ErrorMap{"Lines[0].SKU": {validator.ErrZeroValue}})
+}
+
+// generatedPet stands for a type of generated code, whose tags cannot be
+// edited.
+type generatedPet struct {
+	Name string `json:"name,omitempty" validate:"max=100"`
+	Tag  string `json:"tag,omitempty"`
+}
+
+func (ms *MySuite) TestOverride(c *C) {
+	parent := validator.NewValidator()
+	parent.SetNameTag("json")
+	mv, err := parent.Override(generatedPet{}, "Name", "min=3,max=40")
+	c.Assert(err, IsNil)
+	mv, err = mv.Override(generatedPet{}, "Tag", "nonzero")
+	c.Assert(err, IsNil)
+	_, err = mv.Override(generatedPet{}, "Owner", "nonzero")
+	c.Assert(err, NotNil)
+
+	c.Assert(mv.Validate(generatedPet{Name: "Al"}), DeepEquals, validator.ErrorMap{
+		"name": {validator.ErrMin},
+		"tag":  {validator.ErrZeroValue},
+	})
+
+	ds, err := mv.Describe(generatedPet{})
+	c.Assert(err, IsNil)
+	c.Assert(ds, HasLen, 2)
+	c.Assert(*ds[0].MaxLength, Equals, int64(40))
+	c.Assert(ds[0].Rules[0].Source, Equals, validator.SourceFieldRules)
+
+	rs, err := mv.ExportRules(generatedPet{})
+	c.Assert(err, IsNil)
+	c.Assert(rs.Types[rs.Root].Fields["name"].Rules, Equals, "min=3,max=40")
+	c.Assert(rs.Types[rs.Root].Fields["tag"].Rules, Equals, "nonzero")
+
+	// the validators Override is called on are left unchanged
+	c.Assert(parent.Validate(generatedPet{Name: "Al"}), IsNil)
+	c.Assert(validator.Validate(generatedPet{Name: "Al"}), IsNil)
+	v, err := validator.Override(generatedPet{}, "Name", "min=3")
+	c.Assert(err, IsNil)
+	c.Assert(v.Validate(generatedPet{Name: "Al"}), NotNil)
+	c.Assert(validator.Validate(generatedPet{Name: "Al"}), IsNil)
+}
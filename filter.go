@@ -0,0 +1,320 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+var (
+	// ErrFilterSyntax is the error returned when a filter
+	// expression cannot be parsed
+	ErrFilterSyntax = TextErr{errors.New("invalid filter syntax")}
+	// ErrFilterField is the error returned when a filter expression
+	// refers to a field that is not allowed
+	ErrFilterField = TextErr{errors.New("unknown filter field")}
+	// ErrFilterOperator is the error returned when a filter expression
+	// uses an operator that is not allowed for a field
+	ErrFilterOperator = TextErr{errors.New("filter operator not allowed")}
+	// ErrFilterValue is the error returned when a filter expression
+	// compares a field to a value of the wrong type
+	ErrFilterValue = TextErr{errors.New("invalid filter value")}
+)
+
+// FilterType is the type of the values a filter field is compared to.
+type FilterType int
+
+const (
+	// FilterString fields are compared to strings with =, != and ~.
+	FilterString FilterType = iota
+	// FilterNumber fields are compared to numbers with any operator but ~.
+	FilterNumber
+	// FilterBool fields are compared to true or false with = and !=.
+	FilterBool
+	// FilterTime fields are compared to RFC 3339 times with any
+	// operator but ~.
+	FilterTime
+)
+
+// defaultFilterOps holds the operators allowed for each FilterType.
+var defaultFilterOps = map[FilterType][]string{
+	FilterString: {"=", "!=", "~"},
+	FilterNumber: {"=", "!=", "<", "<=", ">", ">="},
+	FilterBool:   {"=", "!="},
+	FilterTime:   {"=", "!=", "<", "<=", ">", ">="},
+}
+
+// FilterField describes a field that may be used in filter expressions.
+type FilterField struct {
+	// Type is the type of the values the field is compared to.
+	Type FilterType
+	// Ops restricts the operators allowed for the field. The operators
+	// allowed for Type are used if it is empty.
+	Ops []string
+}
+
+// FilterSchema describes the fields that may be used in filter
+// expressions, indexed by their name.
+//
+// Filter expressions are made of comparisons of a field to a value,
+// such as status = "active" or age >= 18, combined with AND and OR and
+// grouped with parentheses. Operators are =, !=, <, <=, >, >= and ~
+// (contains). Values are numbers, true, false, and strings, which must
+// be double quoted if they hold anything other than letters, digits and
+// the characters _ . : + -.
+type FilterSchema map[string]FilterField
+
+var (
+	filtersMu sync.RWMutex
+	filters   = map[string]FilterSchema{}
+)
+
+// RegisterFilter registers the schema under name so that filter
+// expressions can be validated against it with filter=name.
+func RegisterFilter(name string, schema FilterSchema) error {
+	if name == "" {
+		return errors.New("name cannot be empty")
+	}
+	filtersMu.Lock()
+	filters[name] = schema
+	filtersMu.Unlock()
+	return nil
+}
+
+// Validate validates the filter expression against the schema. The
+// empty expression is valid.
+func (fs FilterSchema) Validate(expr string) error {
+	toks, err := tokenizeFilter(expr)
+	if err != nil {
+		return err
+	}
+	if len(toks) == 0 {
+		return nil
+	}
+	p := &filterParser{toks: toks, schema: fs}
+	if err := p.expr(); err != nil {
+		return err
+	}
+	if p.pos != len(p.toks) {
+		return ErrFilterSyntax
+	}
+	return nil
+}
+
+// filter is the builtin validation function that checks whether a
+// string is a filter expression valid against the schema registered
+// with RegisterFilter under the given name.
+func filter(v interface{}, param string) error {
+	filtersMu.RLock()
+	fs, ok := filters[param]
+	filtersMu.RUnlock()
+	if !ok {
+		return ErrBadParameter
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.String {
+		return ErrUnsupported
+	}
+	return fs.Validate(rv.String())
+}
+
+// filterTokenKind is the kind of a filterToken.
+type filterTokenKind int
+
+const (
+	filterWord filterTokenKind = iota
+	filterQuoted
+	filterOp
+	filterOpen
+	filterClose
+)
+
+// filterToken is a token of a filter expression.
+type filterToken struct {
+	kind filterTokenKind
+	text string
+}
+
+// isFilterWordRune reports whether r may be part of an unquoted word.
+func isFilterWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_.:+-", r)
+}
+
+// tokenizeFilter splits a filter expression into tokens.
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var toks []filterToken
+	rs := []rune(expr)
+	for i := 0; i < len(rs); {
+		r := rs[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			toks = append(toks, filterToken{filterOpen, "("})
+			i++
+		case r == ')':
+			toks = append(toks, filterToken{filterClose, ")"})
+			i++
+		case r == '"':
+			var b strings.Builder
+			i++
+			for ; i < len(rs) && rs[i] != '"'; i++ {
+				if rs[i] == '\\' && i+1 < len(rs) {
+					i++
+				}
+				b.WriteRune(rs[i])
+			}
+			if i == len(rs) {
+				return nil, ErrFilterSyntax
+			}
+			toks = append(toks, filterToken{filterQuoted, b.String()})
+			i++
+		case strings.ContainsRune("=!<>~", r):
+			op := string(r)
+			if i+1 < len(rs) && rs[i+1] == '=' && r != '=' && r != '~' {
+				op += "="
+			}
+			if op == "!" {
+				return nil, ErrFilterSyntax
+			}
+			toks = append(toks, filterToken{filterOp, op})
+			i += len(op)
+		case isFilterWordRune(r):
+			j := i
+			for j < len(rs) && isFilterWordRune(rs[j]) {
+				j++
+			}
+			toks = append(toks, filterToken{filterWord, string(rs[i:j])})
+			i = j
+		default:
+			return nil, ErrFilterSyntax
+		}
+	}
+	return toks, nil
+}
+
+// filterParser is a recursive descent parser of filter expressions.
+type filterParser struct {
+	toks   []filterToken
+	pos    int
+	schema FilterSchema
+}
+
+// next returns the next token, if any, and advances past it.
+func (p *filterParser) next() (filterToken, bool) {
+	if p.pos == len(p.toks) {
+		return filterToken{}, false
+	}
+	p.pos++
+	return p.toks[p.pos-1], true
+}
+
+// expr parses comparisons joined by AND or OR.
+func (p *filterParser) expr() error {
+	for {
+		if err := p.term(); err != nil {
+			return err
+		}
+		if p.pos == len(p.toks) {
+			return nil
+		}
+		t := p.toks[p.pos]
+		if t.kind != filterWord || (!strings.EqualFold(t.text, "and") && !strings.EqualFold(t.text, "or")) {
+			return nil
+		}
+		p.pos++
+	}
+}
+
+// term parses a comparison or a parenthesized expression.
+func (p *filterParser) term() error {
+	t, ok := p.next()
+	if !ok {
+		return ErrFilterSyntax
+	}
+	if t.kind == filterOpen {
+		if err := p.expr(); err != nil {
+			return err
+		}
+		if t, ok = p.next(); !ok || t.kind != filterClose {
+			return ErrFilterSyntax
+		}
+		return nil
+	}
+	if t.kind != filterWord {
+		return ErrFilterSyntax
+	}
+	op, ok := p.next()
+	if !ok || op.kind != filterOp {
+		return ErrFilterSyntax
+	}
+	val, ok := p.next()
+	if !ok || (val.kind != filterWord && val.kind != filterQuoted) {
+		return ErrFilterSyntax
+	}
+	return p.schema.check(t.text, op.text, val)
+}
+
+// check validates the comparison of the named field to val with op.
+func (fs FilterSchema) check(name, op string, val filterToken) error {
+	f, ok := fs[name]
+	if !ok {
+		return ErrFilterField
+	}
+	ops := f.Ops
+	if len(ops) == 0 {
+		ops = defaultFilterOps[f.Type]
+	}
+	allowed := false
+	for _, o := range ops {
+		allowed = allowed || o == op
+	}
+	if !allowed {
+		return ErrFilterOperator
+	}
+	var err error
+	switch f.Type {
+	case FilterNumber:
+		if val.kind == filterWord {
+			_, err = strconv.ParseFloat(val.text, 64)
+		} else {
+			err = ErrFilterValue
+		}
+	case FilterBool:
+		if val.kind != filterWord || (val.text != "true" && val.text != "false") {
+			err = ErrFilterValue
+		}
+	case FilterTime:
+		_, err = time.Parse(time.RFC3339, val.text)
+	}
+	if err != nil {
+		return ErrFilterValue
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+var userFilter = validator.FilterSchema{
+	"name":    {Type: validator.FilterString},
+	"status":  {Type: validator.FilterString, Ops: []string{"="}},
+	"age":     {Type: validator.FilterNumber},
+	"active":  {Type: validator.FilterBool},
+	"created": {Type: validator.FilterTime},
+}
+
+func init() {
+	if err := validator.RegisterFilter("users", userFilter); err != nil {
+		panic(err)
+	}
+}
+
+func (ms *MySuite) TestFilter(c *C) {
+	for _, expr := range []string{
+		``,
+		`name ~ "o'brien"`,
+		`age>=18 AND active = true`,
+		`(status = active OR status = "on hold") and created < 2024-01-02T15:04:05Z`,
+		`name = "say \"hi\"" OR (age < -1.5 AND (active != false))`,
+	} {
+		c.Assert(userFilter.Validate(expr), IsNil, Commentf("%s", expr))
+	}
+
+	for _, tc := range []struct {
+		expr string
+		err  error
+	}{
+		{`name`, validator.ErrFilterSyntax},
+		{`name = `, validator.ErrFilterSyntax},
+		{`name = "unterminated`, validator.ErrFilterSyntax},
+		{`(age > 1`, validator.ErrFilterSyntax},
+		{`age > 1 active = true`, validator.ErrFilterSyntax},
+		{`age > 1 AND`, validator.ErrFilterSyntax},
+		{`age ! 1`, validator.ErrFilterSyntax},
+		{`age; 1`, validator.ErrFilterSyntax},
+		{`email = "a@b.c"`, validator.ErrFilterField},
+		{`status != "active"`, validator.ErrFilterOperator},
+		{`age ~ 1`, validator.ErrFilterOperator},
+		{`age = "18"`, validator.ErrFilterValue},
+		{`age = eighteen`, validator.ErrFilterValue},
+		{`active = yes`, validator.ErrFilterValue},
+		{`created > yesterday`, validator.ErrFilterValue},
+	} {
+		c.Assert(userFilter.Validate(tc.expr), Equals, tc.err, Commentf("%s", tc.expr))
+	}
+
+	type search struct {
+		Filter *string `validate:"filter=users"`
+		Other  string  `validate:"filter=groups"`
+	}
+	expr := `age > "x"`
+	err := validator.Validate(search{Filter: &expr})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Filter"], HasError, validator.ErrFilterValue)
+	c.Assert(errs["Other"], HasError, validator.ErrBadParameter)
+}
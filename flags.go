@@ -0,0 +1,94 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"strings"
+)
+
+// FlagProvider reports whether the feature flag called name is enabled
+// for a validation, such as for the user or tenant found in ctx.
+type FlagProvider func(ctx context.Context, name string) bool
+
+// SetFlagProvider sets the flag provider of the default validator.
+// See Validator.SetFlagProvider.
+func SetFlagProvider(p FlagProvider) {
+	setDefault(func() error {
+		defaultValidator.SetFlagProvider(p)
+		return nil
+	})
+}
+
+// SetFlagProvider sets the provider asked, with the context of each
+// validation, whether the constraints gated by iff are enforced. With
+// no provider all flags are disabled.
+func (mv *Validator) SetFlagProvider(p FlagProvider) {
+	mv.flags = p
+}
+
+// iff is the builtin validation function that enforces the constraints
+// given after the name of a feature flag, such as "strict:min=8", only
+// when the flag is enabled. Commas between the constraints are escaped.
+func iff(v interface{}, f Field, param string) error {
+	i := strings.IndexByte(param, ':')
+	if i <= 0 || strings.TrimSpace(param[i+1:]) == "" {
+		return ErrBadParameter
+	}
+	mv := f.mv
+	if mv == nil {
+		mv = NewValidator()
+	}
+	rules := param[i+1:]
+	if _, err := mv.parseTags(rules); err != nil {
+		return err
+	}
+	if mv.flags == nil || !mv.flags(f.Context(), param[:i]) {
+		return nil
+	}
+	return mv.validateVar(v, f, rules)
+}
+
+// sample is the builtin validation function that enforces the
+// constraints given after a rate between 0 and 1, such as
+// "0.01:regexp=^[a-z]+$", on that fraction of the values only, so that
+// costly checks can be monitored without running on every request.
+// Commas between the constraints are escaped.
+func sample(v interface{}, f Field, param string) error {
+	i := strings.IndexByte(param, ':')
+	if i <= 0 || strings.TrimSpace(param[i+1:]) == "" {
+		return ErrBadParameter
+	}
+	rate, err := strconv.ParseFloat(strings.TrimSpace(param[:i]), 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return ErrBadParameter
+	}
+	mv := f.mv
+	if mv == nil {
+		mv = NewValidator()
+	}
+	rules := param[i+1:]
+	if _, err := mv.parseTags(rules); err != nil {
+		return err
+	}
+	if rate == 0 || rate < 1 && rand.Float64() >= rate {
+		return nil
+	}
+	return mv.validateVar(v, f, rules)
+}
@@ -0,0 +1,92 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestIff(c *C) {
+	type tenantKey struct{}
+	type user struct {
+		Name string `validate:"nonzero,iff=strict_names:min=3\\,max=5"`
+		Nick string `validate:"iff=strict_names:nonzero"`
+	}
+	v := validator.NewValidator()
+
+	// without a provider the flag is disabled
+	err := v.Validate(user{Name: "x"})
+	c.Assert(err, IsNil)
+
+	v.SetFlagProvider(func(ctx context.Context, name string) bool {
+		return name == "strict_names" && ctx.Value(tenantKey{}) == "beta"
+	})
+	err = v.ValidateContext(context.Background(), user{Name: "x"})
+	c.Assert(err, IsNil)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "beta")
+	err = v.ValidateContext(ctx, user{Name: "x"})
+	c.Assert(err, NotNil)
+	errs := err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Name"], DeepEquals, validator.ErrorArray{validator.ErrMin})
+	c.Assert(errs["Nick"], HasError, validator.ErrZeroValue)
+
+	err = v.ValidateContext(ctx, user{Name: "abcdef", Nick: "a"})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Name"], DeepEquals, validator.ErrorArray{validator.ErrMax})
+
+	err = v.ValidContext(ctx, "ab", "iff=strict_names:min=3")
+	c.Assert(err, HasError, validator.ErrMin)
+
+	// rules are checked even when the flag is disabled
+	err = v.Valid("ab", "iff=strict_names:nosuchtag")
+	c.Assert(err, HasError, validator.ErrUnknownTag)
+	err = v.Valid("ab", "iff=strict_names")
+	c.Assert(err, HasError, validator.ErrBadParameter)
+	err = v.Valid("ab", "iff=:min=3")
+	c.Assert(err, HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestSample(c *C) {
+	err := validator.Valid("ab", "sample=1:min=3")
+	c.Assert(err, HasError, validator.ErrMin)
+	err = validator.Valid("ab", "sample=0:min=3")
+	c.Assert(err, IsNil)
+
+	failed := 0
+	for i := 0; i < 1000; i++ {
+		if validator.Valid("ab", "nonzero,sample=0.5:min=3\\,max=1") != nil {
+			failed++
+		}
+	}
+	c.Assert(failed > 350 && failed < 650, Equals, true, Commentf("%d", failed))
+
+	// cheap checks always run
+	err = validator.Valid("", "nonzero,sample=0:min=3")
+	c.Assert(err, HasError, validator.ErrZeroValue)
+
+	for _, tag := range []string{"sample=2:min=3", "sample=x:min=3", "sample=0.5", "sample=:min=3"} {
+		err = validator.Valid("ab", tag)
+		c.Assert(err, HasError, validator.ErrBadParameter, Commentf(tag))
+	}
+	err = validator.Valid("ab", "sample=0:nosuchtag")
+	c.Assert(err, HasError, validator.ErrUnknownTag)
+}
@@ -0,0 +1,474 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+var (
+	// ErrCoordinate is the error returned when a value is not a
+	// valid coordinate
+	ErrCoordinate = TextErr{errors.New("invalid coordinate")}
+	// ErrMaxDistance is the error returned when two points are
+	// farther apart than the maximum distance specified
+	ErrMaxDistance = TextErr{errors.New("greater than max distance")}
+	// ErrTrackOrder is the error returned when the points of a track
+	// are not in strictly increasing time order
+	ErrTrackOrder = TextErr{errors.New("timestamps not increasing")}
+	// ErrMaxSpeed is the error returned when the speed implied by two
+	// consecutive points of a track is greater than the maximum specified
+	ErrMaxSpeed = TextErr{errors.New("greater than max speed")}
+	// ErrOutsideCountry is the error returned when coordinates do not
+	// fall within the country specified
+	ErrOutsideCountry = TextErr{errors.New("coordinates outside country")}
+)
+
+// geoprecision is the builtin validation function that checks whether
+// a latitude or longitude, given as a float or as a decimal string, has
+// at most param decimal places.
+func geoprecision(v interface{}, param string) error {
+	p, err := asInt(param)
+	if err != nil || p < 0 {
+		return ErrBadParameter
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	var s string
+	switch rv.Kind() {
+	case reflect.Float32:
+		s = strconv.FormatFloat(rv.Float(), 'f', -1, 32)
+	case reflect.Float64:
+		s = strconv.FormatFloat(rv.Float(), 'f', -1, 64)
+	case reflect.String:
+		s = rv.String()
+		if !decimalPattern.MatchString(s) {
+			return ErrCoordinate
+		}
+	default:
+		return ErrUnsupported
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 && int64(len(s)-i-1) > p {
+		return ErrPrecision
+	}
+	return nil
+}
+
+// earthRadius is the mean radius of the Earth in meters.
+const earthRadius = 6371008.8
+
+// distanceUnits maps the units accepted in distance parameters
+// to their length in meters.
+var distanceUnits = map[string]float64{
+	"m":  1,
+	"km": 1000,
+	"mi": 1609.344,
+}
+
+// speedUnits maps the units accepted in speed parameters
+// to their value in meters per second.
+var speedUnits = map[string]float64{
+	"m/s":  1,
+	"km/h": 1000.0 / 3600,
+	"mph":  1609.344 / 3600,
+}
+
+// pointLayout holds the indexes of the latitude, longitude and,
+// for timed points, time fields of a registered point type.
+type pointLayout struct {
+	lat, long, time []int
+}
+
+var (
+	pointsMu sync.RWMutex
+	points   = map[reflect.Type]pointLayout{}
+)
+
+// RegisterPoint registers the struct type of typ as a geographic point
+// whose latitude and longitude, in degrees, are held in the float fields
+// named lat and long. Values of registered point types can be used with
+// the geographic validation functions such as maxdistance.
+func RegisterPoint(typ interface{}, lat, long string) error {
+	return registerPoint(typ, lat, long, "")
+}
+
+// RegisterTimedPoint is like RegisterPoint but also registers the
+// time.Time field, named time, at which the point was recorded.
+// Slices of timed points can be validated with track.
+func RegisterTimedPoint(typ interface{}, lat, long, time string) error {
+	if time == "" {
+		return errors.New("time field name cannot be empty")
+	}
+	return registerPoint(typ, lat, long, time)
+}
+
+func registerPoint(typ interface{}, lat, long, ts string) error {
+	t := reflect.TypeOf(typ)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return errors.New("point type must be a struct")
+	}
+	var l pointLayout
+	for _, f := range []struct {
+		name string
+		idx  *[]int
+	}{{lat, &l.lat}, {long, &l.long}} {
+		sf, ok := t.FieldByName(f.name)
+		if !ok || sf.PkgPath != "" {
+			return fmt.Errorf("%s has no exported field %s", t, f.name)
+		}
+		if k := sf.Type.Kind(); k != reflect.Float32 && k != reflect.Float64 {
+			return fmt.Errorf("%s.%s is not a float", t, f.name)
+		}
+		*f.idx = sf.Index
+	}
+	if ts != "" {
+		sf, ok := t.FieldByName(ts)
+		if !ok || sf.PkgPath != "" {
+			return fmt.Errorf("%s has no exported field %s", t, ts)
+		}
+		if sf.Type != reflect.TypeOf(time.Time{}) {
+			return fmt.Errorf("%s.%s is not a time.Time", t, ts)
+		}
+		l.time = sf.Index
+	}
+	pointsMu.Lock()
+	points[t] = l
+	pointsMu.Unlock()
+	return nil
+}
+
+// asPoint returns the latitude and longitude of v, which must be a
+// value of a registered point type.
+func asPoint(v reflect.Value) (lat, long float64, err error) {
+	if !v.IsValid() {
+		return 0, 0, ErrUnsupported
+	}
+	pointsMu.RLock()
+	l, ok := points[v.Type()]
+	pointsMu.RUnlock()
+	if !ok {
+		return 0, 0, ErrUnsupported
+	}
+	return l.coordinates(v)
+}
+
+// coordinates returns the latitude and longitude held in v.
+func (l pointLayout) coordinates(v reflect.Value) (lat, long float64, err error) {
+	lat, long = v.FieldByIndex(l.lat).Float(), v.FieldByIndex(l.long).Float()
+	if math.Abs(lat) > 90 || math.Abs(long) > 180 {
+		return 0, 0, ErrCoordinate
+	}
+	return lat, long, nil
+}
+
+// asDistance returns the parameter, a number followed by one
+// of the distanceUnits (e.g. 100km), as a distance in meters.
+func asDistance(param string) (float64, error) {
+	i := strings.IndexFunc(param, unicode.IsLetter)
+	if i < 0 {
+		return 0, ErrBadParameter
+	}
+	unit, ok := distanceUnits[param[i:]]
+	if !ok {
+		return 0, ErrBadParameter
+	}
+	d, err := asFloat(param[:i])
+	if err != nil || d < 0 {
+		return 0, ErrBadParameter
+	}
+	return d * unit, nil
+}
+
+// haversine returns the great-circle distance in meters
+// between two points given in degrees.
+func haversine(lat1, long1, lat2, long2 float64) float64 {
+	rad := math.Pi / 180
+	dlat := (lat2 - lat1) * rad
+	dlong := (long2 - long1) * rad
+	a := math.Sin(dlat/2)*math.Sin(dlat/2) +
+		math.Cos(lat1*rad)*math.Cos(lat2*rad)*math.Sin(dlong/2)*math.Sin(dlong/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// maxdistance is the builtin struct level validation function that
+// checks whether the distance between two point fields of the struct
+// is at most the given distance: maxdistance=Pickup\,Dropoff\,100km,
+// with the commas escaped in tags. Nil points are considered valid.
+func maxdistance(v interface{}, f Field, param string) error {
+	params := strings.Split(param, ",")
+	if len(params) != 3 {
+		return ErrBadParameter
+	}
+	max, err := asDistance(params[2])
+	if err != nil {
+		return err
+	}
+	var coords [2][2]float64
+	for i, name := range params[:2] {
+		p, ok := f.Sibling(name)
+		if !ok {
+			return ErrBadParameter
+		}
+		if p.Kind() == reflect.Ptr || p.Kind() == reflect.Interface {
+			return nil
+		}
+		if coords[i][0], coords[i][1], err = asPoint(p); err != nil {
+			return err
+		}
+	}
+	if haversine(coords[0][0], coords[0][1], coords[1][0], coords[1][1]) > max {
+		return ErrMaxDistance
+	}
+	return nil
+}
+
+// asSpeed returns the parameter, a number followed by one of
+// the speedUnits (e.g. 300km/h), as a speed in meters per second.
+func asSpeed(param string) (float64, error) {
+	i := strings.IndexFunc(param, unicode.IsLetter)
+	if i < 0 {
+		return 0, ErrBadParameter
+	}
+	unit, ok := speedUnits[param[i:]]
+	if !ok {
+		return 0, ErrBadParameter
+	}
+	s, err := asFloat(param[:i])
+	if err != nil || s < 0 {
+		return 0, ErrBadParameter
+	}
+	return s * unit, nil
+}
+
+// track is the builtin validation function that checks whether a slice
+// of timed points has strictly increasing timestamps and, if a maximum
+// speed is given, that the speed implied by consecutive points is at
+// most that speed: track=300km/h. Nil points are skipped.
+func track(v interface{}, param string) error {
+	maxSpeed := -1.0
+	if param != "" {
+		var err error
+		if maxSpeed, err = asSpeed(param); err != nil {
+			return err
+		}
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return ErrUnsupported
+	}
+	et := rv.Type().Elem()
+	if et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	pointsMu.RLock()
+	l, ok := points[et]
+	pointsMu.RUnlock()
+	if !ok || l.time == nil {
+		return ErrUnsupported
+	}
+
+	var prev reflect.Value
+	for i := 0; i < rv.Len(); i++ {
+		cur := rv.Index(i)
+		if cur.Kind() == reflect.Ptr {
+			if cur.IsNil() {
+				continue
+			}
+			cur = cur.Elem()
+		}
+		if prev.IsValid() {
+			if err := l.checkLeg(prev, cur, maxSpeed); err != nil {
+				return err
+			}
+		}
+		prev = cur
+	}
+	return nil
+}
+
+// checkLeg validates the leg of a track going from point a to point b.
+func (l pointLayout) checkLeg(a, b reflect.Value, maxSpeed float64) error {
+	ta := a.FieldByIndex(l.time).Interface().(time.Time)
+	tb := b.FieldByIndex(l.time).Interface().(time.Time)
+	if !tb.After(ta) {
+		return ErrTrackOrder
+	}
+	lat1, long1, err := l.coordinates(a)
+	if err != nil {
+		return err
+	}
+	lat2, long2, err := l.coordinates(b)
+	if err != nil {
+		return err
+	}
+	if maxSpeed >= 0 && haversine(lat1, long1, lat2, long2)/tb.Sub(ta).Seconds() > maxSpeed {
+		return ErrMaxSpeed
+	}
+	return nil
+}
+
+// Region is an area of the Earth's surface.
+type Region interface {
+	// Contains reports whether the point at the given latitude
+	// and longitude, in degrees, lies within the region.
+	Contains(lat, long float64) bool
+}
+
+// BoundingBox is a Region delimited by two parallels and two meridians.
+// Boxes crossing the antimeridian have MinLong greater than MaxLong.
+type BoundingBox struct {
+	MinLat, MinLong, MaxLat, MaxLong float64
+}
+
+// Contains implements the Region interface.
+func (b BoundingBox) Contains(lat, long float64) bool {
+	if lat < b.MinLat || lat > b.MaxLat {
+		return false
+	}
+	if b.MinLong > b.MaxLong {
+		return long >= b.MinLong || long <= b.MaxLong
+	}
+	return long >= b.MinLong && long <= b.MaxLong
+}
+
+// Polygon is a Region delimited by a closed ring of [latitude, longitude]
+// vertices. Edges are straight lines in the latitude/longitude plane.
+type Polygon [][2]float64
+
+// Contains implements the Region interface.
+func (p Polygon) Contains(lat, long float64) bool {
+	in := false
+	for i, j := 0, len(p)-1; i < len(p); j, i = i, i+1 {
+		if (p[i][0] > lat) != (p[j][0] > lat) &&
+			long < (p[j][1]-p[i][1])*(lat-p[i][0])/(p[j][0]-p[i][0])+p[i][1] {
+			in = !in
+		}
+	}
+	return in
+}
+
+// Regions is a Region made of several regions, such as the
+// polygons of a country's mainland and islands.
+type Regions []Region
+
+// Contains implements the Region interface.
+func (rs Regions) Contains(lat, long float64) bool {
+	for _, r := range rs {
+		if r.Contains(lat, long) {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	countriesMu sync.RWMutex
+	countries   = map[string]Region{}
+)
+
+// RegisterCountry sets the region of the country with the given code,
+// as used by incountry. Calling this function with a nil region removes
+// the country, after which coordinates are not checked against it.
+func RegisterCountry(code string, r Region) {
+	countriesMu.Lock()
+	defer countriesMu.Unlock()
+	if r == nil {
+		delete(countries, code)
+		return
+	}
+	countries[code] = r
+}
+
+// incountry is the builtin validation function that checks whether a
+// point lies within the region of the country held in another field.
+// It is used either on a point field, incountry=Country, or as a struct
+// level rule naming the latitude and longitude fields,
+// incountry=Lat Long Country. Countries without a registered region
+// are not checked.
+func incountry(v interface{}, f Field, param string) error {
+	params := splitParams(param)
+	var lat, long float64
+	switch len(params) {
+	case 1:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return nil
+			}
+			rv = rv.Elem()
+		}
+		var err error
+		if lat, long, err = asPoint(rv); err != nil {
+			return err
+		}
+	case 3:
+		var coords [2]float64
+		for i, name := range params[:2] {
+			c, ok := f.Sibling(name)
+			if !ok {
+				return ErrBadParameter
+			}
+			switch c.Kind() {
+			case reflect.Ptr, reflect.Interface:
+				return nil
+			case reflect.Float32, reflect.Float64:
+				coords[i] = c.Float()
+			default:
+				return ErrUnsupported
+			}
+		}
+		lat, long = coords[0], coords[1]
+		if math.Abs(lat) > 90 || math.Abs(long) > 180 {
+			return ErrCoordinate
+		}
+	default:
+		return ErrBadParameter
+	}
+	country, ok := f.Sibling(params[len(params)-1])
+	if !ok || country.Kind() != reflect.String {
+		return ErrBadParameter
+	}
+	countriesMu.RLock()
+	r, ok := countries[country.String()]
+	countriesMu.RUnlock()
+	if ok && !r.Contains(lat, long) {
+		return ErrOutsideCountry
+	}
+	return nil
+}
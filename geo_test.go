@@ -0,0 +1,215 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestGeoPrecision(c *C) {
+	type test struct {
+		Lat  float64  `validate:"geoprecision=6"`
+		Long float32  `validate:"geoprecision=6"`
+		Alt  *float64 `validate:"geoprecision=1"`
+		Str  string   `validate:"geoprecision=3"`
+	}
+	alt := 12.5
+	err := validator.Validate(test{Lat: 45.123456, Long: -73.5, Alt: &alt, Str: "-12.345"})
+	c.Assert(err, IsNil)
+	err = validator.Validate(test{Lat: 45, Long: 1, Str: "12"})
+	c.Assert(err, IsNil)
+
+	alt = 12.55
+	err = validator.Validate(test{Lat: 45.123456789012345, Long: 0.1234567, Alt: &alt, Str: "1.2345"})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 4)
+	c.Assert(errs["Lat"], HasError, validator.ErrPrecision)
+	c.Assert(errs["Long"], HasError, validator.ErrPrecision)
+	c.Assert(errs["Alt"], HasError, validator.ErrPrecision)
+	c.Assert(errs["Str"], HasError, validator.ErrPrecision)
+
+	err = validator.Valid("north", "geoprecision=6")
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrCoordinate)
+	err = validator.Valid(1.5, "geoprecision=-1")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+	err = validator.Valid(15, "geoprecision=6")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrUnsupported)
+}
+
+type GeoPoint struct {
+	Lat, Long float64
+}
+
+func init() {
+	if err := validator.RegisterPoint(GeoPoint{}, "Lat", "Long"); err != nil {
+		panic(err)
+	}
+}
+
+func (ms *MySuite) TestMaxDistance(c *C) {
+	type ride struct {
+		_       struct{} `validate:"maxdistance=Pickup\\,Dropoff\\,100km"`
+		Pickup  GeoPoint
+		Dropoff *GeoPoint
+	}
+	paris := GeoPoint{48.8566, 2.3522}
+	versailles := GeoPoint{48.8049, 2.1204}
+	london := GeoPoint{51.5074, -0.1278}
+
+	err := validator.Validate(ride{Pickup: paris, Dropoff: &versailles})
+	c.Assert(err, IsNil)
+	err = validator.Validate(ride{Pickup: paris})
+	c.Assert(err, IsNil)
+
+	err = validator.Validate(ride{Pickup: paris, Dropoff: &london})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs[""], HasError, validator.ErrMaxDistance)
+
+	type request struct {
+		Ride ride
+	}
+	err = validator.Validate(request{ride{Pickup: paris, Dropoff: &GeoPoint{91, 0}}})
+	c.Assert(err, NotNil)
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Ride"], HasError, validator.ErrCoordinate)
+
+	type badParams struct {
+		_ struct{} `validate:"maxdistance=A\\,B\\,100lightyears"`
+		_ struct{} `validate:"maxdistance=A\\,C\\,1km"`
+		_ struct{} `validate:"maxdistance=A\\,D\\,1km"`
+		_ struct{} `validate:"maxdistance=A B 1km"`
+		A GeoPoint
+		B GeoPoint
+		D struct{ X, Y float64 }
+	}
+	err = validator.Validate(badParams{})
+	c.Assert(err, NotNil)
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs[""], HasLen, 4)
+	c.Assert(errs[""][0], Equals, validator.ErrBadParameter)
+	c.Assert(errs[""][1], Equals, validator.ErrBadParameter)
+	c.Assert(errs[""][2], Equals, validator.ErrUnsupported)
+	// the parameters are separated by commas only
+	c.Assert(errs[""][3], Equals, validator.ErrBadParameter)
+
+	c.Assert(validator.RegisterPoint(struct{ Lat string }{}, "Lat", "Long"), NotNil)
+}
+
+type LocationSample struct {
+	Lat, Long float64
+	At        time.Time
+}
+
+func init() {
+	if err := validator.RegisterTimedPoint(LocationSample{}, "Lat", "Long", "At"); err != nil {
+		panic(err)
+	}
+}
+
+func (ms *MySuite) TestTrack(c *C) {
+	type test struct {
+		Samples []LocationSample  `validate:"track=200km/h"`
+		Ordered []*LocationSample `validate:"track"`
+		Points  []GeoPoint        `validate:"track"`
+		Bad     [1]LocationSample `validate:"track=fast"`
+	}
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	samples := []LocationSample{
+		{48.8566, 2.3522, t0},
+		{48.8049, 2.1204, t0.Add(10 * time.Minute)},
+		{48.8566, 2.3522, t0.Add(20 * time.Minute)},
+	}
+	err := validator.Validate(test{
+		Samples: samples,
+		Ordered: []*LocationSample{&samples[0], nil, &samples[2]},
+		Points:  []GeoPoint{},
+	})
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Points"], HasError, validator.ErrUnsupported)
+	c.Assert(errs["Bad"], HasError, validator.ErrBadParameter)
+
+	// teleporting to London in a minute
+	err = validator.Valid(append(samples, LocationSample{51.5074, -0.1278, t0.Add(21 * time.Minute)}), "track=200km/h")
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrMaxSpeed)
+	err = validator.Valid(append(samples, LocationSample{51.5074, -0.1278, t0.Add(21 * time.Minute)}), "track")
+	c.Assert(err, IsNil)
+
+	err = validator.Valid([]*LocationSample{&samples[1], &samples[0]}, "track")
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrTrackOrder)
+	err = validator.Valid([]LocationSample{samples[0], samples[0]}, "track")
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrTrackOrder)
+}
+
+func (ms *MySuite) TestInCountry(c *C) {
+	type address struct {
+		_        struct{} `validate:"incountry=Lat Long Country"`
+		Lat      float64
+		Long     float64
+		Location *GeoPoint `validate:"incountry=Country"`
+		Country  string
+	}
+	paris := GeoPoint{48.8566, 2.3522}
+	err := validator.Validate(address{Lat: 48.8566, Long: 2.3522, Location: &paris, Country: "FR"})
+	c.Assert(err, IsNil)
+	err = validator.Validate(address{Lat: 64.7, Long: -175.3, Country: "RU"})
+	c.Assert(err, IsNil)
+	// unknown countries are not checked
+	err = validator.Validate(address{Lat: 1, Long: 2, Country: "ZZ"})
+	c.Assert(err, IsNil)
+
+	swapped := GeoPoint{2.3522, 48.8566}
+	err = validator.Validate(address{Lat: 2.3522, Long: 48.8566, Location: &swapped, Country: "FR"})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs[""], HasError, validator.ErrOutsideCountry)
+	c.Assert(errs["Location"], HasError, validator.ErrOutsideCountry)
+
+	validator.RegisterCountry("ZZ", validator.Regions{
+		validator.Polygon{{0, 0}, {0, 10}, {10, 10}, {10, 0}},
+		validator.BoundingBox{MinLat: -10, MinLong: 170, MaxLat: 10, MaxLong: -170},
+	})
+	defer validator.RegisterCountry("ZZ", nil)
+	err = validator.Validate(address{Lat: 5, Long: 5, Country: "ZZ"})
+	c.Assert(err, IsNil)
+	err = validator.Validate(address{Lat: 0, Long: 179.5, Country: "ZZ"})
+	c.Assert(err, IsNil)
+	err = validator.Validate(address{Lat: 5, Long: 15, Country: "ZZ"})
+	c.Assert(err, NotNil)
+	err = validator.Validate(address{Lat: 95, Long: 15, Country: "ZZ"})
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs[""], HasError, validator.ErrCoordinate)
+}
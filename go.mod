@@ -1,8 +1,11 @@
 module gopkg.in/validator.v2
 
-go 1.18
+go 1.20
 
-require gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c
+require (
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
 	github.com/kr/pretty v0.2.1 // indirect
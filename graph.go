@@ -0,0 +1,132 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"reflect"
+)
+
+var (
+	// ErrDuplicate is the error returned when a value that
+	// must be unique is found more than once
+	ErrDuplicate = TextErr{errors.New("duplicate value")}
+	// ErrUnknownParent is the error returned when an item refers
+	// to a parent that does not exist
+	ErrUnknownParent = TextErr{errors.New("unknown parent")}
+	// ErrCycle is the error returned when items refer to their
+	// parents in a cycle
+	ErrCycle = TextErr{errors.New("cycle detected")}
+)
+
+// acyclic is the builtin validation function that checks whether a slice
+// of structs, each holding its own ID and the ID of its parent, describes
+// a forest: IDs are unique, parents exist within the slice and no item is
+// its own ancestor. Items whose parent ID is the zero value or a nil
+// pointer are roots: acyclic=ID ParentID.
+func acyclic(v interface{}, param string) error {
+	params := splitParams(param)
+	if len(params) != 2 {
+		return ErrBadParameter
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return ErrUnsupported
+	}
+	et := rv.Type().Elem()
+	if et.Kind() == reflect.Ptr {
+		et = et.Elem()
+	}
+	if et.Kind() != reflect.Struct {
+		return ErrUnsupported
+	}
+	var idx [2][]int
+	for i, name := range params {
+		sf, ok := et.FieldByName(name)
+		if !ok || sf.PkgPath != "" {
+			return ErrBadParameter
+		}
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if !ft.Comparable() {
+			return ErrUnsupported
+		}
+		idx[i] = sf.Index
+	}
+
+	parents := make(map[interface{}]interface{}, rv.Len())
+	for i := 0; i < rv.Len(); i++ {
+		item := rv.Index(i)
+		if item.Kind() == reflect.Ptr {
+			if item.IsNil() {
+				continue
+			}
+			item = item.Elem()
+		}
+		id, ok := graphKey(item.FieldByIndex(idx[0]))
+		if !ok {
+			return ErrInvalid
+		}
+		if _, found := parents[id]; found {
+			return ErrDuplicate
+		}
+		parents[id], _ = graphKey(item.FieldByIndex(idx[1]))
+	}
+
+	// walk up from every item, remembering the items known to lead to a
+	// root so that each item is walked at most once
+	rooted := make(map[interface{}]bool, len(parents))
+	for id := range parents {
+		seen := map[interface{}]bool{}
+		for cur := id; cur != nil && !rooted[cur]; cur = parents[cur] {
+			if seen[cur] {
+				return ErrCycle
+			}
+			seen[cur] = true
+			if _, found := parents[parents[cur]]; parents[cur] != nil && !found {
+				return ErrUnknownParent
+			}
+		}
+		for cur := range seen {
+			rooted[cur] = true
+		}
+	}
+	return nil
+}
+
+// graphKey returns the value of an ID field as a map key, following
+// pointers. It returns false for zero values and nil pointers.
+func graphKey(v reflect.Value) (interface{}, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if v.IsZero() {
+		return nil, false
+	}
+	return v.Interface(), true
+}
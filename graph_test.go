@@ -0,0 +1,65 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestAcyclic(c *C) {
+	type category struct {
+		ID       int
+		ParentID int
+	}
+	type employee struct {
+		ID        string
+		ManagerID *string
+	}
+	type test struct {
+		Categories []category  `validate:"acyclic=ID ParentID"`
+		Employees  []*employee `validate:"acyclic=ID\\,ManagerID"`
+	}
+	boss, cto := "boss", "cto"
+	err := validator.Validate(test{
+		Categories: []category{{4, 2}, {1, 0}, {2, 1}, {3, 1}, {5, 0}},
+		Employees:  []*employee{{"cto", &boss}, {"boss", nil}, nil, {"dev", &cto}},
+	})
+	c.Assert(err, IsNil)
+
+	for _, tc := range []struct {
+		categories []category
+		err        error
+	}{
+		{[]category{{1, 0}, {2, 3}}, validator.ErrUnknownParent},
+		{[]category{{1, 2}, {2, 3}, {3, 1}, {4, 0}}, validator.ErrCycle},
+		{[]category{{1, 1}}, validator.ErrCycle},
+		{[]category{{1, 0}, {1, 0}}, validator.ErrDuplicate},
+		{[]category{{0, 1}}, validator.ErrInvalid},
+	} {
+		err = validator.Valid(tc.categories, "acyclic=ID ParentID")
+		c.Assert(err, NotNil)
+		c.Assert(err.(validator.ErrorArray), HasError, tc.err)
+	}
+
+	err = validator.Valid([]category{}, "acyclic=ID")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+	err = validator.Valid([]category{}, "acyclic=ID Parent")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+	err = validator.Valid([]int{1}, "acyclic=ID ParentID")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrUnsupported)
+}
@@ -0,0 +1,292 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"mime"
+	"path"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	// ErrIdempotencyKey is the error returned when a value is not
+	// a valid idempotency key
+	ErrIdempotencyKey = TextErr{errors.New("invalid idempotency key")}
+	// ErrTraceparent is the error returned when a value is not a
+	// valid W3C traceparent header
+	ErrTraceparent = TextErr{errors.New("invalid traceparent")}
+	// ErrSignatureHeader is the error returned when a value is not a
+	// valid webhook signature header
+	ErrSignatureHeader = TextErr{errors.New("invalid signature header")}
+	// ErrSignatureTimestamp is the error returned when the timestamp of
+	// a webhook signature header is outside of the tolerance specified
+	ErrSignatureTimestamp = TextErr{errors.New("signature timestamp outside tolerance")}
+	// ErrAcceptLanguage is the error returned when a value is not a
+	// valid Accept-Language header
+	ErrAcceptLanguage = TextErr{errors.New("invalid accept-language")}
+	// ErrMediaType is the error returned when a value is not a valid
+	// media type or not one of the media types specified
+	ErrMediaType = TextErr{errors.New("invalid media type")}
+	// ErrEncoding is the error returned when a string is not
+	// valid UTF-8
+	ErrEncoding = TextErr{errors.New("invalid UTF-8")}
+	// ErrPrintable is the error returned when a string holds
+	// characters that are not printable
+	ErrPrintable = TextErr{errors.New("non-printable characters")}
+)
+
+// defaultSignatureTolerance is the tolerance used by webhooksig
+// when none is specified.
+const defaultSignatureTolerance = 5 * time.Minute
+
+var (
+	// idempotencyKeyForms holds the patterns of the forms an
+	// idempotency key can be restricted to.
+	idempotencyKeyForms = map[string]*regexp.Regexp{
+		"uuid": regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`),
+		"ulid": regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Za-hjkmnp-tv-z]{25}$`),
+	}
+	// idempotencyKeyPattern matches idempotency keys of any form.
+	idempotencyKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,255}$`)
+	// traceparentPattern matches the version, trace ID, parent ID and
+	// flags of W3C traceparent headers.
+	traceparentPattern = regexp.MustCompile(`^([0-9a-f]{2})-([0-9a-f]{32})-([0-9a-f]{16})-([0-9a-f]{2})(-.*)?$`)
+	// languageRangePattern matches the weighted language ranges of
+	// Accept-Language headers, such as fr-CH;q=0.9.
+	languageRangePattern = regexp.MustCompile(`^(\*|[A-Za-z]{1,8}(-[A-Za-z0-9]{1,8})*)` +
+		`([ \t]*;[ \t]*[qQ]=(0(\.[0-9]{0,3})?|1(\.0{0,3})?))?$`)
+)
+
+// stringValue returns the string held in v, following pointers. It
+// returns false if v is a nil pointer and ErrUnsupported if v does not
+// hold a string.
+func stringValue(v interface{}) (string, bool, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return "", false, nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.String {
+		return "", false, ErrUnsupported
+	}
+	return rv.String(), true, nil
+}
+
+// idempotencykey is the builtin validation function that checks whether
+// a string is an idempotency key: 1 to 255 letters, digits, and _ . : -
+// characters. The key can be restricted to given forms, uuid or ulid:
+// idempotencykey=uuid ulid.
+func idempotencykey(v interface{}, param string) error {
+	forms := splitParams(param)
+	for _, f := range forms {
+		if _, ok := idempotencyKeyForms[f]; !ok {
+			return ErrBadParameter
+		}
+	}
+	s, ok, err := stringValue(v)
+	if !ok {
+		return err
+	}
+	if !idempotencyKeyPattern.MatchString(s) {
+		return ErrIdempotencyKey
+	}
+	if len(forms) == 0 {
+		return nil
+	}
+	for _, f := range forms {
+		if idempotencyKeyForms[f].MatchString(s) {
+			return nil
+		}
+	}
+	return ErrIdempotencyKey
+}
+
+// traceparent is the builtin validation function that checks whether a
+// string is a W3C Trace Context traceparent header, such as
+// 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01.
+func traceparent(v interface{}, param string) error {
+	s, ok, err := stringValue(v)
+	if !ok {
+		return err
+	}
+	m := traceparentPattern.FindStringSubmatch(s)
+	if m == nil || m[1] == "ff" ||
+		strings.Trim(m[2], "0") == "" || strings.Trim(m[3], "0") == "" ||
+		(m[1] == "00" && m[5] != "") {
+		return ErrTraceparent
+	}
+	return nil
+}
+
+// NewWebhookSignatureFunc returns the webhooksig validation function
+// using clock to get the current time, so that tests can control it:
+//
+//	validator.SetValidationFunc("webhooksig", validator.NewWebhookSignatureFunc(clock))
+func NewWebhookSignatureFunc(clock func() time.Time) ValidationFunc {
+	return func(v interface{}, param string) error {
+		return webhookSignature(v, param, clock)
+	}
+}
+
+// webhooksig is the builtin validation function that checks whether a
+// string is a webhook signature header such as t=1492774577,v1=5257a8...,
+// with a unix timestamp, one or more hex SHA-256 v1 signatures, and
+// possibly other schemes. The timestamp must be within the tolerance
+// given as a duration, 5 minutes by default, of the current time:
+// webhooksig=10m.
+func webhooksig(v interface{}, param string) error {
+	return webhookSignature(v, param, time.Now)
+}
+
+func webhookSignature(v interface{}, param string, clock func() time.Time) error {
+	tolerance := defaultSignatureTolerance
+	if param != "" {
+		var err error
+		if tolerance, err = time.ParseDuration(param); err != nil || tolerance < 0 {
+			return ErrBadParameter
+		}
+	}
+	s, ok, err := stringValue(v)
+	if !ok {
+		return err
+	}
+	var ts string
+	signatures := 0
+	for _, item := range strings.Split(s, ",") {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return ErrSignatureHeader
+		}
+		switch kv[0] {
+		case "t":
+			if ts != "" {
+				return ErrSignatureHeader
+			}
+			ts = kv[1]
+		case "v1":
+			if len(kv[1]) != 64 || strings.Trim(kv[1], "0123456789abcdef") != "" {
+				return ErrSignatureHeader
+			}
+			signatures++
+		}
+	}
+	if ts == "" || signatures == 0 {
+		return ErrSignatureHeader
+	}
+	unix, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrSignatureHeader
+	}
+	if d := clock().Sub(time.Unix(unix, 0)); d > tolerance || d < -tolerance {
+		return ErrSignatureTimestamp
+	}
+	return nil
+}
+
+// acceptlanguage is the builtin validation function that checks whether
+// a string is an Accept-Language header value: a comma separated list
+// of language ranges, each optionally weighted with a quality value,
+// such as "fr-CH, fr;q=0.9, *;q=0.5".
+func acceptlanguage(v interface{}, param string) error {
+	s, ok, err := stringValue(v)
+	if !ok {
+		return err
+	}
+	for _, lr := range strings.Split(s, ",") {
+		if !languageRangePattern.MatchString(strings.Trim(lr, " \t")) {
+			return ErrAcceptLanguage
+		}
+	}
+	return nil
+}
+
+// mediatype is the builtin validation function that checks whether a
+// string is a media type with optional parameters, as found in
+// Content-Type headers, such as "text/html; charset=utf-8". The media
+// type can be restricted to the given ones, which may use wildcards:
+// mediatype=application/json text/*.
+func mediatype(v interface{}, param string) error {
+	allowed := splitParams(param)
+	for _, a := range allowed {
+		if _, err := path.Match(a, ""); err != nil {
+			return ErrBadParameter
+		}
+	}
+	s, ok, err := stringValue(v)
+	if !ok {
+		return err
+	}
+	mt, _, err := mime.ParseMediaType(s)
+	if err != nil || strings.Count(mt, "/") != 1 || strings.HasPrefix(mt, "/") || strings.HasSuffix(mt, "/") {
+		return ErrMediaType
+	}
+	if len(allowed) == 0 {
+		return nil
+	}
+	for _, a := range allowed {
+		if ok, _ := path.Match(strings.ToLower(a), mt); ok {
+			return nil
+		}
+	}
+	return ErrMediaType
+}
+
+// printable is the builtin validation function that checks whether a
+// string is made of printable characters only, without control or
+// invisible formatting characters, as expected of free text headers
+// such as User-Agent. The parameter can give the maximum length in
+// bytes and restrict the string to ASCII: printable=512 ascii.
+func printable(v interface{}, param string) error {
+	maxBytes := int64(-1)
+	ascii := false
+	for _, p := range splitParams(param) {
+		if p == "ascii" {
+			ascii = true
+			continue
+		}
+		n, err := asInt(p)
+		if err != nil || n < 0 {
+			return ErrBadParameter
+		}
+		maxBytes = n
+	}
+	s, ok, err := stringValue(v)
+	if !ok {
+		return err
+	}
+	if maxBytes >= 0 && int64(len(s)) > maxBytes {
+		return ErrMax
+	}
+	if !utf8.ValidString(s) {
+		return ErrEncoding
+	}
+	for _, r := range s {
+		if !unicode.IsPrint(r) || (ascii && r > unicode.MaxASCII) {
+			return ErrPrintable
+		}
+	}
+	return nil
+}
@@ -0,0 +1,215 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestIdempotencyKey(c *C) {
+	type envelope struct {
+		Key     string  `validate:"idempotencykey"`
+		UUIDKey string  `validate:"idempotencykey=uuid"`
+		AnyKey  *string `validate:"idempotencykey=uuid ulid"`
+	}
+	ulid := "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	err := validator.Validate(envelope{
+		Key:     "order-42:retry.1",
+		UUIDKey: "123e4567-e89b-12d3-a456-426614174000",
+		AnyKey:  &ulid,
+	})
+	c.Assert(err, IsNil)
+
+	bad := "81ARZ3NDEKTSV4RRFFQ69G5FAV"
+	err = validator.Validate(envelope{
+		Key:     strings.Repeat("k", 256),
+		UUIDKey: "123e4567e89b12d3a456426614174000",
+		AnyKey:  &bad,
+	})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["Key"], HasError, validator.ErrIdempotencyKey)
+	c.Assert(errs["UUIDKey"], HasError, validator.ErrIdempotencyKey)
+	c.Assert(errs["AnyKey"], HasError, validator.ErrIdempotencyKey)
+
+	err = validator.Valid("", "idempotencykey")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrIdempotencyKey)
+	err = validator.Valid("key with spaces", "idempotencykey")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrIdempotencyKey)
+	err = validator.Valid("key", "idempotencykey=snowflake")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestTraceparent(c *C) {
+	for _, s := range []string{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00",
+		"01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-future",
+	} {
+		c.Assert(validator.Valid(s, "traceparent"), IsNil, Commentf("%s", s))
+	}
+	for _, s := range []string{
+		"",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00F067AA0BA902B7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra",
+		"00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01",
+	} {
+		err := validator.Valid(s, "traceparent")
+		c.Assert(err, NotNil, Commentf("%s", s))
+		c.Assert(err.(validator.ErrorArray), HasError, validator.ErrTraceparent)
+	}
+	err := validator.Valid(42, "traceparent")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestWebhookSignature(c *C) {
+	now := time.Unix(1492774577, 0)
+	v := validator.NewValidator()
+	v.SetValidationFunc("webhooksig", validator.NewWebhookSignatureFunc(func() time.Time { return now }))
+
+	sig := "5257a869e7ecebeda32affa62cdca3fa51cad7e77a0e56ff536d0ce8e108d8bd"
+	for _, s := range []string{
+		"t=1492774577,v1=" + sig,
+		"t=1492774400,v1=" + sig + ",v1=" + sig + ",v0=legacy",
+		"v1=" + sig + ",t=1492774800",
+	} {
+		c.Assert(v.Valid(s, "webhooksig"), IsNil, Commentf("%s", s))
+	}
+	for _, tc := range []struct {
+		header, tags string
+		err          error
+	}{
+		{"", "webhooksig", validator.ErrSignatureHeader},
+		{"t=1492774577", "webhooksig", validator.ErrSignatureHeader},
+		{"v1=" + sig, "webhooksig", validator.ErrSignatureHeader},
+		{"t=1492774577,v1=" + sig[:63], "webhooksig", validator.ErrSignatureHeader},
+		{"t=1492774577,v1=" + strings.ToUpper(sig), "webhooksig", validator.ErrSignatureHeader},
+		{"t=1492774577,t=1492774577,v1=" + sig, "webhooksig", validator.ErrSignatureHeader},
+		{"t=now,v1=" + sig, "webhooksig", validator.ErrSignatureHeader},
+		{"t=1492774577;v1=" + sig, "webhooksig", validator.ErrSignatureHeader},
+		{"t=1492774000,v1=" + sig, "webhooksig", validator.ErrSignatureTimestamp},
+		{"t=1492774000,v1=" + sig, "webhooksig=1m", validator.ErrSignatureTimestamp},
+		{"t=1492774577,v1=" + sig, "webhooksig=soon", validator.ErrBadParameter},
+	} {
+		err := v.Valid(tc.header, tc.tags)
+		c.Assert(err, NotNil, Commentf("%s", tc.header))
+		c.Assert(err.(validator.ErrorArray), HasError, tc.err, Commentf("%s", tc.header))
+	}
+	c.Assert(v.Valid("t=1492774000,v1="+sig, "webhooksig=10m"), IsNil)
+
+	// the default validator uses the current time
+	header := fmt.Sprintf("t=%d,v1=%s", time.Now().Unix(), sig)
+	c.Assert(validator.Valid(header, "webhooksig"), IsNil)
+}
+
+func (ms *MySuite) TestAcceptLanguage(c *C) {
+	for _, s := range []string{
+		"fr",
+		"*",
+		"fr-CH, fr;q=0.9, en;q=0.8, de;q=0.7, *;q=0.5",
+		"zh-Hant-TW;q=1.000,en-US ; q=0",
+	} {
+		c.Assert(validator.Valid(s, "acceptlanguage"), IsNil, Commentf("%s", s))
+	}
+	for _, s := range []string{
+		"",
+		"fr,,en",
+		"fr;q=1.5",
+		"fr;q=0.1234",
+		"fr;level=1",
+		"english_us",
+		"fr-",
+		"toolonglanguage",
+	} {
+		err := validator.Valid(s, "acceptlanguage")
+		c.Assert(err, NotNil, Commentf("%s", s))
+		c.Assert(err.(validator.ErrorArray), HasError, validator.ErrAcceptLanguage)
+	}
+}
+
+func (ms *MySuite) TestMediaType(c *C) {
+	for _, tc := range []struct{ value, tags string }{
+		{"application/json", "mediatype"},
+		{"text/html; charset=utf-8", "mediatype"},
+		{`multipart/form-data; boundary="a b"`, "mediatype"},
+		{"Application/JSON", "mediatype=application/json"},
+		{"text/plain;charset=us-ascii", "mediatype=application/json text/*"},
+	} {
+		c.Assert(validator.Valid(tc.value, tc.tags), IsNil, Commentf("%s", tc.value))
+	}
+	for _, tc := range []struct{ value, tags string }{
+		{"", "mediatype"},
+		{"json", "mediatype"},
+		{"form-data", "mediatype"},
+		{"text/", "mediatype"},
+		{"text/html; charset", "mediatype"},
+		{"text/html charset=utf-8", "mediatype"},
+		{"image/png", "mediatype=application/json text/*"},
+	} {
+		err := validator.Valid(tc.value, tc.tags)
+		c.Assert(err, NotNil, Commentf("%s", tc.value))
+		c.Assert(err.(validator.ErrorArray), HasError, validator.ErrMediaType)
+	}
+	err := validator.Valid("text/html", "mediatype=text/[")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestPrintable(c *C) {
+	type telemetry struct {
+		UserAgent string  `validate:"printable=64"`
+		Device    string  `validate:"printable=ascii"`
+		Locale    *string `validate:"printable=8 ascii"`
+	}
+	locale := "fr-FR"
+	err := validator.Validate(telemetry{
+		UserAgent: "Mozilla/5.0 (Macintosh; Intel Mac OS X) – Safari",
+		Device:    "iPhone 15 Pro",
+		Locale:    &locale,
+	})
+	c.Assert(err, IsNil)
+
+	locale = "fr-FR-é"
+	err = validator.Validate(telemetry{
+		UserAgent: strings.Repeat("a", 65),
+		Device:    "Pixel\t8",
+		Locale:    &locale,
+	})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["UserAgent"], HasError, validator.ErrMax)
+	c.Assert(errs["Device"], HasError, validator.ErrPrintable)
+	c.Assert(errs["Locale"], HasError, validator.ErrPrintable)
+
+	err = validator.Valid("bad \xff utf8", "printable")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrEncoding)
+	err = validator.Valid("zero\u200bwidth", "printable")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrPrintable)
+	err = validator.Valid("abc", "printable=-1")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+}
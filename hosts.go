@@ -0,0 +1,91 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+)
+
+var (
+	// ErrHostNotAllowed is the error returned when a host is not
+	// one of the hosts allowed
+	ErrHostNotAllowed = TextErr{errors.New("host not allowed")}
+)
+
+// hostOf returns the lower-cased host of s, a URL with a scheme or a
+// host with an optional port.
+func hostOf(s string) (string, error) {
+	if strings.Contains(s, "://") {
+		u, err := url.Parse(s)
+		if err != nil {
+			return "", err
+		}
+		s = u.Hostname()
+	} else if h, _, err := net.SplitHostPort(s); err == nil {
+		s = h
+	}
+	s = strings.TrimSuffix(strings.ToLower(s), ".")
+	if s == "" || strings.ContainsAny(s, "/?#@ ") {
+		return "", ErrHostNotAllowed
+	}
+	return s, nil
+}
+
+// matchHost reports whether host matches pattern, in which a
+// * label matches exactly one label: *.example.com matches
+// api.example.com but neither example.com nor a.b.example.com.
+func matchHost(pattern, host string) bool {
+	pl := strings.Split(strings.TrimSuffix(strings.ToLower(pattern), "."), ".")
+	hl := strings.Split(host, ".")
+	if len(pl) != len(hl) {
+		return false
+	}
+	for i := range pl {
+		if pl[i] != "*" && pl[i] != hl[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// hostallow is the builtin validation function that checks whether the
+// host of a string, a URL or a host with an optional port, matches one
+// of the given patterns, as done for OAuth redirect URIs:
+// hostallow=*.example.com api.partner.io.
+func hostallow(v interface{}, param string) error {
+	patterns := splitParams(param)
+	if len(patterns) == 0 {
+		return ErrBadParameter
+	}
+	s, ok, err := stringValue(v)
+	if !ok {
+		return err
+	}
+	host, err := hostOf(s)
+	if err != nil {
+		return ErrHostNotAllowed
+	}
+	for _, p := range patterns {
+		if matchHost(p, host) {
+			return nil
+		}
+	}
+	return ErrHostNotAllowed
+}
@@ -0,0 +1,51 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestHostAllow(c *C) {
+	tags := "hostallow=*.example.com api.partner.io"
+	for _, s := range []string{
+		"https://app.example.com/callback?x=1",
+		"https://API.Partner.io:8443/cb",
+		"app.example.com",
+		"app.example.com.:443",
+		"api.partner.io",
+	} {
+		c.Assert(validator.Valid(s, tags), IsNil, Commentf("%s", s))
+	}
+	for _, s := range []string{
+		"",
+		"https://example.com/callback",
+		"https://a.b.example.com/callback",
+		"https://app.example.com.evil.io/callback",
+		"https://api.partner.io@evil.io/callback",
+		"evil.io/app.example.com",
+		"javascript:alert(1)",
+		"https://[::1/",
+	} {
+		err := validator.Valid(s, tags)
+		c.Assert(err, NotNil, Commentf("%s", s))
+		c.Assert(err.(validator.ErrorArray), HasError, validator.ErrHostNotAllowed)
+	}
+	err := validator.Valid("api.partner.io", "hostallow")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+}
@@ -0,0 +1,132 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// KeyFormatFunc returns the form of the key of a map element in the
+// paths errors are keyed by, as in "Prices[EUR](value)".
+type KeyFormatFunc func(key reflect.Value) string
+
+// KeyFormatter returns an Option formatting the keys of map elements in
+// paths with fn rather than with FormatKey.
+func KeyFormatter(fn KeyFormatFunc) Option {
+	return func(mv *Validator) {
+		mv.keyFormat = fn
+	}
+}
+
+// FormatKey returns the compact and stable form of the key of a map
+// element used in paths by default. Keys implementing
+// encoding.TextMarshaler are formatted as their text, errors and
+// fmt.Stringers as their message, and strings as themselves. Structs are
+// formatted as in "{Num:3 Name:foo}", arrays as in "[1 2]", and pointers
+// and interfaces as the values they hold, or nil, rather than as their
+// addresses. Pointers back to the values holding them, as in cyclic
+// keys, are formatted as their addresses.
+func FormatKey(key reflect.Value) string {
+	var b strings.Builder
+	formatKey(&b, key, map[uintptr]bool{})
+	return b.String()
+}
+
+// formatKey writes the form of key given by FormatKey to b. Path holds
+// the pointers followed to reach key.
+func formatKey(b *strings.Builder, key reflect.Value, path map[uintptr]bool) {
+	// the methods of nil pointers, such as those held by interface
+	// keys, may not be called
+	for key.Kind() == reflect.Interface && !key.IsNil() {
+		key = key.Elem()
+	}
+	if !key.IsValid() || (key.Kind() == reflect.Ptr || key.Kind() == reflect.Interface) && key.IsNil() {
+		b.WriteString("nil")
+		return
+	}
+	if key.CanInterface() {
+		switch k := key.Interface().(type) {
+		case encoding.TextMarshaler:
+			if text, err := k.MarshalText(); err == nil {
+				b.Write(text)
+				return
+			}
+		case error:
+			b.WriteString(k.Error())
+			return
+		case fmt.Stringer:
+			b.WriteString(k.String())
+			return
+		}
+	}
+	switch key.Kind() {
+	case reflect.String:
+		b.WriteString(key.String())
+	case reflect.Bool:
+		b.WriteString(strconv.FormatBool(key.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		b.WriteString(strconv.FormatInt(key.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		b.WriteString(strconv.FormatUint(key.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		b.WriteString(strconv.FormatFloat(key.Float(), 'g', -1, key.Type().Bits()))
+	case reflect.Ptr:
+		p := key.Pointer()
+		if path[p] {
+			fmt.Fprintf(b, "%#x", p)
+			return
+		}
+		path[p] = true
+		formatKey(b, key.Elem(), path)
+		delete(path, p)
+	case reflect.Array:
+		b.WriteByte('[')
+		for i := 0; i < key.Len(); i++ {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			formatKey(b, key.Index(i), path)
+		}
+		b.WriteByte(']')
+	case reflect.Struct:
+		b.WriteByte('{')
+		for i := 0; i < key.NumField(); i++ {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(key.Type().Field(i).Name)
+			b.WriteByte(':')
+			formatKey(b, key.Field(i), path)
+		}
+		b.WriteByte('}')
+	default:
+		// complex numbers and channels
+		fmt.Fprint(b, key)
+	}
+}
+
+// formatKey returns the form of key in the paths of errors found by mv.
+func (mv *Validator) formatKey(key reflect.Value) string {
+	if mv.keyFormat != nil {
+		return mv.keyFormat(key)
+	}
+	return FormatKey(key)
+}
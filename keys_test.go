@@ -0,0 +1,115 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestFormatKey(c *C) {
+	type inner struct {
+		N *int
+	}
+	type key struct {
+		Num   int
+		Inner inner
+		Tags  [2]string
+		When  time.Time
+	}
+	n := 3
+	when := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	c.Assert(validator.FormatKey(reflect.ValueOf(key{Num: 1, Inner: inner{&n}, Tags: [2]string{"a", "b"}, When: when})),
+		Equals, "{Num:1 Inner:{N:3} Tags:[a b] When:2024-05-01T12:00:00Z}")
+	c.Assert(validator.FormatKey(reflect.ValueOf(inner{})), Equals, "{N:nil}")
+	c.Assert(validator.FormatKey(reflect.ValueOf(when)), Equals, "2024-05-01T12:00:00Z")
+	c.Assert(validator.FormatKey(reflect.ValueOf(2.5)), Equals, "2.5")
+}
+
+type stringerKey struct{ N int }
+
+func (k stringerKey) String() string { return fmt.Sprint("key ", k.N) }
+
+func (ms *MySuite) TestFormatKeyNilPointers(c *C) {
+	keys := map[interface{}]bool{(*stringerKey)(nil): true}
+	c.Assert(validator.FormatKey(reflect.ValueOf(keys).MapKeys()[0]), Equals, "nil")
+	c.Assert(validator.FormatKey(reflect.ValueOf(&stringerKey{2})), Equals, "key 2")
+
+	type item struct {
+		Name string `validate:"nonzero"`
+	}
+	errs, ok := validator.Validate(map[interface{}]item{(*stringerKey)(nil): {}}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["[nil](value).Name"], HasError, validator.ErrZeroValue)
+}
+
+type cyclicKey struct {
+	Name string
+	Next *cyclicKey
+}
+
+func (ms *MySuite) TestFormatKeyCycles(c *C) {
+	n := &cyclicKey{Name: "a"}
+	n.Next = n
+	key := validator.FormatKey(reflect.ValueOf(n))
+	c.Assert(key, Matches, `\{Name:a Next:0x[0-9a-f]+\}`)
+	c.Assert(validator.FormatKey(reflect.ValueOf([2]*cyclicKey{n, n})), Equals, "["+key+" "+key+"]")
+
+	type item struct {
+		Name string `validate:"nonzero"`
+	}
+	errs, ok := validator.Validate(map[*cyclicKey]item{n: {}}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["["+key+"](value).Name"], HasError, validator.ErrZeroValue)
+}
+
+func (ms *MySuite) TestKeyFormatter(c *C) {
+	type point struct {
+		X, Y int
+	}
+	v := validator.NewValidator()
+	v.SetValidationFunc("short", func(i interface{}, param string) error {
+		if len(i.(string)) > 1 {
+			return validator.ErrMax
+		}
+		return nil
+	})
+	type board struct {
+		Cells map[point]*struct {
+			Mark string `validate:"short"`
+		}
+	}
+	b := board{Cells: map[point]*struct {
+		Mark string `validate:"short"`
+	}{{1, 2}: {"xo"}}}
+
+	errs, ok := v.Validate(b).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Cells[{X:1 Y:2}](value).Mark"], HasError, validator.ErrMax)
+
+	v = v.WithOptions(validator.KeyFormatter(func(key reflect.Value) string {
+		p := key.Interface().(point)
+		return fmt.Sprintf("%d,%d", p.X, p.Y)
+	}))
+	errs, ok = v.Validate(b).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Cells[1,2](value).Mark"], HasError, validator.ErrMax)
+}
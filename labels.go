@@ -0,0 +1,138 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"strings"
+)
+
+// SetFieldLabel calls the SetFieldLabel method on the default validator.
+func SetFieldLabel(name, label string) {
+	setDefault(func() error {
+		defaultValidator.SetFieldLabel(name, label)
+		return nil
+	})
+}
+
+// SetFieldLabel sets the label errors of the field called name are shown
+// under in messages meant for users, such as "Pickup latitude" for
+// "origin_latitude". Name is either the key of the field without
+// indexes, such as "Lines.SKU" for the errors keyed "Lines[2].SKU", or
+// the name of the field alone, matching the fields of that name at any
+// depth. Labels can also be set with the label tag of fields:
+//
+//	type Ride struct {
+//		OriginLatitude float64 `json:"origin_latitude" label:"Pickup latitude" validate:"min=-90,max=90"`
+//	}
+//
+// Labels set with SetFieldLabel for a key win over label tags, which win
+// over those set for a name alone. An empty label removes the label.
+// Labels only change messages: errors are still keyed by field.
+func (mv *Validator) SetFieldLabel(name, label string) {
+	mv.own()
+	if label == "" {
+		delete(mv.labels, name)
+		return
+	}
+	mv.labels[name] = label
+}
+
+// Label returns the label of the field of v errors are keyed key under,
+// or key itself if the field has none. See SetFieldLabel.
+func (mv *Validator) Label(v interface{}, key string) string {
+	if label := mv.label(reflect.TypeOf(v), key); label != "" {
+		return label
+	}
+	return key
+}
+
+// label returns the label of the field of the type t errors are keyed
+// key under, or the empty string if it has none.
+func (mv *Validator) label(t reflect.Type, key string) string {
+	if key == "" {
+		return ""
+	}
+	path := stripIndexes(key)
+	if label, ok := mv.labels[path]; ok {
+		return label
+	}
+	if sf, ok := mv.fieldByKey(t, strings.Split(path, ".")); ok {
+		if label := sf.Tag.Get("label"); label != "" {
+			return label
+		}
+	}
+	return mv.labels[path[strings.LastIndex(path, ".")+1:]]
+}
+
+// fieldByKey returns the struct field found by following the names
+// errors are keyed by in parts from the type t.
+func (mv *Validator) fieldByKey(t reflect.Type, parts []string) (reflect.StructField, bool) {
+	for t != nil && t.Kind() != reflect.Struct {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+			t = t.Elem()
+		default:
+			t = nil
+		}
+	}
+	if t == nil {
+		return reflect.StructField{}, false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous && mv.fieldName(sf) != parts[0] {
+			// the fields of embedded structs are keyed as fields of t
+			if f, ok := mv.fieldByKey(sf.Type, parts); ok {
+				return f, true
+			}
+			continue
+		}
+		if mv.fieldName(sf) != parts[0] {
+			continue
+		}
+		if len(parts) == 1 {
+			return sf, true
+		}
+		return mv.fieldByKey(sf.Type, parts[1:])
+	}
+	return reflect.StructField{}, false
+}
+
+// stripIndexes returns key without the indexes and map keys of its
+// elements, as in "Lines.SKU" for "Lines[2].SKU".
+func stripIndexes(key string) string {
+	var b strings.Builder
+	depth := 0
+	for i := 0; i < len(key); i++ {
+		switch c := key[i]; {
+		case c == '[':
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+			// map elements are keyed as in "Map[k](value)"
+			for _, s := range []string{"(key)", "(value)"} {
+				if depth == 0 && strings.HasPrefix(key[i+1:], s) {
+					i += len(s)
+				}
+			}
+		case depth == 0:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,80 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"encoding/json"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestFieldLabels(c *C) {
+	type stop struct {
+		Address string `validate:"nonzero" label:"Stop address"`
+	}
+	type ride struct {
+		OriginLatitude float64 `json:"origin_latitude" validate:"min=-90,max=90"`
+		Stops          []stop  `json:"stops"`
+		Note           string  `json:"note" validate:"max=4"`
+	}
+	v := validator.NewValidator()
+	v.SetTag("validate")
+	v.SetNameTag("json")
+	v.SetFieldLabel("origin_latitude", "Pickup latitude")
+
+	r := v.Check(ride{OriginLatitude: -100, Stops: []stop{{"Main St"}, {}}, Note: "hello"})
+	c.Assert(r.Errors(), DeepEquals, []validator.FieldError{
+		{Field: "note", Err: validator.ErrMax},
+		{Field: "origin_latitude", Err: validator.ErrMin, Label: "Pickup latitude"},
+		{Field: "stops[1].Address", Err: validator.ErrZeroValue, Label: "Stop address"},
+	})
+	c.Assert(r.Errors()[1].Error(), Equals, "Pickup latitude: less than min")
+	c.Assert(r.Errors()[0].Error(), Equals, "note: greater than max")
+	c.Assert(r.Err().(validator.ErrorMap)["origin_latitude"], HasLen, 1)
+
+	b, err := json.Marshal(r.Errors()[1])
+	c.Assert(err, IsNil)
+	c.Assert(string(b), Equals, `{"field":"origin_latitude","label":"Pickup latitude","error":"less than min"}`)
+
+	// keys win over tags, and tags over names alone
+	v.SetFieldLabel("Address", "Address")
+	c.Assert(v.Label(ride{}, "stops[0].Address"), Equals, "Stop address")
+	v.SetFieldLabel("stops.Address", "Drop-off address")
+	c.Assert(v.Label(&ride{}, "stops[0].Address"), Equals, "Drop-off address")
+	c.Assert(v.Label(ride{}, "note"), Equals, "note")
+
+	v.SetFieldLabel("origin_latitude", "")
+	c.Assert(v.Label(ride{}, "origin_latitude"), Equals, "origin_latitude")
+}
+
+func (ms *MySuite) TestFieldLabelsOfMaps(c *C) {
+	type item struct {
+		Qty int `label:"Quantity"`
+	}
+	type cart struct {
+		Items map[string]item
+	}
+	v := validator.NewValidator()
+	c.Assert(v.Label(cart{}, "Items[a.b](value).Qty"), Equals, "Quantity")
+	c.Assert(v.Label(cart{}, "Items[x]"), Equals, "Items[x]")
+
+	derived := v.WithOptions()
+	derived.SetFieldLabel("Items", "Cart items")
+	c.Assert(derived.Label(cart{}, "Items[x]"), Equals, "Cart items")
+	c.Assert(v.Label(cart{}, "Items[x]"), Equals, "Items[x]")
+}
@@ -0,0 +1,72 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"strings"
+)
+
+// LimitProvider returns the value of the limit called name for a
+// validation, such as the attachment size allowed by the plan of the
+// tenant found in ctx. It returns false if there is no such limit, in
+// which case the constraint using it is not enforced.
+type LimitProvider func(ctx context.Context, name string) (string, bool)
+
+// limitTags holds the builtin constraints whose parameter may name a
+// limit, as in max=@attachmentLimit.
+var limitTags = map[string]bool{
+	"len":      true,
+	"min":      true,
+	"max":      true,
+	"minbytes": true,
+	"maxbytes": true,
+}
+
+// SetLimitProvider sets the limit provider of the default validator.
+// See Validator.SetLimitProvider.
+func SetLimitProvider(p LimitProvider) {
+	setDefault(func() error {
+		defaultValidator.SetLimitProvider(p)
+		return nil
+	})
+}
+
+// SetLimitProvider sets the provider asked, with the context of each
+// validation, for the limits named by the parameters of len, min, max,
+// minbytes and maxbytes constraints, as in max=@attachmentLimit, so that
+// limits that depend on the customer are enforced like any other.
+// Without a provider such constraints return ErrBadParameter. Functions
+// set in place of the builtin ones are given their parameters as written.
+func (mv *Validator) SetLimitProvider(p LimitProvider) {
+	mv.limits = p
+}
+
+// limit returns the parameter of the constraint t for the validation of
+// the field f, looking up the limit it names if any. It returns false if
+// the constraint is not to be enforced.
+func (mv *Validator) limit(f Field, t tag) (string, bool, error) {
+	if !limitTags[t.Name] || mv.custom[t.Name] || !strings.HasPrefix(t.Param, "@") {
+		return t.Param, true, nil
+	}
+	name := t.Param[1:]
+	if name == "" || mv.limits == nil {
+		return "", false, ErrBadParameter
+	}
+	param, ok := mv.limits(f.Context(), name)
+	return param, ok, nil
+}
@@ -0,0 +1,79 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestLimitProvider(c *C) {
+	type planKey struct{}
+	type message struct {
+		Attachments []string `validate:"max=@attachments"`
+		Subject     string   `validate:"min=1,max=@subject"`
+	}
+	plans := map[string]map[string]string{
+		"free": {"attachments": "1", "subject": "5"},
+		"pro":  {"attachments": "10"},
+	}
+	v := validator.NewValidator()
+	msg := message{Attachments: []string{"a", "b"}, Subject: "quarterly report"}
+
+	err := v.Validate(msg)
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Attachments"], HasError, validator.ErrBadParameter)
+
+	v.SetLimitProvider(func(ctx context.Context, name string) (string, bool) {
+		plan, _ := ctx.Value(planKey{}).(string)
+		limit, ok := plans[plan][name]
+		return limit, ok
+	})
+	err = v.ValidateContext(context.WithValue(context.Background(), planKey{}, "free"), msg)
+	c.Assert(err, NotNil)
+	errs := err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Attachments"], HasError, validator.ErrMax)
+	c.Assert(errs["Subject"], HasError, validator.ErrMax)
+
+	// limits the provider does not know are not enforced
+	err = v.ValidateContext(context.WithValue(context.Background(), planKey{}, "pro"), msg)
+	c.Assert(err, IsNil)
+	err = v.ValidateContext(context.Background(), message{})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Subject"], DeepEquals, validator.ErrorArray{validator.ErrMin})
+
+	err = v.Valid("abc", "max=@")
+	c.Assert(err, HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestLimitsCustomFunc(c *C) {
+	v := validator.NewValidator()
+	v.SetLimitProvider(func(context.Context, string) (string, bool) { return "1", true })
+	var got string
+	v.SetValidationFunc("max", func(_ interface{}, param string) error {
+		got = param
+		return nil
+	})
+	c.Assert(v.Valid("abc", "max=@subject"), IsNil)
+	c.Assert(got, Equals, "@subject")
+	// builtins are still given the limit
+	c.Assert(v.Valid("abc", "min=@subject"), IsNil)
+	c.Assert(v.Valid("", "min=@subject"), HasError, validator.ErrMin)
+}
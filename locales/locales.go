@@ -0,0 +1,48 @@
+// Package locales holds the number formats of the locales known to
+// package validator. It is registered by default and left out of builds
+// with the validator_minimal tag, which may import it and register the
+// locales they need.
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locales
+
+// NumberFormat describes how a locale writes decimal numbers, as
+// validator.NumberFormat, to which it converts.
+type NumberFormat struct {
+	// Decimal separates the integer part from the fraction digits.
+	Decimal string
+	// Group lists the separators accepted between groups of three
+	// digits of the integer part.
+	Group []string
+}
+
+// NumberFormats holds the number formats of locales, given as BCP 47
+// tags such as "fr" or "de-CH".
+var NumberFormats = map[string]NumberFormat{
+	"en":    {Decimal: ".", Group: []string{","}},
+	"ja":    {Decimal: ".", Group: []string{","}},
+	"zh":    {Decimal: ".", Group: []string{","}},
+	"de":    {Decimal: ",", Group: []string{"."}},
+	"es":    {Decimal: ",", Group: []string{"."}},
+	"it":    {Decimal: ",", Group: []string{"."}},
+	"nl":    {Decimal: ",", Group: []string{"."}},
+	"pt":    {Decimal: ",", Group: []string{"."}},
+	"fr":    {Decimal: ",", Group: []string{"\u202f", "\u00a0", " "}},
+	"pl":    {Decimal: ",", Group: []string{"\u00a0", " "}},
+	"ru":    {Decimal: ",", Group: []string{"\u00a0", " "}},
+	"sv":    {Decimal: ",", Group: []string{"\u00a0", " "}},
+	"de-CH": {Decimal: ".", Group: []string{"'", "’"}},
+}
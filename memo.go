@@ -0,0 +1,81 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"sync"
+)
+
+// Pure returns an Option marking the validation functions called names
+// as pure: their result depends on the value and the parameter only.
+// Within one validation, such as of a batch in which every line holds
+// the same currency code, each is then called once per distinct value
+// and parameter, and its result reused. Only the results for strings,
+// booleans and numbers are kept. Field validation functions, which may
+// depend on other fields, cannot be pure.
+func Pure(names ...string) Option {
+	return func(mv *Validator) {
+		pure := make(map[string]bool, len(mv.pure)+len(names))
+		for name := range mv.pure {
+			pure[name] = true
+		}
+		for _, name := range names {
+			pure[name] = true
+		}
+		mv.pure = pure
+	}
+}
+
+// memoKey identifies a call to a pure validation function.
+type memoKey struct {
+	name  string
+	param string
+	value interface{}
+}
+
+// memo holds the results of the calls to pure validation functions
+// within a validation.
+type memo struct {
+	mu      sync.Mutex
+	results map[memoKey]error
+}
+
+// call returns the result of the validation function of t for v and
+// param, calling it unless it was already called with them.
+func (m *memo) call(t tag, v interface{}, param string) error {
+	switch reflect.ValueOf(v).Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+	default:
+		return t.Fn(v, param)
+	}
+	key := memoKey{t.Name, param, v}
+	m.mu.Lock()
+	err, ok := m.results[key]
+	m.mu.Unlock()
+	if ok {
+		return err
+	}
+	err = t.Fn(v, param)
+	m.mu.Lock()
+	m.results[key] = err
+	m.mu.Unlock()
+	return err
+}
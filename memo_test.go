@@ -0,0 +1,67 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"errors"
+	"sync/atomic"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+var errCurrency = errors.New("unknown currency")
+
+func (ms *MySuite) TestPure(c *C) {
+	var calls int32
+	currency := func(v interface{}, param string) error {
+		atomic.AddInt32(&calls, 1)
+		if v.(string) != "EUR" && v.(string) != "USD" {
+			return errCurrency
+		}
+		return nil
+	}
+	type line struct {
+		Currency string `validate:"currency"`
+	}
+	type batch struct {
+		Lines []line
+	}
+	b := batch{}
+	for i := 0; i < 100; i++ {
+		b.Lines = append(b.Lines, line{Currency: "EUR"}, line{Currency: "XXX"})
+	}
+
+	mv := validator.NewValidator().WithOptions(validator.Pure("currency"))
+	c.Assert(mv.SetValidationFunc("currency", currency), IsNil)
+	err := mv.Validate(b)
+	c.Assert(err, NotNil)
+	errs := err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 100)
+	c.Assert(errs["Lines[199].Currency"], DeepEquals, validator.ErrorArray{errCurrency})
+	c.Assert(atomic.LoadInt32(&calls), Equals, int32(2))
+
+	// results are kept within one validation only
+	c.Assert(mv.Validate(b), NotNil)
+	c.Assert(atomic.LoadInt32(&calls), Equals, int32(4))
+
+	atomic.StoreInt32(&calls, 0)
+	plain := validator.NewValidator()
+	c.Assert(plain.SetValidationFunc("currency", currency), IsNil)
+	c.Assert(plain.Validate(b), NotNil)
+	c.Assert(atomic.LoadInt32(&calls), Equals, int32(200))
+}
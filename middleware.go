@@ -0,0 +1,100 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"reflect"
+)
+
+// Struct describes a struct value being validated.
+type Struct struct {
+	// Value is the struct.
+	Value reflect.Value
+	// Path is the key errors of the struct are reported under by
+	// Validate, such as "Order.Lines[2]", or the empty string for the
+	// value passed to Validate.
+	Path string
+	// Depth is the number of structs the struct is nested in.
+	Depth int
+}
+
+// StructFunc validates the fields of a struct. It returns an ErrorMap
+// of the errors found, keyed relative to the struct, or another error
+// for the struct itself.
+type StructFunc func(ctx context.Context, s Struct) error
+
+// Middleware wraps the validation of every struct, such as to time it,
+// limit the depth of nested values or skip some types.
+type Middleware func(next StructFunc) StructFunc
+
+// Use calls the Use method on the default validator.
+func Use(mw ...Middleware) {
+	setDefault(func() error {
+		defaultValidator.Use(mw...)
+		return nil
+	})
+}
+
+// Use adds middleware wrapping the validation of every struct. The first
+// middleware added is the outermost one. Middleware may call next, with
+// the same or another context, or return errors of its own.
+func (mv *Validator) Use(mw ...Middleware) {
+	mv.own()
+	mv.middleware = append(mv.middleware, mw...)
+}
+
+// runStruct validates the struct sv found under path through the
+// middleware of mv, adding the errors of its fields to m.
+func (mv *Validator) runStruct(w *walk, sv reflect.Value, path string, m ErrorMap) error {
+	if len(mv.middleware) == 0 {
+		return mv.validateStruct(w, sv, m)
+	}
+	full := joinKey(w.prefix, path)
+	prefix := w.prefix
+	w.depth++
+	w.prefix = full
+	defer func() {
+		w.depth--
+		w.prefix = prefix
+	}()
+
+	var fn StructFunc = func(ctx context.Context, s Struct) error {
+		prev := w.ctx
+		w.ctx = ctx
+		defer func() { w.ctx = prev }()
+		subm := make(ErrorMap)
+		if err := mv.validateStruct(w, s.Value, subm); err != nil {
+			return err
+		}
+		if len(subm) > 0 {
+			return subm
+		}
+		return nil
+	}
+	for i := len(mv.middleware) - 1; i >= 0; i-- {
+		fn = mv.middleware[i](fn)
+	}
+	err := fn(w.ctx, Struct{Value: sv, Path: full, Depth: w.depth - 1})
+	if errs, ok := err.(ErrorMap); ok {
+		for name, errarr := range errs {
+			m.add(name, errarr)
+		}
+		return nil
+	}
+	return err
+}
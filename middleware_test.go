@@ -0,0 +1,101 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestMiddleware(c *C) {
+	type leaf struct {
+		Name string `validate:"nonzero"`
+	}
+	type branch struct {
+		Leaf   leaf
+		Leaves []leaf
+	}
+	type root struct {
+		ID     string `validate:"nonzero"`
+		Branch branch
+	}
+	value := root{Branch: branch{Leaves: []leaf{{}, {Name: "x"}}}}
+
+	v := validator.NewValidator()
+	var visited []string
+	v.Use(func(next validator.StructFunc) validator.StructFunc {
+		return func(ctx context.Context, s validator.Struct) error {
+			visited = append(visited, s.Value.Type().Name()+"@"+s.Path)
+			return next(ctx, s)
+		}
+	})
+	err := v.Validate(value)
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap), HasLen, 3)
+	c.Assert(visited, DeepEquals, []string{
+		"root@", "branch@Branch", "leaf@Branch.Leaf", "leaf@Branch.Leaves[0]", "leaf@Branch.Leaves[1]",
+	})
+
+	errTooDeep := errors.New("too deep")
+	limited := v.WithTag("validate")
+	limited.Use(func(next validator.StructFunc) validator.StructFunc {
+		return func(ctx context.Context, s validator.Struct) error {
+			if s.Depth > 1 {
+				return errTooDeep
+			}
+			return next(ctx, s)
+		}
+	})
+	err = limited.Validate(value)
+	c.Assert(err, NotNil)
+	errs := err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 4, Commentf("%v", errs))
+	c.Assert(errs["ID"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["Branch.Leaf"], HasError, errTooDeep)
+	c.Assert(errs["Branch.Leaves[0]"], HasError, errTooDeep)
+
+	type ctxKey struct{}
+	skip := validator.NewValidator()
+	skip.SetValidationFunc("flagged", func(interface{}, string) error { return nil })
+	skip.SetFieldValidationFunc("strict", func(i interface{}, f validator.Field, param string) error {
+		if f.Context().Value(ctxKey{}) == true {
+			return errors.New("strict")
+		}
+		return nil
+	})
+	skip.Use(func(next validator.StructFunc) validator.StructFunc {
+		return func(ctx context.Context, s validator.Struct) error {
+			if s.Value.Type() == reflect.TypeOf(leaf{}) {
+				return nil
+			}
+			return next(context.WithValue(ctx, ctxKey{}, true), s)
+		}
+	})
+	type strict struct {
+		A    string `validate:"strict"`
+		Leaf leaf
+	}
+	err = skip.Validate(strict{})
+	c.Assert(err, NotNil)
+	errs = err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 1, Commentf("%v", errs))
+	c.Assert(errs["A"], HasLen, 1)
+}
@@ -0,0 +1,124 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var (
+	// ErrAmount is the error returned when a value is not a valid
+	// decimal amount
+	ErrAmount = TextErr{errors.New("invalid amount")}
+	// ErrCurrency is the error returned when a currency code is not
+	// a known ISO 4217 code
+	ErrCurrency = TextErr{errors.New("unknown currency")}
+	// ErrPrecision is the error returned when a value has more
+	// decimal places than allowed
+	ErrPrecision = TextErr{errors.New("too many decimal places")}
+)
+
+// decimalPattern matches plain decimal numbers such as "-12.50"
+var decimalPattern = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+
+// currencyDigits holds the number of minor unit digits of the
+// active ISO 4217 currencies.
+var currencyDigits = map[string]int{
+	"AED": 2, "AFN": 2, "ALL": 2, "AMD": 2, "ANG": 2, "AOA": 2, "ARS": 2, "AUD": 2,
+	"AWG": 2, "AZN": 2, "BAM": 2, "BBD": 2, "BDT": 2, "BGN": 2, "BHD": 3, "BIF": 0,
+	"BMD": 2, "BND": 2, "BOB": 2, "BOV": 2, "BRL": 2, "BSD": 2, "BTN": 2, "BWP": 2,
+	"BYN": 2, "BZD": 2, "CAD": 2, "CDF": 2, "CHE": 2, "CHF": 2, "CHW": 2, "CLF": 4,
+	"CLP": 0, "CNY": 2, "COP": 2, "COU": 2, "CRC": 2, "CUC": 2, "CUP": 2, "CVE": 2,
+	"CZK": 2, "DJF": 0, "DKK": 2, "DOP": 2, "DZD": 2, "EGP": 2, "ERN": 2, "ETB": 2,
+	"EUR": 2, "FJD": 2, "FKP": 2, "GBP": 2, "GEL": 2, "GHS": 2, "GIP": 2, "GMD": 2,
+	"GNF": 0, "GTQ": 2, "GYD": 2, "HKD": 2, "HNL": 2, "HTG": 2, "HUF": 2, "IDR": 2,
+	"ILS": 2, "INR": 2, "IQD": 3, "IRR": 2, "ISK": 0, "JMD": 2, "JOD": 3, "JPY": 0,
+	"KES": 2, "KGS": 2, "KHR": 2, "KMF": 0, "KPW": 2, "KRW": 0, "KWD": 3, "KYD": 2,
+	"KZT": 2, "LAK": 2, "LBP": 2, "LKR": 2, "LRD": 2, "LSL": 2, "LYD": 3, "MAD": 2,
+	"MDL": 2, "MGA": 2, "MKD": 2, "MMK": 2, "MNT": 2, "MOP": 2, "MRU": 2, "MUR": 2,
+	"MVR": 2, "MWK": 2, "MXN": 2, "MXV": 2, "MYR": 2, "MZN": 2, "NAD": 2, "NGN": 2,
+	"NIO": 2, "NOK": 2, "NPR": 2, "NZD": 2, "OMR": 3, "PAB": 2, "PEN": 2, "PGK": 2,
+	"PHP": 2, "PKR": 2, "PLN": 2, "PYG": 0, "QAR": 2, "RON": 2, "RSD": 2, "RUB": 2,
+	"RWF": 0, "SAR": 2, "SBD": 2, "SCR": 2, "SDG": 2, "SEK": 2, "SGD": 2, "SHP": 2,
+	"SLE": 2, "SLL": 2, "SOS": 2, "SRD": 2, "SSP": 2, "STN": 2, "SVC": 2, "SYP": 2,
+	"SZL": 2, "THB": 2, "TJS": 2, "TMT": 2, "TND": 3, "TOP": 2, "TRY": 2, "TTD": 2,
+	"TWD": 2, "TZS": 2, "UAH": 2, "UGX": 0, "USD": 2, "USN": 2, "UYI": 0, "UYU": 2,
+	"UYW": 4, "UZS": 2, "VED": 2, "VES": 2, "VND": 0, "VUV": 0, "WST": 2, "XAF": 0,
+	"XCD": 2, "XOF": 0, "XPF": 0, "YER": 2, "ZAR": 2, "ZMW": 2, "ZWL": 2,
+}
+
+// amount is the builtin validation function that checks whether a
+// string is a decimal amount with no more fraction digits than the
+// currency held by the field named in the parameter allows, as in
+// amount=currencyfield:Currency. Optional minimum and maximum bounds
+// follow the field name, as in amount=currencyfield:Currency:0.01:1000.
+// Values are compared as exact decimals, never as floats, and may be
+// written in the format of the locale set with WithLocale.
+func amount(v interface{}, f Field, param string) error {
+	params := strings.Split(param, ":")
+	if len(params) < 2 || len(params) > 4 || params[0] != "currencyfield" || params[1] == "" {
+		return ErrBadParameter
+	}
+	var bounds [2]*big.Rat
+	for i, p := range params[2:] {
+		if p == "" {
+			continue
+		}
+		if !decimalPattern.MatchString(p) {
+			return ErrBadParameter
+		}
+		bounds[i], _ = new(big.Rat).SetString(p)
+	}
+	cur, ok := f.Sibling(params[1])
+	if !ok || cur.Kind() != reflect.String {
+		return ErrBadParameter
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.String {
+		return ErrUnsupported
+	}
+	s, ok := plainNumber(f.Context(), rv.String())
+	if !ok {
+		return ErrAmount
+	}
+	digits, ok := currencyDigits[cur.String()]
+	if !ok {
+		return ErrCurrency
+	}
+	if i := strings.IndexByte(s, '.'); i >= 0 && len(s)-i-1 > digits {
+		return ErrPrecision
+	}
+	a, _ := new(big.Rat).SetString(s)
+	if bounds[0] != nil && a.Cmp(bounds[0]) < 0 {
+		return ErrMin
+	}
+	if bounds[1] != nil && a.Cmp(bounds[1]) > 0 {
+		return ErrMax
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestAmount(c *C) {
+	type test struct {
+		Currency string
+		Price    string  `validate:"amount=currencyfield:Currency"`
+		Total    *string `validate:"amount=currencyfield:Currency:0.01:1000"`
+	}
+	total := "1000.00"
+	err := validator.Validate(test{Currency: "USD", Price: "10", Total: &total})
+	c.Assert(err, IsNil)
+	err = validator.Validate(test{Currency: "KWD", Price: "0.125"})
+	c.Assert(err, IsNil)
+
+	total = "1000.01"
+	err = validator.Validate(test{Currency: "JPY", Price: "10.5", Total: &total})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Price"], HasError, validator.ErrPrecision)
+	c.Assert(errs["Total"], HasError, validator.ErrPrecision)
+
+	total = "0.00"
+	err = validator.Validate(test{Currency: "EUR", Price: "1e3", Total: &total})
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Price"], HasError, validator.ErrAmount)
+	c.Assert(errs["Total"], HasError, validator.ErrMin)
+
+	total = "1000.001"
+	err = validator.Validate(test{Currency: "BHD", Price: "-1", Total: &total})
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Price"], IsNil)
+	c.Assert(errs["Total"], HasError, validator.ErrMax)
+
+	err = validator.Validate(test{Currency: "XXY", Price: "1"})
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Price"], HasError, validator.ErrCurrency)
+
+	type badParam struct {
+		A string `validate:"amount=currencyfield:Missing"`
+		B string `validate:"amount=currencyfield:C:x"`
+		C string
+		D string `validate:"amount=C"`
+		E string `validate:"amount=currencyfield"`
+	}
+	err = validator.Validate(badParam{C: "USD"})
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["A"], HasError, validator.ErrBadParameter)
+	c.Assert(errs["B"], HasError, validator.ErrBadParameter)
+	c.Assert(errs["D"], HasError, validator.ErrBadParameter)
+	c.Assert(errs["E"], HasError, validator.ErrBadParameter)
+
+	err = validator.Valid("10.00", "amount=currencyfield:Currency")
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+}
@@ -0,0 +1,181 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrNumber is the error returned when a string is not a number
+	ErrNumber = TextErr{errors.New("invalid number")}
+	// ErrInteger is the error returned when a string is not an integer
+	ErrInteger = TextErr{errors.New("invalid integer")}
+)
+
+// NumberFormat describes how a locale writes decimal numbers.
+type NumberFormat struct {
+	// Decimal separates the integer part from the fraction digits.
+	Decimal string
+	// Group lists the separators accepted between groups of three
+	// digits of the integer part.
+	Group []string
+}
+
+var (
+	numberFormatsMu sync.RWMutex
+	numberFormats   = map[string]NumberFormat{}
+)
+
+// RegisterNumberFormat sets the number format of a locale, given as a
+// BCP 47 tag such as "fr" or "de-CH". Locales without a format of their
+// own use the format of their language. Calling this function with the
+// zero NumberFormat removes the locale.
+func RegisterNumberFormat(locale string, nf NumberFormat) {
+	numberFormatsMu.Lock()
+	defer numberFormatsMu.Unlock()
+	if nf.Decimal == "" && len(nf.Group) == 0 {
+		delete(numberFormats, locale)
+		return
+	}
+	numberFormats[locale] = nf
+}
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx in which floatstr, intstr and amount
+// accept numbers written in the format of the given locale, such as
+// "1 234,56" for "fr-FR", when passed to ValidateContext or ValidContext.
+// Numbers are read in the format of the locale first, so that "1.234"
+// is 1234 in "de", and then as plain numbers such as "1234.56".
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, locale)
+}
+
+// numberFormat returns the number format of the locale set on ctx.
+func numberFormat(ctx context.Context) (NumberFormat, bool) {
+	locale, _ := ctx.Value(localeKey{}).(string)
+	if locale == "" {
+		return NumberFormat{}, false
+	}
+	numberFormatsMu.RLock()
+	defer numberFormatsMu.RUnlock()
+	locale = strings.Replace(locale, "_", "-", -1)
+	if nf, ok := numberFormats[locale]; ok {
+		return nf, true
+	}
+	nf, ok := numberFormats[strings.SplitN(locale, "-", 2)[0]]
+	return nf, ok
+}
+
+// plainNumber returns s as a plain decimal number, such as "-1234.56".
+// When a locale is set on ctx, s is read in its number format first.
+func plainNumber(ctx context.Context, s string) (string, bool) {
+	if n, ok := localNumber(ctx, s); ok {
+		return n, true
+	}
+	return s, decimalPattern.MatchString(s)
+}
+
+// localNumber returns s as a plain decimal number if it is written in
+// the number format of the locale set on ctx.
+func localNumber(ctx context.Context, s string) (string, bool) {
+	nf, ok := numberFormat(ctx)
+	if !ok {
+		return "", false
+	}
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	integer, fraction := s, ""
+	if nf.Decimal != "" {
+		if i := strings.LastIndex(s, nf.Decimal); i >= 0 {
+			integer, fraction = s[:i], s[i+len(nf.Decimal):]
+			if fraction == "" {
+				return "", false
+			}
+		}
+	}
+	for _, sep := range nf.Group {
+		if !strings.Contains(integer, sep) {
+			continue
+		}
+		groups := strings.Split(integer, sep)
+		for i, g := range groups {
+			if g == "" || len(g) > 3 || i > 0 && len(g) != 3 {
+				return "", false
+			}
+		}
+		integer = strings.Join(groups, "")
+		break
+	}
+	n := sign + integer
+	if fraction != "" {
+		n += "." + fraction
+	}
+	return n, decimalPattern.MatchString(n)
+}
+
+// floatstr is the builtin validation function that checks whether a
+// string holds a finite decimal number, written either plainly or in
+// the format of the locale set with WithLocale. Exponents, hexadecimal
+// floats, "Inf" and "NaN" are not decimal numbers.
+func floatstr(v interface{}, f Field, param string) error {
+	if param != "" {
+		return ErrBadParameter
+	}
+	s, ok, err := stringValue(v)
+	if !ok {
+		return err
+	}
+	n, ok := plainNumber(f.Context(), s)
+	if !ok {
+		return ErrNumber
+	}
+	x, err := strconv.ParseFloat(n, 64)
+	if err != nil || math.IsInf(x, 0) {
+		return ErrNumber
+	}
+	return nil
+}
+
+// intstr is the builtin validation function that checks whether a
+// string holds a 64-bit integer, written either plainly or in the format
+// of the locale set with WithLocale.
+func intstr(v interface{}, f Field, param string) error {
+	if param != "" {
+		return ErrBadParameter
+	}
+	s, ok, err := stringValue(v)
+	if !ok {
+		return err
+	}
+	n, ok := plainNumber(f.Context(), s)
+	if !ok || strings.Contains(n, ".") {
+		return ErrInteger
+	}
+	if _, err := strconv.ParseInt(n, 10, 64); err != nil {
+		return ErrInteger
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+	"strings"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestNumberStrings(c *C) {
+	type row struct {
+		Price    string `validate:"floatstr"`
+		Quantity string `validate:"intstr"`
+	}
+	err := validator.Validate(row{Price: "-12500.5", Quantity: "-42"})
+	c.Assert(err, IsNil)
+
+	err = validator.Validate(row{Price: "1 234,56", Quantity: "4.2"})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Price"], HasError, validator.ErrNumber)
+	c.Assert(errs["Quantity"], HasError, validator.ErrInteger)
+
+	err = validator.Validate(row{Price: "Inf", Quantity: "99999999999999999999"})
+	c.Assert(err, NotNil)
+	errs = err.(validator.ErrorMap)
+	c.Assert(errs["Price"], HasError, validator.ErrNumber)
+	c.Assert(errs["Quantity"], HasError, validator.ErrInteger)
+
+	fr := validator.WithLocale(context.Background(), "fr-FR")
+	err = validator.ValidateContext(fr, row{Price: "1 234,56", Quantity: "12 345"})
+	c.Assert(err, IsNil)
+	err = validator.ValidateContext(fr, row{Price: "1234.56", Quantity: "1234"})
+	c.Assert(err, IsNil, Commentf("plain numbers are accepted in every locale"))
+	err = validator.ValidateContext(fr, row{Price: "12 34,5", Quantity: "1,5"})
+	c.Assert(err, NotNil)
+	errs = err.(validator.ErrorMap)
+	c.Assert(errs["Price"], HasError, validator.ErrNumber)
+	c.Assert(errs["Quantity"], HasError, validator.ErrInteger)
+
+	de := validator.WithLocale(context.Background(), "de_AT")
+	err = validator.ValidContext(de, "1.234", "intstr")
+	c.Assert(err, IsNil)
+
+	validator.RegisterNumberFormat("en-IN", validator.NumberFormat{Decimal: ".", Group: []string{","}})
+	defer validator.RegisterNumberFormat("en-IN", validator.NumberFormat{})
+	err = validator.ValidContext(validator.WithLocale(context.Background(), "en-IN"), "1,234.5", "floatstr")
+	c.Assert(err, IsNil)
+	err = validator.ValidContext(validator.WithLocale(context.Background(), "xx"), "1,234.5", "floatstr")
+	c.Assert(err, NotNil)
+
+	err = validator.Valid("1", "intstr=10")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+	err = validator.Valid(1.5, "floatstr")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrUnsupported)
+}
+
+func (ms *MySuite) TestFloatstrDecimalOnly(c *C) {
+	for _, s := range []string{"1e3", "1E-2", "0x1p-2", "Inf", "-Inf", "NaN", "+1.5", ".5", "1."} {
+		err := validator.Valid(s, "floatstr")
+		c.Assert(err, NotNil, Commentf("%q", s))
+		c.Assert(err.(validator.ErrorArray), HasError, validator.ErrNumber)
+	}
+	err := validator.Valid("1"+strings.Repeat("0", 400), "floatstr")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrNumber)
+	err = validator.Valid("-0.25", "floatstr")
+	c.Assert(err, IsNil)
+}
+
+func (ms *MySuite) TestAmountLocale(c *C) {
+	type invoice struct {
+		Currency string
+		Total    string `validate:"amount=currencyfield:Currency:0:10000"`
+	}
+	de := validator.WithLocale(context.Background(), "de-DE")
+	err := validator.ValidateContext(de, invoice{Currency: "EUR", Total: "1.234,50"})
+	c.Assert(err, IsNil)
+	err = validator.ValidateContext(de, invoice{Currency: "EUR", Total: "12.345,00"})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Total"], HasError, validator.ErrMax)
+	err = validator.ValidateContext(de, invoice{Currency: "EUR", Total: "1,234"})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Total"], HasError, validator.ErrPrecision)
+	err = validator.Validate(invoice{Currency: "EUR", Total: "1.234,50"})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Total"], HasError, validator.ErrAmount)
+}
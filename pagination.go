@@ -0,0 +1,89 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrBase64 is the error returned when a value is not
+	// valid base64
+	ErrBase64 = TextErr{errors.New("invalid base64")}
+	// ErrExclusive is the error returned when a field is set
+	// along with a field it is mutually exclusive with
+	ErrExclusive = TextErr{errors.New("mutually exclusive fields set")}
+)
+
+// Pagination holds the parameters of list requests and is meant to be
+// embedded in them. A request pages either by cursor or by offset, and
+// the limit is validated by the pagelimit alias, which defaults to at
+// most 100 items and can be changed with SetAlias.
+type Pagination struct {
+	Cursor string `json:"cursor,omitempty" validate:"base64url,exclusive=Offset"`
+	Limit  int    `json:"limit,omitempty" validate:"pagelimit"`
+	Offset int    `json:"offset,omitempty" validate:"min=0"`
+}
+
+// base64url is the builtin validation function that checks whether a
+// string is URL-safe base64, as used by opaque cursors, with or without
+// padding. Empty strings are valid.
+func base64url(v interface{}, param string) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.String {
+		return ErrUnsupported
+	}
+	s := strings.TrimRight(rv.String(), "=")
+	if _, err := base64.RawURLEncoding.DecodeString(s); err != nil {
+		return ErrBase64
+	}
+	return nil
+}
+
+// exclusive is the builtin validation function that checks whether at
+// most one of the field and the named fields is set, that is holds a
+// value other than its zero value: exclusive=Offset Page.
+func exclusive(v interface{}, f Field, param string) error {
+	names := splitParams(param)
+	if len(names) == 0 {
+		return ErrBadParameter
+	}
+	set := v != nil && !reflect.ValueOf(v).IsZero()
+	for _, name := range names {
+		other, ok := f.Sibling(name)
+		if !ok {
+			return ErrBadParameter
+		}
+		if other.IsZero() {
+			continue
+		}
+		if set {
+			return ErrExclusive
+		}
+		set = true
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestPagination(c *C) {
+	type listRequest struct {
+		validator.Pagination
+		Query string
+	}
+	err := validator.Validate(listRequest{Pagination: validator.Pagination{Cursor: "eyJpZCI6NDJ9", Limit: 50}})
+	c.Assert(err, IsNil)
+	err = validator.Validate(listRequest{Pagination: validator.Pagination{Offset: 20}})
+	c.Assert(err, IsNil)
+
+	err = validator.Validate(listRequest{Pagination: validator.Pagination{Cursor: "a+b/", Limit: 101, Offset: -1}})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 3)
+	c.Assert(errs["Pagination.Cursor"], HasError, validator.ErrBase64)
+	c.Assert(errs["Pagination.Cursor"], HasError, validator.ErrExclusive)
+	c.Assert(errs["Pagination.Limit"], HasError, validator.ErrMax)
+	c.Assert(errs["Pagination.Offset"], HasError, validator.ErrMin)
+
+	v := validator.NewValidator()
+	c.Assert(v.SetAlias("pagelimit", "min=1,max=500"), IsNil)
+	err = v.Validate(listRequest{Pagination: validator.Pagination{Limit: 200}})
+	c.Assert(err, IsNil)
+	err = v.Validate(listRequest{})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Pagination.Limit"], HasError, validator.ErrMin)
+}
+
+func (ms *MySuite) TestAlias(c *C) {
+	v := validator.NewValidator()
+	c.Assert(v.SetAlias("username", "min=3,max=8,regexp=^[a-z]+$"), IsNil)
+	c.Assert(v.SetAlias("handle", "username,nonzero"), IsNil)
+	c.Assert(v.SetAlias("loop", "nonzero,loop"), IsNil)
+	c.Assert(v.SetAlias("", "nonzero"), NotNil)
+	type test struct {
+		A string `validate:"username"`
+		B string `validate:"handle"`
+		C string `validate:"loop"`
+		D string `validate:"username=foo"`
+	}
+	err := v.Validate(test{A: "ab", B: "Abcdefghi"})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 4)
+	c.Assert(errs["A"], HasError, validator.ErrMin)
+	c.Assert(errs["B"], HasError, validator.ErrMax)
+	c.Assert(errs["B"], HasError, validator.ErrRegexp)
+	c.Assert(errs["C"], HasError, validator.ErrUnknownTag)
+	c.Assert(errs["D"], HasError, validator.ErrBadParameter)
+
+	c.Assert(v.SetAlias("username", ""), IsNil)
+	err = v.Valid("ab", "username")
+	c.Assert(err, Equals, validator.ErrUnknownTag)
+}
@@ -0,0 +1,179 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrSimilar is the error returned when a value is too close to
+	// the value of another field
+	ErrSimilar = TextErr{errors.New("too similar to another field")}
+	// ErrEntropy is the error returned when a value is too easy to
+	// guess
+	ErrEntropy = TextErr{errors.New("not random enough")}
+)
+
+// notsimilar is the builtin validation function that checks whether a
+// string is more than a given number of edits away from the values of
+// the fields named in the parameter: notsimilar=Username Email:3. The
+// distance defaults to 2 and comparisons ignore case. The local part of
+// values that look like email addresses is compared as well.
+func notsimilar(v interface{}, f Field, param string) error {
+	dist := int64(2)
+	if i := strings.LastIndexByte(param, ':'); i >= 0 {
+		var err error
+		if dist, err = asInt(param[i+1:]); err != nil || dist < 0 {
+			return ErrBadParameter
+		}
+		param = param[:i]
+	}
+	names := splitParams(param)
+	if len(names) == 0 {
+		return ErrBadParameter
+	}
+	s, ok, err := stringValue(v)
+	if !ok {
+		return err
+	}
+	s = strings.ToLower(s)
+	for _, name := range names {
+		other, ok := f.Sibling(name)
+		if !ok {
+			return ErrBadParameter
+		}
+		if other.Kind() == reflect.Ptr || other.Kind() == reflect.Interface {
+			continue
+		}
+		if other.Kind() != reflect.String {
+			return ErrBadParameter
+		}
+		o := strings.ToLower(other.String())
+		candidates := []string{o}
+		if i := strings.LastIndexByte(o, '@'); i > 0 {
+			candidates = append(candidates, o[:i])
+		}
+		for _, c := range candidates {
+			if c != "" && int64(levenshtein(s, c)) <= dist {
+				return ErrSimilar
+			}
+		}
+	}
+	return nil
+}
+
+// levenshtein returns the number of single rune insertions, deletions
+// and substitutions needed to turn a into b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	row := make([]int, len(rb)+1)
+	for j := range row {
+		row[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		prev := row[0]
+		row[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur := row[j]
+			row[j] = min3(row[j]+1, row[j-1]+1, prev+cost)
+			prev = cur
+		}
+	}
+	return row[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// minentropy is the builtin validation function that checks whether the
+// estimated entropy of a string, in bits, is at least the parameter. See
+// entropy for how it is estimated.
+func minentropy(v interface{}, param string) error {
+	min, err := asFloat(param)
+	if err != nil || min < 0 {
+		return ErrBadParameter
+	}
+	s, ok, err := stringValue(v)
+	if !ok {
+		return err
+	}
+	if entropy(s) < min {
+		return ErrEntropy
+	}
+	return nil
+}
+
+// entropy estimates the entropy of a string in bits as the number of
+// characters times log2 of the size of the pool they are drawn from,
+// given by the classes of characters used: lower case, upper case,
+// digits, ASCII symbols and other characters. Characters repeating the
+// previous one or extending a run such as "abc" or "321" do not count.
+func entropy(s string) float64 {
+	var lower, upper, digit, symbol, other bool
+	n := 0
+	rs := []rune(s)
+	for i, r := range rs {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r >= ' ' && r < utf8.RuneSelf:
+			symbol = true
+		default:
+			other = true
+		}
+		if i > 0 && r == rs[i-1] {
+			continue
+		}
+		if i > 1 && r-rs[i-1] == rs[i-1]-rs[i-2] && (r-rs[i-1] == 1 || r-rs[i-1] == -1) {
+			continue
+		}
+		n++
+	}
+	pool := 0
+	for _, c := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if c.used {
+			pool += c.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+	return float64(n) * math.Log2(float64(pool))
+}
@@ -0,0 +1,103 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestNotSimilar(c *C) {
+	type account struct {
+		Username string
+		Email    *string
+		Password string `validate:"notsimilar=Username Email:3"`
+		Handle   string `validate:"notsimilar=Username\\,Email"`
+	}
+	email := "jane.doe@example.com"
+	err := validator.Validate(account{
+		Username: "janedoe",
+		Email:    &email,
+		Password: "correct horse battery",
+		Handle:   "jdoe",
+	})
+	c.Assert(err, IsNil)
+
+	err = validator.Validate(account{
+		Username: "janedoe",
+		Email:    &email,
+		Password: "JaneDoe99",
+		Handle:   "jane.doe1",
+	})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Password"], HasError, validator.ErrSimilar)
+	c.Assert(errs["Handle"], HasError, validator.ErrSimilar)
+
+	// nil and empty fields are not compared
+	err = validator.Validate(account{Password: "x", Handle: "y"})
+	c.Assert(err, IsNil)
+
+	// distance is counted in runes
+	err = validator.Validate(account{Username: "żółw", Password: "zolw", Handle: "turtle"})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Password"], HasError, validator.ErrSimilar)
+
+	type bad struct {
+		A string `validate:"notsimilar=Missing"`
+		B string `validate:"notsimilar=A:x"`
+		C int    `validate:"notsimilar=A"`
+		D string `validate:"notsimilar=C"`
+	}
+	err = validator.Validate(bad{D: "d"})
+	c.Assert(err, NotNil)
+	errs = err.(validator.ErrorMap)
+	c.Assert(errs["A"], HasError, validator.ErrBadParameter)
+	c.Assert(errs["B"], HasError, validator.ErrBadParameter)
+	c.Assert(errs["C"], HasError, validator.ErrUnsupported)
+	c.Assert(errs["D"], HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestMinEntropy(c *C) {
+	type secrets struct {
+		Password string  `validate:"minentropy=40"`
+		APIKey   *string `validate:"minentropy=128"`
+	}
+	key := "q8Zt1xV0mKp3RwL9sJd7Hc2Nf6Gb4Ya5"
+	err := validator.Validate(secrets{Password: "Tr0ub4dor&3", APIKey: &key})
+	c.Assert(err, IsNil)
+	err = validator.Validate(secrets{Password: "correct horse battery staple"})
+	c.Assert(err, IsNil)
+
+	for _, weak := range []string{"", "password", "aaaaaaaaaaaaaaaaaaaaaaaa", "abcdefghijklmnopqrstuvwx", "9876543210123456789"} {
+		err = validator.Validate(secrets{Password: weak})
+		c.Assert(err, NotNil, Commentf("%q", weak))
+		c.Assert(err.(validator.ErrorMap)["Password"], HasError, validator.ErrEntropy)
+	}
+
+	key = "0123456789abcdef0123456789abcdef"
+	err = validator.Validate(secrets{Password: "Tr0ub4dor&3", APIKey: &key})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["APIKey"], HasError, validator.ErrEntropy)
+
+	err = validator.Valid("secret", "minentropy=x")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+	err = validator.Valid(42, "minentropy=10")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrUnsupported)
+}
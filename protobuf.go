@@ -0,0 +1,84 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"reflect"
+	"sync"
+	"time"
+)
+
+// protoWrappers unwraps the protobuf well-known types, by full name, so
+// that the fields of generated messages using them are validated like
+// plain fields without depending on the protobuf module: a nil message
+// holds no value, wrappers such as google.protobuf.StringValue hold their
+// Value, Timestamp holds a time.Time and Duration a time.Duration.
+var protoWrappers = map[string]UnwrapFunc{
+	"google.protobuf.DoubleValue": protoValue,
+	"google.protobuf.FloatValue":  protoValue,
+	"google.protobuf.Int64Value":  protoValue,
+	"google.protobuf.UInt64Value": protoValue,
+	"google.protobuf.Int32Value":  protoValue,
+	"google.protobuf.UInt32Value": protoValue,
+	"google.protobuf.BoolValue":   protoValue,
+	"google.protobuf.StringValue": protoValue,
+	"google.protobuf.BytesValue":  protoValue,
+	"google.protobuf.Timestamp": func(v reflect.Value) (reflect.Value, bool) {
+		seconds, nanos := v.FieldByName("Seconds"), v.FieldByName("Nanos")
+		if !seconds.IsValid() || !nanos.IsValid() {
+			return reflect.Value{}, false
+		}
+		return reflect.ValueOf(time.Unix(seconds.Int(), nanos.Int()).UTC()), true
+	},
+	"google.protobuf.Duration": func(v reflect.Value) (reflect.Value, bool) {
+		seconds, nanos := v.FieldByName("Seconds"), v.FieldByName("Nanos")
+		if !seconds.IsValid() || !nanos.IsValid() {
+			return reflect.Value{}, false
+		}
+		d := time.Duration(seconds.Int())*time.Second + time.Duration(nanos.Int())
+		return reflect.ValueOf(d), true
+	},
+}
+
+// protoValue unwraps the wrapper messages of package wrapperspb.
+func protoValue(v reflect.Value) (reflect.Value, bool) {
+	value := v.FieldByName("Value")
+	return value, value.IsValid()
+}
+
+// protoNames caches the full names of the protobuf messages by Go type,
+// or the empty string for other types.
+var protoNames sync.Map
+
+// protoName returns the full name of the protobuf message of struct type
+// t, that is (*T).ProtoReflect().Descriptor().FullName(), or the empty
+// string if t is not a generated message.
+func protoName(t reflect.Type) string {
+	if name, ok := protoNames.Load(t); ok {
+		return name.(string)
+	}
+	var name string
+	if m, ok := callMethod(reflect.New(t), "ProtoReflect"); ok && !(m.Kind() == reflect.Interface && m.IsNil()) {
+		if d, ok := callMethod(m, "Descriptor"); ok && !(d.Kind() == reflect.Interface && d.IsNil()) {
+			if n, ok := callMethod(d, "FullName"); ok && n.Kind() == reflect.String {
+				name = n.String()
+			}
+		}
+	}
+	protoNames.Store(t, name)
+	return name
+}
@@ -0,0 +1,140 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+// The types below stand for the generated protobuf well-known types,
+// which are recognized by the full names of their descriptors.
+
+type wktFullName string
+
+type wktDescriptor struct {
+	name wktFullName
+}
+
+func (d wktDescriptor) FullName() wktFullName {
+	return d.name
+}
+
+type wktMessage struct {
+	name wktFullName
+}
+
+func (m wktMessage) Descriptor() wktDescriptor {
+	return wktDescriptor{m.name}
+}
+
+type StringValue struct {
+	Value string
+}
+
+func (*StringValue) ProtoReflect() wktMessage {
+	return wktMessage{"google.protobuf.StringValue"}
+}
+
+type Int32Value struct {
+	Value int32
+}
+
+func (*Int32Value) ProtoReflect() wktMessage {
+	return wktMessage{"google.protobuf.Int32Value"}
+}
+
+type Timestamp struct {
+	Seconds int64
+	Nanos   int32
+}
+
+func (*Timestamp) ProtoReflect() wktMessage {
+	return wktMessage{"google.protobuf.Timestamp"}
+}
+
+type Duration struct {
+	Seconds int64
+	Nanos   int32
+}
+
+func (*Duration) ProtoReflect() wktMessage {
+	return wktMessage{"google.protobuf.Duration"}
+}
+
+func (ms *MySuite) TestProtobufWellKnownTypes(c *C) {
+	type ride struct {
+		Note      *StringValue `validate:"nonzero,max=5"`
+		Seats     *Int32Value  `validate:"min=1,max=4"`
+		PickupAt  *Timestamp   `validate:"nonzero,after=2020-01-01,before=now"`
+		Timeout   *Duration    `validate:"min=1s,max=1h"`
+		DropoffAt *Timestamp   `validate:"after=2020-01-01"`
+	}
+	past := time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC).Unix()
+	r := ride{
+		Note:     &StringValue{},
+		PickupAt: &Timestamp{Seconds: past},
+		Timeout:  &Duration{Seconds: 30},
+	}
+	c.Assert(validator.Validate(r), IsNil)
+
+	r = ride{
+		Seats:     &Int32Value{Value: 5},
+		Timeout:   &Duration{Nanos: 1000},
+		DropoffAt: &Timestamp{Seconds: 1},
+	}
+	c.Assert(validator.Validate(r), DeepEquals, validator.ErrorMap{
+		"Note":      {validator.ErrZeroValue},
+		"Seats":     {validator.ErrMax},
+		"PickupAt":  {validator.ErrZeroValue},
+		"Timeout":   {validator.ErrMin},
+		"DropoffAt": {validator.ErrAfter},
+	})
+
+	r = ride{Note: &StringValue{Value: "too long"}, PickupAt: &Timestamp{Seconds: time.Now().Add(time.Hour).Unix()}}
+	c.Assert(validator.Validate(r), DeepEquals, validator.ErrorMap{
+		"Note":     {validator.ErrMax},
+		"PickupAt": {validator.ErrBefore},
+	})
+}
+
+func (ms *MySuite) TestBeforeAfter(c *C) {
+	t := time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)
+	c.Assert(validator.Valid(t, "after=2024-01-01,before=2024-03-01T00:00:00Z"), IsNil)
+	c.Assert(validator.Valid(t, "before=2024-02-29"), DeepEquals, validator.ErrorArray{validator.ErrBefore})
+	c.Assert(validator.Valid(t, "after=now-1h"), DeepEquals, validator.ErrorArray{validator.ErrAfter})
+	c.Assert(validator.Valid(&t, "before=now+24h"), IsNil)
+	c.Assert(validator.Valid(time.Time{}, "after=now"), IsNil)
+	c.Assert(validator.Valid(t, "before=tomorrow"), DeepEquals, validator.ErrorArray{validator.ErrBadParameter})
+	c.Assert(validator.Valid("2024-01-01", "before=now"), DeepEquals, validator.ErrorArray{validator.ErrUnsupported})
+	c.Assert(validator.Valid(90*time.Minute, "min=1h,max=2h"), IsNil)
+	c.Assert(validator.Valid(3*time.Hour, "max=2h"), DeepEquals, validator.ErrorArray{validator.ErrMax})
+}
+
+func (ms *MySuite) TestBeforeAfterNil(c *C) {
+	c.Assert(validator.Valid(nil, "before=now"), IsNil)
+	c.Assert(validator.Valid(nil, "after=now"), IsNil)
+	type window struct {
+		From interface{} `validate:"after=2024-01-01"`
+		To   interface{} `validate:"before=now"`
+	}
+	c.Assert(validator.Validate(window{}), IsNil)
+	c.Assert(validator.Validate(window{From: time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)}), DeepEquals,
+		validator.ErrorMap{"From": {validator.ErrAfter}})
+}
@@ -0,0 +1,150 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrPwned is the error returned when a password has been
+	// found in a data breach
+	ErrPwned = TextErr{errors.New("password found in data breach")}
+	// ErrPwnedUnavailable is the error returned when a password
+	// could not be checked against data breaches
+	ErrPwnedUnavailable = TextErr{errors.New("breach check unavailable")}
+)
+
+// PwnedRangeFunc returns the hashes of the breached passwords whose SHA-1
+// hash starts with prefix, five upper-case hex characters. Hashes are
+// indexed by their remaining 35 characters, in upper case, and map to the
+// number of times they were seen in breaches. Only the prefix leaves the
+// process, which is the k-anonymity model of the Pwned Passwords API.
+type PwnedRangeFunc func(ctx context.Context, prefix string) (map[string]int, error)
+
+// PwnedConfig configures the validation function returned by NewPwnedFunc.
+type PwnedConfig struct {
+	// Range looks up hashes by prefix. It is typically the result of
+	// NewPwnedRange.
+	Range PwnedRangeFunc
+	// CacheTTL is how long the results of Range are kept. Caching is
+	// disabled if it is zero.
+	CacheTTL time.Duration
+	// CacheSize is the maximum number of prefixes kept in the cache,
+	// 1000 if zero.
+	CacheSize int
+	// FailOpen makes passwords valid when Range fails instead of
+	// returning ErrPwnedUnavailable.
+	FailOpen bool
+}
+
+// pwnedCacheEntry holds the result of a range lookup.
+type pwnedCacheEntry struct {
+	hashes  map[string]int
+	expires time.Time
+}
+
+// NewPwnedFunc returns a field validation function checking that a
+// password string does not appear in data breaches, or appears fewer
+// times than the optional parameter. It honors the context given to
+// ValidateContext and is meant to be registered under a name of your
+// choosing:
+//
+//	validator.SetFieldValidationFunc("pwned", validator.NewPwnedFunc(validator.PwnedConfig{
+//		Range:    validator.NewPwnedRange(nil, ""),
+//		CacheTTL: time.Hour,
+//		FailOpen: true,
+//	}))
+func NewPwnedFunc(cfg PwnedConfig) FieldValidationFunc {
+	size := cfg.CacheSize
+	if size <= 0 {
+		size = 1000
+	}
+	var (
+		mu    sync.Mutex
+		cache = map[string]pwnedCacheEntry{}
+	)
+	lookup := func(ctx context.Context, prefix string) (map[string]int, error) {
+		now := time.Now()
+		if cfg.CacheTTL > 0 {
+			mu.Lock()
+			e, ok := cache[prefix]
+			mu.Unlock()
+			if ok && now.Before(e.expires) {
+				return e.hashes, nil
+			}
+		}
+		hashes, err := cfg.Range(ctx, prefix)
+		if err != nil || cfg.CacheTTL <= 0 {
+			return hashes, err
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if len(cache) >= size {
+			// drop expired entries first, then arbitrary ones
+			for k, e := range cache {
+				if !now.Before(e.expires) {
+					delete(cache, k)
+				}
+			}
+			for k := range cache {
+				if len(cache) < size {
+					break
+				}
+				delete(cache, k)
+			}
+		}
+		cache[prefix] = pwnedCacheEntry{hashes, now.Add(cfg.CacheTTL)}
+		return hashes, nil
+	}
+
+	return func(v interface{}, f Field, param string) error {
+		threshold := int64(1)
+		if param != "" {
+			var err error
+			if threshold, err = asInt(param); err != nil || threshold < 1 {
+				return ErrBadParameter
+			}
+		}
+		if cfg.Range == nil {
+			return ErrBadParameter
+		}
+		s, ok, err := stringValue(v)
+		if !ok {
+			return err
+		}
+		sum := sha1.Sum([]byte(s))
+		hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+		hashes, err := lookup(f.Context(), hash[:5])
+		if err != nil {
+			if cfg.FailOpen {
+				return nil
+			}
+			return ErrPwnedUnavailable
+		}
+		if int64(hashes[hash[5:]]) >= threshold {
+			return ErrPwned
+		}
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !validator_minimal
+
+package validator
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// NewPwnedRange returns a PwnedRangeFunc querying a Pwned Passwords
+// compatible API at baseURL, https://api.pwnedpasswords.com if empty,
+// with the given client, http.DefaultClient if nil.
+func NewPwnedRange(client *http.Client, baseURL string) PwnedRangeFunc {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	if baseURL == "" {
+		baseURL = "https://api.pwnedpasswords.com"
+	}
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	return func(ctx context.Context, prefix string) (map[string]int, error) {
+		req, err := http.NewRequest(http.MethodGet, baseURL+"/range/"+prefix, nil)
+		if err != nil {
+			return nil, err
+		}
+		req = req.WithContext(ctx)
+		req.Header.Set("Add-Padding", "true")
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("pwned range %s: %s", prefix, resp.Status)
+		}
+		hashes := map[string]int{}
+		sc := bufio.NewScanner(resp.Body)
+		for sc.Scan() {
+			parts := strings.SplitN(strings.TrimSpace(sc.Text()), ":", 2)
+			if len(parts) != 2 {
+				continue
+			}
+			n, err := strconv.Atoi(parts[1])
+			if err != nil || n == 0 {
+				// padding entries have a count of zero
+				continue
+			}
+			hashes[strings.ToUpper(parts[0])] = n
+		}
+		return hashes, sc.Err()
+	}
+}
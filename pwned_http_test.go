@@ -0,0 +1,60 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !validator_minimal
+
+package validator_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestPwnedRange(c *C) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/range/"+pwnedPrefix {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "%s:42\r\n0018A45C4D1DEF81644B54AB7F969B88D65:0\r\n", pwnedSuffix)
+	}))
+	defer srv.Close()
+
+	lookup := validator.NewPwnedRange(srv.Client(), srv.URL+"/")
+	hashes, err := lookup(context.Background(), pwnedPrefix)
+	c.Assert(err, IsNil)
+	c.Assert(hashes, DeepEquals, map[string]int{pwnedSuffix: 42})
+
+	_, err = lookup(context.Background(), "00000")
+	c.Assert(err, NotNil)
+
+	v := validator.NewValidator()
+	v.SetFieldValidationFunc("pwned", validator.NewPwnedFunc(validator.PwnedConfig{Range: lookup}))
+	err = v.Valid("password", "pwned=42")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrPwned)
+	err = v.Valid("password", "pwned=43")
+	c.Assert(err, IsNil)
+
+	// a nil client is http.DefaultClient
+	hashes, err = validator.NewPwnedRange(nil, srv.URL)(context.Background(), pwnedPrefix)
+	c.Assert(err, IsNil)
+	c.Assert(hashes, DeepEquals, map[string]int{pwnedSuffix: 42})
+}
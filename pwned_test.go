@@ -0,0 +1,90 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+// SHA-1 of "password" is 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+const pwnedPrefix, pwnedSuffix = "5BAA6", "1E4C9B93F3F0682250B6CF8331B7EE68FD8"
+
+func (ms *MySuite) TestPwned(c *C) {
+	calls := 0
+	fail := false
+	rangeFn := func(ctx context.Context, prefix string) (map[string]int, error) {
+		calls++
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if fail {
+			return nil, errors.New("unavailable")
+		}
+		if prefix != pwnedPrefix {
+			return map[string]int{}, nil
+		}
+		return map[string]int{pwnedSuffix: 3}, nil
+	}
+
+	type signup struct {
+		Password string `validate:"pwned"`
+		Recovery string `validate:"pwned=5"`
+	}
+	v := validator.NewValidator()
+	v.SetFieldValidationFunc("pwned", validator.NewPwnedFunc(validator.PwnedConfig{
+		Range:    rangeFn,
+		CacheTTL: time.Minute,
+	}))
+
+	err := v.Validate(signup{Password: "correct horse battery staple", Recovery: "password"})
+	c.Assert(err, IsNil)
+	err = v.Validate(signup{Password: "password", Recovery: "password"})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Password"], HasError, validator.ErrPwned)
+	c.Assert(calls, Equals, 2, Commentf("range lookups should be cached"))
+
+	fail = true
+	err = v.Validate(signup{Password: "hunter2", Recovery: "password"})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Password"], HasError, validator.ErrPwnedUnavailable)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fail = false
+	v.SetFieldValidationFunc("pwned", validator.NewPwnedFunc(validator.PwnedConfig{
+		Range:    rangeFn,
+		FailOpen: true,
+	}))
+	err = v.ValidateContext(ctx, signup{Password: "password"})
+	c.Assert(err, IsNil, Commentf("cancelled lookups fail open"))
+	err = v.ValidContext(context.Background(), "password", "pwned")
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrPwned)
+
+	err = v.Valid("password", "pwned=0")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+	err = v.Valid(42, "pwned")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrUnsupported)
+}
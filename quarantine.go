@@ -0,0 +1,53 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+// Rejected is an item of a batch found to be invalid by Quarantine,
+// along with its index in the batch and the errors found in it.
+type Rejected[T any] struct {
+	Index int
+	Item  T
+	Err   error
+}
+
+// Quarantine validates the items of a batch, such as the rows of an
+// import, with mv, or the default validator if mv is nil, and splits
+// them into the valid items and the rejected ones rather than failing
+// the whole batch, so that the valid items can be processed and the
+// rejected ones reported. Both keep the order of items. Items with
+// warnings only, such as the errors of the rules marked with Soft, are
+// valid; the errors of rejected items include their warnings.
+//
+//	valid, rejected := validator.Quarantine(nil, rows)
+//	for _, r := range rejected {
+//		log.Printf("row %d: %v", r.Index+1, r.Err)
+//	}
+func Quarantine[T any](mv *Validator, items []T) ([]T, []Rejected[T]) {
+	if mv == nil {
+		mv = defaultValidator
+	}
+	var valid []T
+	var rejected []Rejected[T]
+	for i, item := range items {
+		if err := mv.Validate(item); err != nil && !newResult(err).Ok() {
+			rejected = append(rejected, Rejected[T]{Index: i, Item: item, Err: err})
+		} else {
+			valid = append(valid, item)
+		}
+	}
+	return valid, rejected
+}
@@ -0,0 +1,62 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestQuarantine(c *C) {
+	type row struct {
+		SKU string `validate:"nonzero"`
+		Qty int    `validate:"min=1"`
+	}
+	rows := []row{{"A1", 2}, {"", 1}, {"B2", 1}, {"C3", 0}}
+
+	valid, rejected := validator.Quarantine(nil, rows)
+	c.Assert(valid, DeepEquals, []row{{"A1", 2}, {"B2", 1}})
+	c.Assert(rejected, HasLen, 2)
+	c.Assert(rejected[0].Index, Equals, 1)
+	c.Assert(rejected[0].Item, Equals, rows[1])
+	c.Assert(rejected[0].Err.(validator.ErrorMap)["SKU"], HasError, validator.ErrZeroValue)
+	c.Assert(rejected[1].Index, Equals, 3)
+	c.Assert(rejected[1].Err.(validator.ErrorMap)["Qty"], HasError, validator.ErrMin)
+
+	v := validator.NewValidator()
+	v.SetTag("strict")
+	ptrs, rejectedPtrs := validator.Quarantine(v, []*row{{}, {SKU: "A1"}})
+	c.Assert(ptrs, HasLen, 2)
+	c.Assert(rejectedPtrs, HasLen, 0)
+}
+
+func (ms *MySuite) TestQuarantineWarnings(c *C) {
+	type row struct {
+		SKU  string `validate:"nonzero"`
+		Note string `validate:"max=5"`
+	}
+	rows := []row{{"A1", "too long"}, {"", "too long"}, {"B2", ""}}
+
+	v := validator.WithOptions(validator.Soft("max"))
+	valid, rejected := validator.Quarantine(v, rows)
+	c.Assert(valid, DeepEquals, []row{{"A1", "too long"}, {"B2", ""}})
+	c.Assert(rejected, HasLen, 1)
+	c.Assert(rejected[0].Index, Equals, 1)
+	errs := rejected[0].Err.(validator.ErrorMap)
+	c.Assert(errs["SKU"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["Note"], DeepEquals, validator.ErrorArray{validator.Warning{Err: validator.ErrMax}})
+}
@@ -0,0 +1,184 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Reload calls the Reload method on the default validator.
+func Reload(r io.Reader) error {
+	return defaultValidator.Reload(r)
+}
+
+// Reload reads rules from r, such as from a configuration file, and
+// swaps them for the rules read before, so that limits can be tuned
+// while a program runs. The rules are encoded as JSON objects holding
+// the rules of struct types, by package path and type name as in
+// "example.com/shop/api.Order", keyed by the paths of their fields as
+// given to SetFieldRules:
+//
+//	{
+//		"example.com/shop/api.Order": {
+//			"Note": "max=140",
+//			"Lines.SKU": "nonzero,max=12"
+//		}
+//	}
+//
+// Paths go through the anonymous struct types of a type, whose rules
+// apply only to the values held by a value of that type. The fields of
+// other named struct types, embedded ones included, are given rules
+// under the names of those types.
+//
+// Rules read with Reload win over those set with SetFieldRules and over
+// tags. As with SetFieldRules, rules set to "-" disable the validation
+// of the field, while empty rules leave it to the rules set with
+// SetFieldRules or to its tag.
+// Validations in flight when the rules are swapped go on with the rules
+// they started with. Validators derived with WithOptions share the
+// rules of mv, while those copied by WithTag or Merge keep the rules mv
+// had. Reload returns an error, and keeps the rules read before, if r
+// cannot be decoded or if the rules use unknown validation functions.
+// An empty object removes the rules read before.
+func (mv *Validator) Reload(r io.Reader) error {
+	var types map[string]map[string]string
+	if err := json.NewDecoder(r).Decode(&types); err != nil {
+		return err
+	}
+	for name, fields := range types {
+		for path, rules := range fields {
+			if rules == "" {
+				delete(fields, path)
+				continue
+			}
+			if rules == "-" {
+				continue
+			}
+			if _, err := mv.parseTags(rules); err != nil {
+				return fmt.Errorf("validator: rules of %s.%s: %w", name, path, err)
+			}
+		}
+	}
+	defer rulesChanged()
+	mv.loaded.Store(&ruleFile{
+		types:    types,
+		resolved: map[reflect.Type]bool{},
+		fields:   map[reflect.Type]map[reflect.Type]map[string]string{},
+	})
+	return nil
+}
+
+// ruleFile holds the rules read with Reload.
+type ruleFile struct {
+	// types holds the rules as read, by type name and path.
+	types map[string]map[string]string
+
+	mu sync.RWMutex
+	// resolved holds the named types whose paths have been resolved
+	// into fields, which holds the rules by named type, struct type
+	// holding the field and field name.
+	resolved map[reflect.Type]bool
+	fields   map[reflect.Type]map[reflect.Type]map[string]string
+}
+
+// ruleFile returns the rules read with Reload, or nil if there are none.
+func (mv *Validator) ruleFile() *ruleFile {
+	if mv.loaded == nil {
+		return nil
+	}
+	rf, _ := mv.loaded.Load().(*ruleFile)
+	return rf
+}
+
+// rules returns the rules of the field called name of the struct type t,
+// held by a value of the named type owner. The paths of a named type are
+// resolved the first time it is asked for.
+func (rf *ruleFile) rules(owner, t reflect.Type, name string) (string, bool) {
+	if rf == nil || len(rf.types) == 0 || owner == nil {
+		return "", false
+	}
+	rf.mu.RLock()
+	resolved := rf.resolved[owner]
+	rules, ok := rf.fields[owner][t][name]
+	rf.mu.RUnlock()
+	if resolved {
+		return rules, ok
+	}
+
+	rf.mu.Lock()
+	defer rf.mu.Unlock()
+	if !rf.resolved[owner] {
+		rf.resolved[owner] = true
+		holders := map[reflect.Type]map[string]string{}
+		for path, rules := range rf.types[owner.PkgPath()+"."+owner.Name()] {
+			// paths not found in owner are left out
+			holder, field, ok := ownedField(owner, path)
+			if !ok {
+				continue
+			}
+			if holders[holder] == nil {
+				holders[holder] = map[string]string{}
+			}
+			holders[holder][field] = rules
+		}
+		rf.fields[owner] = holders
+	}
+	rules, ok = rf.fields[owner][t][name]
+	return rules, ok
+}
+
+// ownedField returns the struct type holding the field found at path
+// from the named type owner, and the name of the field, if the path only
+// goes through owner and anonymous struct types.
+func ownedField(owner reflect.Type, path string) (reflect.Type, string, bool) {
+	parts := strings.Split(path, ".")
+	for i := range parts {
+		holder, field, err := fieldAt(owner, strings.Join(parts[:i+1], "."))
+		if err != nil || holder != owner && holder.Name() != "" {
+			return nil, "", false
+		}
+		if i == len(parts)-1 {
+			return holder, field, true
+		}
+	}
+	return nil, "", false
+}
+
+// ownerOf returns the type whose rules read with Reload apply to the
+// fields of the struct type t, held by a value of the named type owner:
+// t itself if it is named.
+func ownerOf(owner, t reflect.Type) reflect.Type {
+	if t.Name() != "" {
+		return t
+	}
+	return owner
+}
+
+// newLoaded returns a holder of the rules read with Reload holding rf.
+func newLoaded(rf *ruleFile) *atomic.Value {
+	loaded := new(atomic.Value)
+	if rf != nil {
+		loaded.Store(rf)
+	}
+	return loaded
+}
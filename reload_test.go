@@ -0,0 +1,128 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"strings"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type reloadOrder struct {
+	Note  string `validate:"max=10"`
+	Lines []struct {
+		SKU string
+	}
+}
+
+func (ms *MySuite) TestReload(c *C) {
+	v := validator.NewValidator()
+	derived := v.WithOptions()
+	order := reloadOrder{Note: "leave at door", Lines: []struct{ SKU string }{{""}}}
+	c.Assert(v.Validate(order).(validator.ErrorMap)["Note"], HasError, validator.ErrMax)
+
+	err := v.Reload(strings.NewReader(`{
+		"gopkg.in/validator.v2_test.reloadOrder": {
+			"Note": "max=20",
+			"Lines.SKU": "nonzero",
+			"NoSuchField": "nonzero"
+		}
+	}`))
+	c.Assert(err, IsNil)
+	copied := v.WithTag("validate")
+	for _, mv := range []*validator.Validator{v, derived} {
+		errs, ok := mv.Validate(order).(validator.ErrorMap)
+		c.Assert(ok, Equals, true)
+		c.Assert(errs["Note"], IsNil)
+		c.Assert(errs["Lines[0].SKU"], HasError, validator.ErrZeroValue)
+	}
+
+	ds, err := v.Describe(order)
+	c.Assert(err, IsNil)
+	c.Assert(ds[0].Rules, DeepEquals, []validator.Rule{{Name: "max", Param: "20", Source: validator.SourceLoaded}})
+
+	// bad rules keep the rules read before
+	err = v.Reload(strings.NewReader(`{"gopkg.in/validator.v2_test.reloadOrder": {"Note": "nosuchtag"}}`))
+	c.Assert(err, ErrorMatches, ".*reloadOrder.Note: unknown tag")
+	c.Assert(v.Validate(order).(validator.ErrorMap)["Note"], IsNil)
+	c.Assert(v.Reload(strings.NewReader(`[`)), NotNil)
+
+	c.Assert(v.Reload(strings.NewReader(`{}`)), IsNil)
+	c.Assert(v.Validate(order).(validator.ErrorMap)["Note"], HasError, validator.ErrMax)
+	c.Assert(derived.Validate(order).(validator.ErrorMap)["Note"], HasError, validator.ErrMax)
+	errs, ok := copied.Validate(order).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["Note"], IsNil)
+}
+
+func (ms *MySuite) TestReloadKeys(c *C) {
+	v := validator.NewValidator()
+	order := reloadOrder{Note: "leave at door"}
+
+	// types are keyed by package path, not by package name
+	err := v.Reload(strings.NewReader(`{"validator_test.reloadOrder": {"Note": "max=20"}}`))
+	c.Assert(err, IsNil)
+	c.Assert(v.Validate(order).(validator.ErrorMap)["Note"], HasError, validator.ErrMax)
+
+	// empty rules leave fields to their field rules and tags
+	c.Assert(v.SetFieldRules(reloadOrder{}, "Note", "max=20"), IsNil)
+	err = v.Reload(strings.NewReader(`{"gopkg.in/validator.v2_test.reloadOrder": {"Note": ""}}`))
+	c.Assert(err, IsNil)
+	c.Assert(v.Validate(order), IsNil)
+	c.Assert(v.SetFieldRules(reloadOrder{}, "Note", ""), IsNil)
+	c.Assert(v.Validate(order).(validator.ErrorMap)["Note"], HasError, validator.ErrMax)
+}
+
+type reloadAddress struct {
+	City string
+}
+
+type reloadCustomer struct {
+	Address reloadAddress
+	Phones  []struct {
+		Number string
+	}
+}
+
+func (ms *MySuite) TestReloadScope(c *C) {
+	v := validator.NewValidator()
+	err := v.Reload(strings.NewReader(`{
+		"gopkg.in/validator.v2_test.reloadCustomer": {
+			"Address.City": "nonzero",
+			"Phones.Number": "nonzero"
+		}
+	}`))
+	c.Assert(err, IsNil)
+	customer := reloadCustomer{Phones: []struct{ Number string }{{""}}}
+
+	// rules of the fields of named types are set under their names
+	c.Assert(v.Validate(reloadAddress{}), IsNil)
+	c.Assert(v.Validate(customer.Phones), IsNil)
+	errs, ok := v.Validate(customer).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Phones[0].Number"], HasError, validator.ErrZeroValue)
+	// anonymous structs only get the rules of the types holding them
+	c.Assert(v.Validate(reloadAddress{}), IsNil)
+	c.Assert(v.Validate(customer.Phones), IsNil)
+
+	err = v.Reload(strings.NewReader(`{"gopkg.in/validator.v2_test.reloadAddress": {"City": "nonzero"}}`))
+	c.Assert(err, IsNil)
+	c.Assert(v.Validate(reloadAddress{}).(validator.ErrorMap)["City"], HasError, validator.ErrZeroValue)
+	c.Assert(v.Validate(customer).(validator.ErrorMap)["Address.City"], HasError, validator.ErrZeroValue)
+}
@@ -0,0 +1,105 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"sort"
+)
+
+// ImportReport summarizes the errors found in a bulk import, such as
+// an uploaded CSV or spreadsheet file, for the user who sent it.
+type ImportReport struct {
+	// Rows is the number of rows read.
+	Rows int `json:"rows"`
+	// FailedRows is the number of rows with at least one error.
+	FailedRows int `json:"failed_rows"`
+	// FailedPercent is FailedRows as a percentage of Rows.
+	FailedPercent float64 `json:"failed_percent"`
+	// Issues lists the distinct errors, most frequent first.
+	Issues []ImportIssue `json:"issues"`
+}
+
+// ImportIssue is an error found in one column of one or more rows.
+type ImportIssue struct {
+	// Column is the column the error was found in, or the empty string
+	// for errors about whole rows.
+	Column string `json:"column"`
+	// Error is the error message.
+	Error string `json:"error"`
+	// Count is the number of rows with the error.
+	Count int `json:"count"`
+	// Examples holds the first rows with the error, in order.
+	Examples []int `json:"examples"`
+}
+
+// NewImportReport summarizes the errors of rows rows, as returned by
+// ValidateCSV, keeping up to examples row numbers for each issue.
+func NewImportReport(rows int, errs RowErrors, examples int) ImportReport {
+	numbers := make([]int, 0, len(errs))
+	for row := range errs {
+		numbers = append(numbers, row)
+	}
+	sort.Ints(numbers)
+
+	type issueKey struct{ column, err string }
+	issues := map[issueKey]*ImportIssue{}
+	report := ImportReport{Rows: rows, Issues: []ImportIssue{}}
+	for _, row := range numbers {
+		failed := false
+		for column, errs := range errs[row] {
+			seen := map[string]bool{}
+			for _, err := range errs {
+				k := issueKey{column, err.Error()}
+				if seen[k.err] {
+					continue
+				}
+				seen[k.err] = true
+				failed = true
+				issue, ok := issues[k]
+				if !ok {
+					issue = &ImportIssue{Column: k.column, Error: k.err, Examples: []int{}}
+					issues[k] = issue
+				}
+				issue.Count++
+				if len(issue.Examples) < examples {
+					issue.Examples = append(issue.Examples, row)
+				}
+			}
+		}
+		if failed {
+			report.FailedRows++
+		}
+	}
+	if rows > 0 {
+		report.FailedPercent = float64(report.FailedRows) * 100 / float64(rows)
+	}
+
+	for _, issue := range issues {
+		report.Issues = append(report.Issues, *issue)
+	}
+	sort.Slice(report.Issues, func(i, j int) bool {
+		a, b := report.Issues[i], report.Issues[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Column != b.Column {
+			return a.Column < b.Column
+		}
+		return a.Error < b.Error
+	})
+	return report
+}
@@ -0,0 +1,51 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestImportReport(c *C) {
+	data := "email,age,score,Admin\n" +
+		"jane@example.com,30,1,true\n" +
+		",12,2,false\n" +
+		",15,,false\n" +
+		"bob@example.com,16,3,false\n"
+	var users []importedUser
+	err := validator.ValidateCSV(csv.NewReader(strings.NewReader(data)), &users)
+	c.Assert(err, NotNil)
+
+	report := validator.NewImportReport(len(users), err.(validator.RowErrors), 2)
+	c.Assert(report.Rows, Equals, 4)
+	c.Assert(report.FailedRows, Equals, 3)
+	c.Assert(report.FailedPercent, Equals, 75.0)
+	c.Assert(report.Issues, DeepEquals, []validator.ImportIssue{
+		{Column: "age", Error: validator.ErrMin.Error(), Count: 3, Examples: []int{3, 4}},
+		{Column: "email", Error: validator.ErrZeroValue.Error(), Count: 2, Examples: []int{3, 4}},
+		{Column: "score", Error: validator.ErrZeroValue.Error(), Count: 1, Examples: []int{4}},
+	})
+
+	b, err := json.Marshal(validator.NewImportReport(0, nil, 5))
+	c.Assert(err, IsNil)
+	c.Assert(string(b), Equals, `{"rows":0,"failed_rows":0,"failed_percent":0,"issues":[]}`)
+}
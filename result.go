@@ -0,0 +1,207 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+)
+
+// Warning marks an error returned by a validation function as a warning.
+// Validate reports warnings along with errors, while Check tells them
+// apart.
+type Warning struct {
+	Err error
+}
+
+// Error implements the error interface.
+func (w Warning) Error() string {
+	return w.Err.Error()
+}
+
+// Unwrap returns the error of the warning.
+func (w Warning) Unwrap() error {
+	return w.Err
+}
+
+// FieldError is an error found in a field. Field is the key the error
+// has in an ErrorMap, or the empty string for the value itself. Label is
+// the label of the field, if it has one, which Error shows instead of
+// its key. See SetFieldLabel. Message is the message translated for
+// users, if any, which Error returns instead. See Translator.
+type FieldError struct {
+	Field   string
+	Err     error
+	Label   string
+	Message string
+}
+
+// Error implements the error interface.
+func (e FieldError) Error() string {
+	switch {
+	case e.Message != "":
+		return e.Message
+	case e.Label != "":
+		return e.Label + ": " + e.Err.Error()
+	case e.Field == "":
+		return e.Err.Error()
+	}
+	return e.Field + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error found in the field.
+func (e FieldError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalJSON encodes the field error as {"field": ..., "error": ...},
+// with "label" and "message" set to its label and translated message if
+// it has them.
+func (e FieldError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Field   string `json:"field"`
+		Label   string `json:"label,omitempty"`
+		Error   string `json:"error"`
+		Message string `json:"message,omitempty"`
+	}{e.Field, e.Label, e.Err.Error(), e.Message})
+}
+
+// UnmarshalJSON decodes a field error encoded by MarshalJSON, such as in
+// an AuditRecord. The decoded error holds the message of the error only.
+func (e *FieldError) UnmarshalJSON(data []byte) error {
+	var fe struct {
+		Field   string `json:"field"`
+		Label   string `json:"label"`
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &fe); err != nil {
+		return err
+	}
+	*e = FieldError{Field: fe.Field, Err: errors.New(fe.Error), Label: fe.Label, Message: fe.Message}
+	return nil
+}
+
+// Result is the outcome of Check.
+type Result struct {
+	errors   []FieldError
+	warnings []FieldError
+}
+
+// Ok returns whether no errors were found. Warnings do not count.
+func (r Result) Ok() bool {
+	return len(r.errors) == 0
+}
+
+// Errors returns the errors found, ordered by field.
+func (r Result) Errors() []FieldError {
+	return r.errors
+}
+
+// Warnings returns the warnings found, ordered by field, with the
+// errors they hold.
+func (r Result) Warnings() []FieldError {
+	return r.warnings
+}
+
+// Err returns the errors found as an ErrorMap, or nil if there are none.
+func (r Result) Err() error {
+	if r.Ok() {
+		return nil
+	}
+	m := ErrorMap{}
+	for _, e := range r.errors {
+		m[e.Field] = append(m[e.Field], e.Err)
+	}
+	return m
+}
+
+// MarshalJSON encodes the result as
+// {"valid": ..., "errors": [...], "warnings": [...]}.
+func (r Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Valid    bool         `json:"valid"`
+		Errors   []FieldError `json:"errors"`
+		Warnings []FieldError `json:"warnings"`
+	}{r.Ok(), append([]FieldError{}, r.errors...), append([]FieldError{}, r.warnings...)})
+}
+
+// Check validates v using the default validator. See Validator.Check.
+func Check(v interface{}) Result {
+	return defaultValidator.CheckContext(context.Background(), v)
+}
+
+// CheckContext validates v with ctx using the default validator.
+// See Validator.Check.
+func CheckContext(ctx context.Context, v interface{}) Result {
+	return defaultValidator.CheckContext(ctx, v)
+}
+
+// Check validates v like Validate, but returns a Result telling errors
+// and warnings apart rather than an error.
+func (mv *Validator) Check(v interface{}) Result {
+	return mv.CheckContext(context.Background(), v)
+}
+
+// CheckContext validates v with ctx like ValidateContext, but returns a
+// Result telling errors and warnings apart rather than an error. The
+// errors of fields with labels are labeled, and their messages
+// translated if mv has a Translator.
+func (mv *Validator) CheckContext(ctx context.Context, v interface{}) Result {
+	r := newResult(mv.ValidateContext(ctx, v))
+	t := reflect.TypeOf(v)
+	locale, _ := mv.context(ctx).Value(localeKey{}).(string)
+	for _, fes := range [][]FieldError{r.errors, r.warnings} {
+		for i := range fes {
+			fes[i].Label = mv.label(t, fes[i].Field)
+			if mv.translate != nil {
+				fes[i].Message = mv.translate(locale, fes[i])
+			}
+		}
+	}
+	return r
+}
+
+// newResult sorts the errors returned by Validate into a Result.
+func newResult(err error) Result {
+	var r Result
+	if err == nil {
+		return r
+	}
+	m, ok := err.(ErrorMap)
+	if !ok {
+		m = ErrorMap{"": ErrorArray{err}}
+	}
+	fields := make([]string, 0, len(m))
+	for field := range m {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		for _, e := range m[field] {
+			if w, ok := e.(Warning); ok {
+				r.warnings = append(r.warnings, FieldError{Field: field, Err: w.Err})
+			} else {
+				r.errors = append(r.errors, FieldError{Field: field, Err: e})
+			}
+		}
+	}
+	return r
+}
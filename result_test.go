@@ -0,0 +1,105 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestCheck(c *C) {
+	v := validator.NewValidator()
+	errWeak := errors.New("weak password")
+	v.SetValidationFunc("strong", func(i interface{}, param string) error {
+		if len(i.(string)) < 12 {
+			return validator.Warning{Err: errWeak}
+		}
+		return nil
+	})
+	type signup struct {
+		Email    string `validate:"nonzero"`
+		Password string `validate:"min=8,strong"`
+	}
+
+	r := v.Check(signup{Email: "jane@example.com", Password: "correct horse"})
+	c.Assert(r.Ok(), Equals, true)
+	c.Assert(r.Errors(), HasLen, 0)
+	c.Assert(r.Warnings(), HasLen, 0)
+	c.Assert(r.Err(), IsNil)
+
+	r = v.Check(signup{Email: "jane@example.com", Password: "hunter22"})
+	c.Assert(r.Ok(), Equals, true)
+	c.Assert(r.Warnings(), DeepEquals, []validator.FieldError{{Field: "Password", Err: errWeak}})
+	c.Assert(v.Validate(signup{Email: "jane@example.com", Password: "hunter22"}), NotNil)
+
+	r = v.Check(signup{Password: "hunter"})
+	c.Assert(r.Ok(), Equals, false)
+	c.Assert(r.Errors(), DeepEquals, []validator.FieldError{
+		{Field: "Email", Err: validator.ErrZeroValue},
+		{Field: "Password", Err: validator.ErrMin},
+	})
+	c.Assert(r.Errors()[1].Error(), Equals, "Password: less than min")
+	c.Assert(r.Warnings(), HasLen, 1)
+	c.Assert(r.Err().(validator.ErrorMap)["Password"], HasError, validator.ErrMin)
+
+	b, err := json.Marshal(r)
+	c.Assert(err, IsNil)
+	c.Assert(string(b), Equals, `{"valid":false,"errors":[{"field":"Email","error":"zero value"},`+
+		`{"field":"Password","error":"less than min"}],"warnings":[{"field":"Password","error":"weak password"}]}`)
+}
+
+// joinedErr mimics the errors returned by errors.Join.
+type joinedErr []error
+
+func (e joinedErr) Error() string   { return fmt.Sprint([]error(e)) }
+func (e joinedErr) Unwrap() []error { return e }
+
+func (ms *MySuite) TestErrorsUnwrap(c *C) {
+	errTooShort := errors.New("too short")
+	errNoDigit := errors.New("no digit")
+	v := validator.NewValidator()
+	v.SetValidationFunc("policy", func(i interface{}, param string) error {
+		return joinedErr{errTooShort, errNoDigit}
+	})
+	type T struct {
+		A string `validate:"nonzero"`
+		B string `validate:"policy"`
+	}
+	err := v.Validate(T{})
+	c.Assert(err, NotNil)
+	c.Assert(errors.Is(err, validator.ErrZeroValue), Equals, true)
+	c.Assert(errors.Is(err, errNoDigit), Equals, true)
+	c.Assert(errors.Is(err, validator.ErrMin), Equals, false)
+
+	var fe validator.FieldError
+	c.Assert(errors.As(err, &fe), Equals, true)
+	c.Assert(fe.Field, Equals, "A")
+	c.Assert(fe.Err, Equals, validator.ErrZeroValue)
+
+	errs := err.(validator.ErrorMap)
+	c.Assert(errs["B"], HasLen, 1)
+	c.Assert(errs["B"][0], DeepEquals, joinedErr{errTooShort, errNoDigit})
+	c.Assert(errors.Is(errs["B"], errTooShort), Equals, true)
+
+	err = v.Valid("", "nonzero,policy")
+	c.Assert(errors.Is(err, validator.ErrZeroValue), Equals, true)
+	c.Assert(errors.Is(err, errTooShort), Equals, true)
+}
@@ -0,0 +1,406 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RuleSet describes the rules of a struct type, and of the struct types
+// it holds, so that they can be enforced on decoded JSON, such as by a
+// gateway, without the struct definitions. It is exported with
+// ExportRules, encoded as JSON and loaded back with LoadRules.
+type RuleSet struct {
+	// Root is the name of the type the rule set was exported for.
+	Root string `json:"root"`
+	// Types holds the rules of the struct types, by name.
+	Types map[string]TypeRules `json:"types"`
+
+	mv *Validator
+}
+
+// TypeRules holds the rules of a struct type.
+type TypeRules struct {
+	// Rules holds the rules of the struct as a whole, set on blank
+	// (_) fields.
+	Rules string `json:"rules,omitempty"`
+	// Fields holds the rules of the fields, keyed by the names errors
+	// are reported under.
+	Fields map[string]FieldRules `json:"fields"`
+	// Names holds the keys of the fields named differently in Go, by
+	// Go name, for the parameters of rules referring to other fields.
+	Names map[string]string `json:"names,omitempty"`
+}
+
+// FieldRules holds the rules of a struct field.
+type FieldRules struct {
+	// Rules holds the tag of the field.
+	Rules string `json:"rules,omitempty"`
+	// Type is the name of the struct type of the values the field
+	// holds, if any, which are validated with the rules of the type.
+	Type string `json:"type,omitempty"`
+	// Wrap lists, outermost first, the slices ("[]") and maps ("map")
+	// the values of Type are held in, as in a field of type []T.
+	Wrap []string `json:"wrap,omitempty"`
+	// Required is set on the fields that must be present in the maps
+	// validated, as with the required keyword of JSON Schema. It does
+	// not constrain their values, which may be null, nor the fields of
+	// structs.
+	Required bool `json:"required,omitempty"`
+}
+
+// ErrMissing is the error returned by rule sets for the required fields
+// missing from a map.
+var ErrMissing = TextErr{errors.New("missing field")}
+
+// missing is validated in place of the fields missing from a value, as
+// a nil pointer field of a struct would be.
+var missing = (*interface{})(nil)
+
+// ExportRules returns the rules of the type of v using the default
+// validator. See Validator.ExportRules.
+func ExportRules(v interface{}) (*RuleSet, error) {
+	return defaultValidator.ExportRules(v)
+}
+
+// ExportRules returns the rules mv enforces on the struct type of v, a
+// struct or a pointer to one. Fields are keyed by the names errors are
+// reported under, so that a validator with SetNameTag("json") exports
+// rules matching the JSON encoding of v. The functions set with
+// SetStructValidationFunc and the types of interface fields cannot be
+// exported, and fields without a name, such as json "-" fields, are left
+// out.
+func (mv *Validator) ExportRules(v interface{}) (*RuleSet, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, ErrUnsupported
+	}
+	rs := &RuleSet{Types: map[string]TypeRules{}, mv: mv}
+	rs.Root = rs.export(t, nil, map[reflect.Type]string{}, t.String())
+	return rs, nil
+}
+
+// export adds the rules of the struct type t, held by a value of the
+// named type owner, and of the struct types it holds, to rs and returns
+// the name of t. Anonymous struct types are named after the field
+// holding them, as in "api.Order.Lines".
+func (rs *RuleSet) export(t, owner reflect.Type, names map[reflect.Type]string, field string) string {
+	if name, ok := names[t]; ok {
+		return name
+	}
+	base := t.String()
+	if t.Name() == "" {
+		base = field
+	}
+	name := base
+	for i := 2; ; i++ {
+		if _, taken := rs.Types[name]; !taken {
+			break
+		}
+		name = base + "#" + strconv.Itoa(i)
+	}
+	names[t] = name
+	// placeholder keeping types referring to t while t is exported
+	rs.Types[name] = TypeRules{Fields: map[string]FieldRules{"": {}}}
+
+	tr := TypeRules{Fields: map[string]FieldRules{}}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := rs.mv.fieldTag(ownerOf(owner, t), t, sf)
+		if sf.Name == "_" {
+			if tag != "" && tag != "-" {
+				if tr.Rules != "" {
+					tr.Rules += ","
+				}
+				tr.Rules += tag
+			}
+			continue
+		}
+		if tag == "-" || !sf.Anonymous && sf.PkgPath != "" {
+			continue
+		}
+		key := rs.mv.fieldName(sf)
+		if key == "" {
+			continue
+		}
+		if key != sf.Name {
+			if tr.Names == nil {
+				tr.Names = map[string]string{}
+			}
+			tr.Names[sf.Name] = key
+		}
+		fr := FieldRules{Rules: tag}
+		ft := sf.Type
+		for {
+			switch ft.Kind() {
+			case reflect.Ptr:
+				ft = ft.Elem()
+				continue
+			case reflect.Slice, reflect.Array:
+				if ft.Elem().Kind() != reflect.Uint8 {
+					fr.Wrap = append(fr.Wrap, "[]")
+					ft = ft.Elem()
+					continue
+				}
+			case reflect.Map:
+				fr.Wrap = append(fr.Wrap, "map")
+				ft = ft.Elem()
+				continue
+			case reflect.Struct:
+				// types without rules, such as time.Time, are left out
+				fr.Type = rs.export(ft, ownerOf(owner, t), names, name+"."+sf.Name)
+				if tr := rs.Types[fr.Type]; tr.Rules == "" && len(tr.Fields) == 0 {
+					delete(rs.Types, fr.Type)
+					fr.Type = ""
+				}
+			}
+			break
+		}
+		if fr.Type == "" {
+			fr.Wrap = nil
+		}
+		if fr.Rules != "" || fr.Type != "" {
+			tr.Fields[key] = fr
+		}
+	}
+	rs.Types[name] = tr
+	return name
+}
+
+// LoadRules decodes a rule set encoded as JSON for the default
+// validator. See Validator.LoadRules.
+func LoadRules(data []byte) (*RuleSet, error) {
+	return defaultValidator.LoadRules(data)
+}
+
+// LoadRules decodes a rule set exported by ExportRules and encoded as
+// JSON, to be enforced with the validation functions and aliases of mv.
+// It returns an error if a type is missing or if a tag is unknown.
+func (mv *Validator) LoadRules(data []byte) (*RuleSet, error) {
+	rs := &RuleSet{mv: mv}
+	if err := json.Unmarshal(data, rs); err != nil {
+		return nil, err
+	}
+	if err := rs.check(); err != nil {
+		return nil, err
+	}
+	return rs, nil
+}
+
+// check returns an error if the root type or a type of a field of rs is
+// missing, or if a tag or a wrap is unknown.
+func (rs *RuleSet) check() error {
+	mv := rs.mv
+	if mv == nil {
+		mv = defaultValidator
+	}
+	if _, ok := rs.Types[rs.Root]; !ok {
+		return fmt.Errorf("validator: unknown root type %q", rs.Root)
+	}
+	for name, tr := range rs.Types {
+		if _, err := mv.parseTags(tr.Rules); tr.Rules != "" && err != nil {
+			return fmt.Errorf("validator: rules of %s: %w", name, err)
+		}
+		for key, fr := range tr.Fields {
+			if _, err := mv.parseTags(fr.Rules); fr.Rules != "" && err != nil {
+				return fmt.Errorf("validator: rules of %s.%s: %w", name, key, err)
+			}
+			if _, ok := rs.Types[fr.Type]; fr.Type != "" && !ok {
+				return fmt.Errorf("validator: unknown type %q of %s.%s", fr.Type, name, key)
+			}
+			for _, w := range fr.Wrap {
+				if w != "[]" && w != "map" {
+					return fmt.Errorf("validator: unknown wrap %q of %s.%s", w, name, key)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Validate validates v, such as a map[string]interface{} decoded from
+// JSON, with the rules of the root type. See RuleSet.ValidateContext.
+func (rs *RuleSet) Validate(v interface{}) error {
+	return rs.ValidateContext(context.Background(), v)
+}
+
+// ValidateContext validates v with ctx like Validator.ValidateContext,
+// but with the rules of the root type of rs rather than those of the
+// type of v. Struct values are maps with string keys, such as those
+// decoded from JSON, or structs of any type whose fields are keyed as
+// in the rules, and slices and maps hold other values as in the
+// types the rules were exported from. Missing and null fields are
+// validated like nil pointers. Field validation functions find the other
+// fields of a map with Field.Sibling, by their Go names as for structs.
+func (rs *RuleSet) ValidateContext(ctx context.Context, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return ErrInvalid
+	}
+	mv := rs.mv
+	if mv == nil {
+		mv = defaultValidator
+	}
+	m := ErrorMap{}
+	rs.validateObject(ctx, mv, rs.Root, rv, "", m)
+	if len(m) > 0 {
+		return m
+	}
+	return nil
+}
+
+// validateObject validates the map or struct ov with the rules of the
+// type called name, adding its errors to m under prefix.
+func (rs *RuleSet) validateObject(ctx context.Context, mv *Validator, name string, ov reflect.Value, prefix string, m ErrorMap) {
+	for (ov.Kind() == reflect.Ptr || ov.Kind() == reflect.Interface) && !ov.IsNil() {
+		ov = ov.Elem()
+	}
+	if ov.Kind() != reflect.Struct && (ov.Kind() != reflect.Map || ov.Type().Key().Kind() != reflect.String) {
+		m.add(prefix, ErrUnsupported)
+		return
+	}
+	if ov.Kind() == reflect.Struct && !ov.CanInterface() {
+		m.add(prefix, ErrCannotValidate)
+		return
+	}
+	tr := rs.Types[name]
+	goNames := make(map[string]string, len(tr.Names))
+	for goName, key := range tr.Names {
+		goNames[key] = goName
+	}
+	if tr.Rules != "" {
+		err := mv.validateVar(ov.Interface(), Field{Parent: ov, ctx: ctx, mv: mv, keys: tr.Names}, tr.Rules)
+		addRuleErrors(m, prefix, "", tr.Names, err)
+	}
+
+	keys := make([]string, 0, len(tr.Fields))
+	for key := range tr.Fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fr := tr.Fields[key]
+		fv := mv.objectField(ov, key)
+		for fv.IsValid() && fv.Kind() == reflect.Interface && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		var val interface{} = missing
+		if fv.IsValid() && !(fv.Kind() == reflect.Interface && fv.IsNil()) {
+			val = fv.Interface()
+		}
+		path := joinKey(prefix, key)
+		if fr.Required && ov.Kind() == reflect.Map && !fv.IsValid() {
+			m.add(path, ErrMissing)
+		}
+		if fr.Rules != "" {
+			goName := key
+			if n, ok := goNames[key]; ok {
+				goName = n
+			}
+			err := mv.validateVar(val, Field{Name: goName, Parent: ov, ctx: ctx, mv: mv, keys: tr.Names}, fr.Rules)
+			addRuleErrors(m, prefix, path, tr.Names, err)
+		}
+		if fr.Type != "" && val != missing {
+			rs.validateWrapped(ctx, mv, fr.Type, fr.Wrap, fv, path, m)
+		}
+	}
+}
+
+// objectField returns the value of the field of ov keyed key: the value
+// of the map ov, or the exported field of the struct ov mv keys key. It
+// returns the zero Value if there is none.
+func (mv *Validator) objectField(ov reflect.Value, key string) reflect.Value {
+	if ov.Kind() == reflect.Map {
+		return ov.MapIndex(reflect.ValueOf(key).Convert(ov.Type().Key()))
+	}
+	for _, sf := range reflect.VisibleFields(ov.Type()) {
+		if sf.PkgPath != "" || mv.fieldName(sf) != key {
+			continue
+		}
+		// fields promoted from nil pointers are missing
+		fv, err := ov.FieldByIndexErr(sf.Index)
+		if err != nil {
+			return reflect.Value{}
+		}
+		return fv
+	}
+	return reflect.Value{}
+}
+
+// validateWrapped validates the values of the type called name held in
+// v, within the slices and maps listed in wrap.
+func (rs *RuleSet) validateWrapped(ctx context.Context, mv *Validator, name string, wrap []string, v reflect.Value, path string, m ErrorMap) {
+	for (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		return
+	}
+	if len(wrap) == 0 {
+		rs.validateObject(ctx, mv, name, v, path, m)
+		return
+	}
+	switch {
+	case wrap[0] == "[]" && (v.Kind() == reflect.Slice || v.Kind() == reflect.Array):
+		for i := 0; i < v.Len(); i++ {
+			rs.validateWrapped(ctx, mv, name, wrap[1:], v.Index(i), fmt.Sprintf("%s[%d]", path, i), m)
+		}
+	case wrap[0] == "map" && v.Kind() == reflect.Map:
+		for _, key := range v.MapKeys() {
+			rs.validateWrapped(ctx, mv, name, wrap[1:], v.MapIndex(key), path+"["+mv.formatKey(key)+"](value)", m)
+		}
+	default:
+		m.add(path, ErrUnsupported)
+	}
+}
+
+// addRuleErrors adds the errors of err to m under path, and those of the
+// ErrorMaps it holds, keyed relative to the object, under prefix. Keys
+// starting with the Go name of a field are renamed with names.
+func addRuleErrors(m ErrorMap, prefix, path string, names map[string]string, err error) {
+	errs, ok := err.(ErrorArray)
+	if !ok {
+		m.add(path, err)
+		return
+	}
+	for _, e := range errs {
+		if em, ok := e.(ErrorMap); ok {
+			for key, errarr := range em {
+				name := key
+				if i := strings.IndexAny(key, ".["); i >= 0 {
+					name = key[:i]
+				}
+				if k, ok := names[name]; ok && name != "" {
+					key = k + key[len(name):]
+				}
+				m.add(joinKey(prefix, key), errarr)
+			}
+			continue
+		}
+		m.add(path, e)
+	}
+}
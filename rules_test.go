@@ -0,0 +1,104 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type ruleLine struct {
+	SKU      string `json:"sku" validate:"nonzero,regexp=^[A-Z]+$"`
+	Quantity int    `json:"quantity" validate:"min=1"`
+}
+
+type ruleOrder struct {
+	ID       string              `json:"id" validate:"nonzero"`
+	Email    string              `json:"email" validate:"max=20"`
+	Password string              `json:"password" validate:"notsimilar=Email"`
+	Lines    []ruleLine          `json:"lines" validate:"min=1"`
+	ByWH     map[string]ruleLine `json:"by_warehouse"`
+	Parent   *ruleOrder          `json:"parent"`
+	Created  time.Time           `json:"created"`
+	Internal string              `json:"-" validate:"nonzero"`
+}
+
+func (ms *MySuite) TestRuleSet(c *C) {
+	v := validator.NewValidator()
+	v.SetNameTag("json")
+	rs, err := v.ExportRules(&ruleOrder{})
+	c.Assert(err, IsNil)
+	data, err := json.Marshal(rs)
+	c.Assert(err, IsNil)
+
+	loaded, err := validator.NewValidator().LoadRules(data)
+	c.Assert(err, IsNil)
+	c.Assert(loaded.Root, Equals, "validator_test.ruleOrder")
+	c.Assert(loaded.Types, HasLen, 2)
+	c.Assert(loaded.Types[loaded.Root].Fields["lines"], DeepEquals, validator.FieldRules{
+		Rules: "min=1", Type: "validator_test.ruleLine", Wrap: []string{"[]"},
+	})
+	c.Assert(loaded.Types[loaded.Root].Fields["parent"].Type, Equals, loaded.Root)
+	_, ok := loaded.Types[loaded.Root].Fields["created"]
+	c.Assert(ok, Equals, false)
+
+	var doc map[string]interface{}
+	err = json.Unmarshal([]byte(`{
+		"id": "o1",
+		"email": "jane@example.com",
+		"password": "jane@example.con",
+		"lines": [{"sku": "ABC", "quantity": 2}, {"sku": "abc", "quantity": 0}],
+		"by_warehouse": {"east": {"quantity": 1}},
+		"parent": {"email": "someone.with.a.long.name@example.com", "lines": []},
+		"created": "2024-01-01T00:00:00Z"
+	}`), &doc)
+	c.Assert(err, IsNil)
+	err = loaded.Validate(doc)
+	c.Assert(err, NotNil)
+	errs := err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 7, Commentf("%v", errs))
+	c.Assert(errs["password"], HasError, validator.ErrSimilar)
+	c.Assert(errs["lines[1].sku"], HasError, validator.ErrRegexp)
+	c.Assert(errs["lines[1].quantity"], HasError, validator.ErrMin)
+	c.Assert(errs["by_warehouse[east](value).sku"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["parent.id"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["parent.email"], HasError, validator.ErrMax)
+	c.Assert(errs["parent.lines"], HasError, validator.ErrMin)
+
+	// the loaded rules agree with the struct rules
+	var order ruleOrder
+	valid := `{"id": "o2", "email": "a@b.c", "password": "correct horse", "lines": [{"sku": "A", "quantity": 1}]}`
+	doc = nil
+	c.Assert(json.Unmarshal([]byte(valid), &doc), IsNil)
+	c.Assert(json.Unmarshal([]byte(valid), &order), IsNil)
+	order.Internal = "x"
+	c.Assert(v.Validate(order), IsNil)
+	c.Assert(loaded.Validate(doc), IsNil)
+
+	c.Assert(loaded.Validate(nil), Equals, validator.ErrInvalid)
+	c.Assert(loaded.Validate([]interface{}{}), DeepEquals, validator.ErrorMap{"": {validator.ErrUnsupported}})
+
+	_, err = validator.LoadRules([]byte(`{"root": "T", "types": {"T": {"fields": {"a": {"rules": "nosuchtag"}}}}}`))
+	c.Assert(err, ErrorMatches, ".*unknown tag")
+	_, err = validator.LoadRules([]byte(`{"root": "T", "types": {}}`))
+	c.Assert(err, NotNil)
+	_, err = validator.ExportRules(42)
+	c.Assert(err, Equals, validator.ErrUnsupported)
+}
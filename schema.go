@@ -0,0 +1,326 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NewRuleSet returns an empty rule set enforced with the default
+// validator. See Validator.NewRuleSet.
+func NewRuleSet(root string) *RuleSet {
+	return defaultValidator.NewRuleSet(root)
+}
+
+// NewRuleSet returns an empty rule set whose root type is called root,
+// to be enforced with the validation functions and aliases of mv, for
+// rules built with SetField rather than exported from a struct type.
+// Like those exported, it can be encoded as JSON and applied to decoded
+// maps and to the structs of any type whose fields are keyed alike, so
+// that one definition serves the internal and external forms of an
+// entity.
+//
+//	rs := validator.NewRuleSet("Order")
+//	err := rs.SetField("Order", "sku", validator.FieldRules{Rules: "nonzero,max=12"})
+func (mv *Validator) NewRuleSet(root string) *RuleSet {
+	return &RuleSet{Root: root, Types: map[string]TypeRules{root: {Fields: map[string]FieldRules{}}}, mv: mv}
+}
+
+// SetField sets the rules of the field keyed key of the type called typ,
+// adding the type to rs if it has none. Types given by the Type of the
+// rules may be added later. It returns an error if the rules use unknown
+// validation functions or if a wrap is unknown.
+func (rs *RuleSet) SetField(typ, key string, fr FieldRules) error {
+	mv := rs.mv
+	if mv == nil {
+		mv = defaultValidator
+	}
+	if _, err := mv.parseTags(fr.Rules); fr.Rules != "" && err != nil {
+		return fmt.Errorf("validator: rules of %s.%s: %w", typ, key, err)
+	}
+	for _, w := range fr.Wrap {
+		if w != "[]" && w != "map" {
+			return fmt.Errorf("validator: unknown wrap %q of %s.%s", w, typ, key)
+		}
+	}
+	if rs.Types == nil {
+		rs.Types = map[string]TypeRules{}
+	}
+	tr := rs.Types[typ]
+	if tr.Fields == nil {
+		tr.Fields = map[string]FieldRules{}
+	}
+	tr.Fields[key] = fr
+	rs.Types[typ] = tr
+	return nil
+}
+
+// LoadJSONSchema converts a JSON Schema for the default validator. See
+// Validator.LoadJSONSchema.
+func LoadJSONSchema(data []byte) (*RuleSet, error) {
+	return defaultValidator.LoadJSONSchema(data)
+}
+
+// LoadJSONSchema converts a JSON Schema describing an object into a rule
+// set enforced with mv, so that the canonical definition of an entity
+// published for clients also validates its Go forms. The root type is
+// named after the title of the schema, or "root", and the objects it
+// holds after the properties holding them, or the definitions they refer
+// to with $ref. The keywords converted are:
+//
+//	properties, $ref, $defs and definitions
+//	required                              Required
+//	minLength, minItems and minimum       min
+//	maxLength, maxItems and maximum       max
+//	pattern                               regexp
+//	items and additionalProperties        objects held in arrays and maps
+//
+// Required fields must be present in the maps validated, but may be null
+// as pointers of the structs validated may be nil. Types tell how to read
+// the other keywords but are not enforced, and constraints on the items
+// of arrays and maps other than objects cannot be converted. Annotations,
+// such as description, are ignored, and other keywords return an error.
+func (mv *Validator) LoadJSONSchema(data []byte) (*RuleSet, error) {
+	var s jsonSchema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	root := s.Title
+	if root == "" {
+		root = "root"
+	}
+	l := schemaLoader{rs: &RuleSet{Root: root, Types: map[string]TypeRules{}, mv: mv}, root: &s}
+	if s.kind() != "object" {
+		return nil, fmt.Errorf("validator: schema %s is not an object", root)
+	}
+	if err := l.object(root, &s); err != nil {
+		return nil, err
+	}
+	if err := l.rs.check(); err != nil {
+		return nil, err
+	}
+	return l.rs, nil
+}
+
+// jsonSchema is the part of a JSON Schema read by LoadJSONSchema.
+type jsonSchema struct {
+	Ref                  string                 `json:"$ref"`
+	Defs                 map[string]*jsonSchema `json:"$defs"`
+	Definitions          map[string]*jsonSchema `json:"definitions"`
+	Title                string                 `json:"title"`
+	Type                 json.RawMessage        `json:"type"`
+	Properties           map[string]*jsonSchema `json:"properties"`
+	Required             []string               `json:"required"`
+	Items                *jsonSchema            `json:"items"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	MinLength            json.Number            `json:"minLength"`
+	MaxLength            json.Number            `json:"maxLength"`
+	MinItems             json.Number            `json:"minItems"`
+	MaxItems             json.Number            `json:"maxItems"`
+	Minimum              json.Number            `json:"minimum"`
+	Maximum              json.Number            `json:"maximum"`
+	Pattern              string                 `json:"pattern"`
+}
+
+// jsonSchemaKeywords holds the keywords LoadJSONSchema reads or ignores.
+var jsonSchemaKeywords = map[string]bool{
+	"$ref": true, "$defs": true, "definitions": true, "type": true,
+	"properties": true, "required": true, "items": true, "additionalProperties": true,
+	"minLength": true, "maxLength": true, "minItems": true, "maxItems": true,
+	"minimum": true, "maximum": true, "pattern": true,
+	// annotations
+	"$schema": true, "$id": true, "$comment": true, "title": true, "description": true,
+	"default": true, "examples": true, "deprecated": true, "readOnly": true, "writeOnly": true,
+}
+
+// UnmarshalJSON decodes a schema, returning an error if it uses keywords
+// that cannot be converted.
+func (s *jsonSchema) UnmarshalJSON(data []byte) error {
+	var keywords map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keywords); err != nil {
+		return err
+	}
+	for k := range keywords {
+		if !jsonSchemaKeywords[k] {
+			return fmt.Errorf("validator: unsupported JSON Schema keyword %q", k)
+		}
+	}
+	type plain jsonSchema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// kind returns the type of the values of s other than null, told by the
+// keywords it uses if it has none.
+func (s *jsonSchema) kind() string {
+	var types []string
+	var t string
+	if json.Unmarshal(s.Type, &t) == nil {
+		types = []string{t}
+	} else {
+		json.Unmarshal(s.Type, &types)
+	}
+	for _, t := range types {
+		if t != "null" {
+			return t
+		}
+	}
+	switch {
+	case s.Properties != nil || s.AdditionalProperties != nil:
+		return "object"
+	case s.Items != nil:
+		return "array"
+	}
+	return ""
+}
+
+// rules returns the rules given by the keywords of s constraining the
+// values it describes themselves.
+func (s *jsonSchema) rules() []string {
+	var rules []string
+	add := func(name string, n json.Number) {
+		if n != "" {
+			rules = append(rules, name+"="+n.String())
+		}
+	}
+	add("min", s.MinLength)
+	add("max", s.MaxLength)
+	add("min", s.MinItems)
+	add("max", s.MaxItems)
+	add("min", s.Minimum)
+	add("max", s.Maximum)
+	if s.Pattern != "" {
+		rules = append(rules, "regexp="+strings.Replace(s.Pattern, ",", `\,`, -1))
+	}
+	return rules
+}
+
+// schemaLoader converts a JSON Schema into a rule set.
+type schemaLoader struct {
+	rs   *RuleSet
+	root *jsonSchema
+}
+
+// object adds the type called name, described by the object schema s,
+// and the types it holds to the rule set.
+func (l *schemaLoader) object(name string, s *jsonSchema) error {
+	if _, ok := l.rs.Types[name]; ok {
+		return nil
+	}
+	// placeholder keeping schemas referring to s while s is converted
+	l.rs.Types[name] = TypeRules{}
+
+	required := map[string]bool{}
+	for _, key := range s.Required {
+		required[key] = true
+	}
+	keys := make([]string, 0, len(s.Properties))
+	for key := range s.Properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	tr := TypeRules{Fields: map[string]FieldRules{}}
+	for _, key := range keys {
+		fr, err := l.field(name+"."+key, s.Properties[key])
+		if err != nil {
+			return err
+		}
+		fr.Required = required[key]
+		if fr.Rules != "" || fr.Type != "" || fr.Required {
+			tr.Fields[key] = fr
+		}
+	}
+	l.rs.Types[name] = tr
+	return nil
+}
+
+// field returns the rules of the field called name described by s.
+func (l *schemaLoader) field(name string, s *jsonSchema) (FieldRules, error) {
+	var fr FieldRules
+	seen := map[string]bool{}
+	for {
+		if s.Ref != "" {
+			def, ref, err := l.resolve(s.Ref)
+			if err != nil {
+				return fr, fmt.Errorf("validator: %s: %w", name, err)
+			}
+			if seen[ref] {
+				return fr, fmt.Errorf("validator: %s: %s refers to itself", name, s.Ref)
+			}
+			seen[ref] = true
+			if def.kind() == "object" && def.Properties != nil {
+				fr.Type = ref
+				return fr, l.object(ref, def)
+			}
+			s = def
+			continue
+		}
+		rules := s.rules()
+		if len(rules) > 0 {
+			if len(fr.Wrap) > 0 {
+				return fr, fmt.Errorf("validator: %s: constraints on items cannot be converted", name)
+			}
+			fr.Rules = strings.Join(rules, ",")
+		}
+		switch s.kind() {
+		case "object":
+			if s.Properties != nil {
+				fr.Type = name
+				return fr, l.object(name, s)
+			}
+			var items jsonSchema
+			if len(s.AdditionalProperties) == 0 || json.Unmarshal(s.AdditionalProperties, &items) != nil {
+				// additionalProperties set to true or false
+				if string(s.AdditionalProperties) == "false" {
+					return fr, fmt.Errorf("validator: %s: additionalProperties false cannot be converted", name)
+				}
+				return fr, nil
+			}
+			fr.Wrap = append(fr.Wrap, "map")
+			s = &items
+		case "array":
+			if s.Items == nil {
+				return fr, nil
+			}
+			fr.Wrap = append(fr.Wrap, "[]")
+			s = s.Items
+		default:
+			if len(fr.Wrap) > 0 {
+				// slices and maps of values other than objects
+				fr.Wrap = nil
+			}
+			return fr, nil
+		}
+	}
+}
+
+// resolve returns the definition ref refers to, and its name.
+func (l *schemaLoader) resolve(ref string) (*jsonSchema, string, error) {
+	for prefix, defs := range map[string]map[string]*jsonSchema{
+		"#/$defs/":       l.root.Defs,
+		"#/definitions/": l.root.Definitions,
+	} {
+		if strings.HasPrefix(ref, prefix) {
+			if def, ok := defs[ref[len(prefix):]]; ok {
+				return def, ref[len(prefix):], nil
+			}
+		}
+	}
+	return nil, "", fmt.Errorf("unknown reference %q", ref)
+}
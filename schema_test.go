@@ -0,0 +1,141 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"encoding/json"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+const orderSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"title": "Order",
+	"type": "object",
+	"required": ["id", "lines"],
+	"properties": {
+		"id": {"type": "string", "pattern": "^[A-Z]{2}[0-9]+$"},
+		"note": {"type": ["string", "null"], "maxLength": 10, "description": "for the courier"},
+		"lines": {"type": "array", "minItems": 1, "items": {"$ref": "#/$defs/Line"}},
+		"tags": {"type": "array", "items": {"type": "string"}}
+	},
+	"$defs": {
+		"Line": {
+			"type": "object",
+			"required": ["sku"],
+			"properties": {
+				"sku": {"type": "string", "maxLength": 12},
+				"qty": {"type": "integer", "minimum": 1}
+			}
+		}
+	}
+}`
+
+func (ms *MySuite) TestLoadJSONSchema(c *C) {
+	rs, err := validator.LoadJSONSchema([]byte(orderSchema))
+	c.Assert(err, IsNil)
+	c.Assert(rs.Root, Equals, "Order")
+	c.Assert(rs.Types["Order"].Fields, DeepEquals, map[string]validator.FieldRules{
+		"id":    {Rules: "regexp=^[A-Z]{2}[0-9]+$", Required: true},
+		"note":  {Rules: "max=10"},
+		"lines": {Rules: "min=1", Type: "Line", Wrap: []string{"[]"}, Required: true},
+	})
+
+	var doc map[string]interface{}
+	c.Assert(json.Unmarshal([]byte(`{"id": "FR12", "lines": [{"sku": "A1", "qty": 2}, {"qty": 0}]}`), &doc), IsNil)
+	errs, ok := rs.Validate(doc).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["lines[1].sku"], HasError, validator.ErrMissing)
+	c.Assert(errs["lines[1].qty"], HasError, validator.ErrMin)
+
+	// required fields may be null
+	doc = nil
+	c.Assert(json.Unmarshal([]byte(`{"id": null, "lines": [{"sku": null}]}`), &doc), IsNil)
+	c.Assert(rs.Validate(doc), IsNil)
+
+	// the same rules apply to the Go form of the order
+	type line struct {
+		SKU string `json:"sku"`
+		Qty int    `json:"qty"`
+	}
+	type order struct {
+		ID    string  `json:"id"`
+		Note  *string `json:"note"`
+		Lines []line  `json:"lines"`
+	}
+	rs, err = validator.WithNameTag("json").LoadJSONSchema([]byte(orderSchema))
+	c.Assert(err, IsNil)
+	c.Assert(rs.Validate(order{ID: "FR12", Lines: []line{{"A1", 2}}}), IsNil)
+	errs, ok = rs.Validate(&order{ID: "fr", Lines: []line{{"A1", 0}}}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs["id"], HasError, validator.ErrRegexp)
+	c.Assert(errs["lines[0].qty"], HasError, validator.ErrMin)
+
+	_, err = validator.LoadJSONSchema([]byte(`{"type": "object", "properties": {"a": {"oneOf": []}}}`))
+	c.Assert(err, ErrorMatches, `.*unsupported JSON Schema keyword "oneOf"`)
+	_, err = validator.LoadJSONSchema([]byte(`{"type": "object", "properties": {"a": {"type": "array", "items": {"maxLength": 3}}}}`))
+	c.Assert(err, ErrorMatches, `.*root.a: constraints on items cannot be converted`)
+	_, err = validator.LoadJSONSchema([]byte(`{"type": "string"}`))
+	c.Assert(err, NotNil)
+}
+
+func (ms *MySuite) TestRuleSetBuilder(c *C) {
+	rs := validator.NewRuleSet("Customer")
+	c.Assert(rs.SetField("Customer", "email", validator.FieldRules{Rules: "nonzero,regexp=@"}), IsNil)
+	c.Assert(rs.SetField("Customer", "addresses", validator.FieldRules{Type: "Address", Wrap: []string{"[]"}}), IsNil)
+	c.Assert(rs.SetField("Address", "zip", validator.FieldRules{Rules: "len=5"}), IsNil)
+	c.Assert(rs.SetField("Address", "zip", validator.FieldRules{Rules: "nosuchtag"}), NotNil)
+	c.Assert(rs.SetField("Address", "zip", validator.FieldRules{Wrap: []string{"set"}}), NotNil)
+
+	// an internal and an external form of the same entity
+	type address struct {
+		Zip string `json:"zip"`
+	}
+	type customerRow struct {
+		Email     string    `json:"email"`
+		Addresses []address `json:"addresses"`
+	}
+	type customerDTO struct {
+		Email     *string                  `json:"email"`
+		Addresses []map[string]interface{} `json:"addresses"`
+	}
+	v := validator.NewValidator()
+	v.SetNameTag("json")
+	rs = v.NewRuleSet("Customer")
+	c.Assert(rs.SetField("Customer", "email", validator.FieldRules{Rules: "nonzero,regexp=@"}), IsNil)
+	c.Assert(rs.SetField("Customer", "addresses", validator.FieldRules{Type: "Address", Wrap: []string{"[]"}}), IsNil)
+	c.Assert(rs.SetField("Address", "zip", validator.FieldRules{Rules: "len=5"}), IsNil)
+
+	errs, ok := rs.Validate(customerRow{Email: "a@b", Addresses: []address{{"123"}}}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["addresses[0].zip"], HasError, validator.ErrLen)
+
+	email := "jane"
+	errs, ok = rs.Validate(customerDTO{Email: &email, Addresses: []map[string]interface{}{{"zip": "12345"}}}).(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["email"], HasError, validator.ErrRegexp)
+
+	data, err := json.Marshal(rs)
+	c.Assert(err, IsNil)
+	loaded, err := v.LoadRules(data)
+	c.Assert(err, IsNil)
+	c.Assert(loaded.Types, DeepEquals, rs.Types)
+}
@@ -0,0 +1,134 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"context"
+)
+
+// Disagreement describes a value on which the active rules and the
+// candidate rules set with SetShadow disagree.
+type Disagreement struct {
+	// Value is the value validated.
+	Value interface{}
+	// Active is the error returned by the active rules, and Candidate
+	// the one the candidate rules would have returned.
+	Active    error
+	Candidate error
+	// Added holds the errors only the candidate rules report, that is
+	// the values they would reject, and Removed the errors only the
+	// active rules report.
+	Added   ErrorMap
+	Removed ErrorMap
+}
+
+// ShadowFunc is called with the context of a validation when the
+// candidate rules disagree with the active ones, such as to log the
+// disagreement or to count it.
+type ShadowFunc func(ctx context.Context, d Disagreement)
+
+// shadow holds the candidate rules run alongside the active ones.
+type shadow struct {
+	candidate *Validator
+	report    ShadowFunc
+}
+
+// SetShadow sets the candidate rules of the default validator.
+// See Validator.SetShadow.
+func SetShadow(candidate *Validator, report ShadowFunc) {
+	setDefault(func() error {
+		defaultValidator.SetShadow(candidate, report)
+		return nil
+	})
+}
+
+// SetShadow makes mv validate every value with candidate as well,
+// calling report when the errors found differ. Validate still returns
+// the errors found by mv only, so that stricter rules, such as those of
+// another tag given with WithTag, can be measured before they are
+// enforced. Shadows set on candidate are not run. A nil candidate
+// removes the shadow.
+//
+// The candidate validates values with its own configuration, such as
+// its name tag and locale, on the path of the caller, once Validate has
+// found the errors of mv and before it returns them, as the value may
+// change once it has returned. Every validation thus costs that of both
+// rules; to measure the candidate rules on a sample of values only, set
+// the shadow on a validator derived from mv with WithOptions and use it
+// for that sample.
+func (mv *Validator) SetShadow(candidate *Validator, report ShadowFunc) {
+	if candidate == nil || report == nil {
+		mv.shadow = nil
+		return
+	}
+	mv.shadow = &shadow{candidate: candidate, report: report}
+}
+
+// compare validates v with the candidate rules and reports whether
+// they disagree with err, the error returned by the active rules. Ctx
+// is the context given to ValidateContext, which the locale of the
+// candidate applies to.
+func (s *shadow) compare(ctx context.Context, v interface{}, err error) {
+	cerr := s.candidate.validate(s.candidate.context(ctx), v)
+	added := errorsNotIn(cerr, err)
+	removed := errorsNotIn(err, cerr)
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+	s.report(ctx, Disagreement{
+		Value:     v,
+		Active:    err,
+		Candidate: cerr,
+		Added:     added,
+		Removed:   removed,
+	})
+}
+
+// errorsNotIn returns the errors of err, by key and message, that are
+// not errors of other.
+func errorsNotIn(err, other error) ErrorMap {
+	m := ErrorMap{}
+	errs := errorsByKey(err)
+	others := errorsByKey(other)
+	for key, arr := range errs {
+		seen := map[string]int{}
+		for _, e := range others[key] {
+			seen[e.Error()]++
+		}
+		for _, e := range arr {
+			if seen[e.Error()] > 0 {
+				seen[e.Error()]--
+				continue
+			}
+			m[key] = append(m[key], e)
+		}
+	}
+	return m
+}
+
+// errorsByKey returns the errors of err as an ErrorMap, with errors
+// other than ErrorMaps keyed by the empty name.
+func errorsByKey(err error) ErrorMap {
+	switch err := err.(type) {
+	case nil:
+		return ErrorMap{}
+	case ErrorMap:
+		return err
+	default:
+		return ErrorMap{"": ErrorArray{err}}
+	}
+}
@@ -0,0 +1,93 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"context"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestShadow(c *C) {
+	type user struct {
+		Name string `validate:"nonzero" next:"nonzero,min=3"`
+		Age  int    `validate:"min=13" next:"min=0"`
+	}
+	var reports []validator.Disagreement
+	v := validator.NewValidator()
+	v.SetShadow(validator.NewValidator().WithTag("next"), func(ctx context.Context, d validator.Disagreement) {
+		reports = append(reports, d)
+	})
+
+	err := v.Validate(user{Name: "jane", Age: 20})
+	c.Assert(err, IsNil)
+	c.Assert(reports, HasLen, 0)
+
+	// the candidate rules do not change the error returned
+	err = v.Validate(user{Name: "jo", Age: 20})
+	c.Assert(err, IsNil)
+	c.Assert(reports, HasLen, 1)
+	c.Assert(reports[0].Active, IsNil)
+	c.Assert(reports[0].Added, DeepEquals, validator.ErrorMap{"Name": {validator.ErrMin}})
+	c.Assert(reports[0].Removed, HasLen, 0)
+	c.Assert(reports[0].Value, DeepEquals, user{Name: "jo", Age: 20})
+
+	// errors both rules report are not disagreements
+	reports = nil
+	err = v.Validate(user{Age: 10})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap), HasLen, 2)
+	c.Assert(reports, HasLen, 1)
+	c.Assert(reports[0].Added, DeepEquals, validator.ErrorMap{"Name": {validator.ErrMin}})
+	c.Assert(reports[0].Removed, DeepEquals, validator.ErrorMap{"Age": {validator.ErrMin}})
+	c.Assert(reports[0].Candidate, NotNil)
+
+	reports = nil
+	v.SetShadow(nil, nil)
+	err = v.Validate(user{Name: "jo", Age: 20})
+	c.Assert(err, IsNil)
+	c.Assert(reports, HasLen, 0)
+}
+
+func (ms *MySuite) TestShadowCandidateConfig(c *C) {
+	type price struct {
+		Amount string `json:"amount" validate:"floatstr"`
+	}
+	var reports []validator.Disagreement
+	v := validator.NewValidator().WithOptions(validator.Locale("en"))
+	candidate := validator.NewValidator().WithOptions(validator.NameTag("json"), validator.Locale("de"))
+	v.SetShadow(candidate, func(ctx context.Context, d validator.Disagreement) {
+		reports = append(reports, d)
+	})
+
+	// the candidate reads numbers in its own locale
+	err := v.Validate(price{Amount: "1.234,5"})
+	c.Assert(err, NotNil)
+	c.Assert(reports, HasLen, 1)
+	c.Assert(reports[0].Candidate, IsNil)
+	c.Assert(reports[0].Added, HasLen, 0)
+	c.Assert(reports[0].Removed, DeepEquals, validator.ErrorMap{"Amount": {validator.ErrNumber}})
+
+	// and keys its errors with its own name tag
+	reports = nil
+	err = v.Validate(price{Amount: "abc"})
+	c.Assert(err, ErrorMatches, "Amount: invalid number")
+	c.Assert(reports, HasLen, 1)
+	c.Assert(reports[0].Added, DeepEquals, validator.ErrorMap{"amount": {validator.ErrNumber}})
+	c.Assert(reports[0].Candidate, ErrorMatches, "amount: invalid number")
+}
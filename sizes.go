@@ -0,0 +1,219 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	// ErrMinBytes is the error returned when a value is smaller than
+	// the minimum size
+	ErrMinBytes = TextErr{errors.New("less than min bytes")}
+	// ErrMaxBytes is the error returned when a value is larger than
+	// the maximum size
+	ErrMaxBytes = TextErr{errors.New("greater than max bytes")}
+)
+
+// sizeUnits holds the number of bytes of the units sizes may be given in.
+var sizeUnits = map[string]float64{
+	"B":   1,
+	"KB":  1e3,
+	"kB":  1e3,
+	"MB":  1e6,
+	"GB":  1e9,
+	"TB":  1e12,
+	"KiB": 1 << 10,
+	"MiB": 1 << 20,
+	"GiB": 1 << 30,
+	"TiB": 1 << 40,
+}
+
+// asSize returns the number of bytes of a size such as "2MiB", "64KB"
+// or "1.5GB". It returns false for plain numbers.
+func asSize(param string) (int64, bool, error) {
+	param = strings.TrimSpace(param)
+	i := strings.LastIndexAny(param, "0123456789.") + 1
+	unit, ok := sizeUnits[strings.TrimSpace(param[i:])]
+	if !ok || i == 0 {
+		return 0, false, ErrBadParameter
+	}
+	n, err := strconv.ParseFloat(param[:i], 64)
+	if err != nil || n < 0 || n*unit > math.MaxInt64 {
+		return 0, false, ErrBadParameter
+	}
+	return int64(math.Round(n * unit)), true, nil
+}
+
+// lengthParam returns the length of the string, slice, map or array st
+// and the length param allows. Lengths are numbers of characters or
+// items, or numbers of bytes when param is a size such as "64KB", which
+// only applies to strings and []byte.
+func lengthParam(st reflect.Value, param string) (int64, int64, error) {
+	p, sized, err := asSize(param)
+	if !sized {
+		if p, err = asInt(param); err != nil {
+			return 0, 0, err
+		}
+		if st.Kind() == reflect.String {
+			return int64(utf8.RuneCountInString(st.String())), p, nil
+		}
+		return int64(st.Len()), p, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+	n, ok := byteLen(st)
+	if !ok {
+		return 0, 0, ErrBadParameter
+	}
+	return n, p, nil
+}
+
+// sizeFunc returns the function enforcing the builtin constraint name,
+// one of limitTags, with the size given as param, parsed once when the
+// tags are parsed rather than for every value. It returns nil if param
+// is not a size, such as a plain number or a limit.
+func sizeFunc(name, param string) ValidationFunc {
+	p, sized, err := asSize(param)
+	if !sized || err != nil {
+		return nil
+	}
+	if name == "minbytes" || name == "maxbytes" {
+		return func(v interface{}, _ string) error {
+			st := reflect.ValueOf(v)
+			if st.Kind() == reflect.Ptr && st.IsNil() {
+				return nil
+			}
+			n, ok := byteLen(st)
+			if !ok {
+				return ErrUnsupported
+			}
+			return compareSize(name, n, p)
+		}
+	}
+	return func(v interface{}, _ string) error {
+		st := reflect.ValueOf(v)
+		if st.Kind() == reflect.Ptr {
+			if st.IsNil() {
+				return nil
+			}
+			st = st.Elem()
+		}
+		switch st.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map, reflect.Array:
+			n, ok := byteLen(st)
+			if !ok {
+				return ErrBadParameter
+			}
+			return compareSize(name, n, p)
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+			reflect.Float32, reflect.Float64:
+			// numbers have no size
+			return ErrBadParameter
+		default:
+			return ErrUnsupported
+		}
+	}
+}
+
+// compareSize returns the error of the builtin constraint name, one of
+// limitTags, if n bytes break the size p.
+func compareSize(name string, n, p int64) error {
+	switch {
+	case name == "len" && n != p:
+		return ErrLen
+	case name == "min" && n < p:
+		return ErrMin
+	case name == "max" && n > p:
+		return ErrMax
+	case name == "minbytes" && n < p:
+		return ErrMinBytes
+	case name == "maxbytes" && n > p:
+		return ErrMaxBytes
+	}
+	return nil
+}
+
+// byteLen returns the number of bytes of a string or []byte value,
+// following pointers.
+func byteLen(st reflect.Value) (int64, bool) {
+	for st.Kind() == reflect.Ptr && !st.IsNil() {
+		st = st.Elem()
+	}
+	switch {
+	case st.Kind() == reflect.String:
+		return int64(st.Len()), true
+	case (st.Kind() == reflect.Slice || st.Kind() == reflect.Array) && st.Type().Elem().Kind() == reflect.Uint8:
+		return int64(st.Len()), true
+	}
+	return 0, false
+}
+
+// bytesParam returns the number of bytes of the string or []byte v and
+// the size, with or without unit, given as parameter.
+func bytesParam(v interface{}, param string) (int64, int64, bool, error) {
+	st := reflect.ValueOf(v)
+	if st.Kind() == reflect.Ptr && st.IsNil() {
+		return 0, 0, false, nil
+	}
+	p, sized, err := asSize(param)
+	if !sized {
+		if p, err = asInt(param); err != nil || p < 0 {
+			return 0, 0, false, ErrBadParameter
+		}
+	}
+	n, ok := byteLen(st)
+	if !ok {
+		return 0, 0, false, ErrUnsupported
+	}
+	return n, p, true, nil
+}
+
+// minbytes is the builtin validation function that checks whether a
+// string or []byte has at least the number of bytes given as parameter,
+// a plain number or a size such as "1KiB".
+func minbytes(v interface{}, param string) error {
+	n, p, ok, err := bytesParam(v, param)
+	if !ok {
+		return err
+	}
+	if n < p {
+		return ErrMinBytes
+	}
+	return nil
+}
+
+// maxbytes is the builtin validation function that checks whether a
+// string or []byte has at most the number of bytes given as parameter,
+// a plain number or a size such as "2MiB".
+func maxbytes(v interface{}, param string) error {
+	n, p, ok, err := bytesParam(v, param)
+	if !ok {
+		return err
+	}
+	if n > p {
+		return ErrMaxBytes
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"strings"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+func (ms *MySuite) TestSizeUnits(c *C) {
+	type upload struct {
+		Name    string `validate:"max=1KB"`
+		Body    []byte `validate:"min=1B,max=2KiB"`
+		Exact   string `validate:"len=4B"`
+		Comment *string
+	}
+	err := validator.Validate(upload{Name: "é", Body: []byte("x"), Exact: "éé"})
+	c.Assert(err, IsNil)
+
+	err = validator.Validate(upload{Name: strings.Repeat("é", 501), Body: make([]byte, 2049), Exact: "éé"})
+	c.Assert(err, NotNil)
+	errs := err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Name"], HasError, validator.ErrMax)
+	c.Assert(errs["Body"], HasError, validator.ErrMax)
+
+	// without units strings are measured in characters
+	c.Assert(validator.Valid("éé", "len=2"), IsNil)
+	c.Assert(validator.Valid("éé", "len=2B"), HasError, validator.ErrLen)
+	c.Assert(validator.Valid(make([]byte, 1500000), "max=1.5MB"), IsNil)
+	c.Assert(validator.Valid(make([]byte, 1500001), "max=1.5MB"), HasError, validator.ErrMax)
+	c.Assert(validator.Valid([]int{1}, "max=1KB"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid("x", "max=1XB"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid(42, "max=1KB"), HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestBytes(c *C) {
+	c.Assert(validator.Valid("é", "maxbytes=2,minbytes=2"), IsNil)
+	c.Assert(validator.Valid("éé", "maxbytes=2"), HasError, validator.ErrMaxBytes)
+	c.Assert(validator.Valid(make([]byte, 2<<20), "maxbytes=2MiB"), IsNil)
+	c.Assert(validator.Valid(make([]byte, 2<<20+1), "maxbytes=2MiB"), HasError, validator.ErrMaxBytes)
+	c.Assert(validator.Valid([]byte("abc"), "minbytes=1kB"), HasError, validator.ErrMinBytes)
+
+	var s *string
+	c.Assert(validator.Valid(s, "maxbytes=1"), IsNil)
+	c.Assert(validator.Valid(42, "maxbytes=1"), HasError, validator.ErrUnsupported)
+	c.Assert(validator.Valid("x", "maxbytes=-1"), HasError, validator.ErrBadParameter)
+	c.Assert(validator.Valid("x", "maxbytes=lots"), HasError, validator.ErrBadParameter)
+}
+
+func (ms *MySuite) TestSizesCompiled(c *C) {
+	v := validator.NewValidator()
+	c.Assert(v.Valid("abc", "max=2B"), HasError, validator.ErrMax)
+	c.Assert(v.Valid(42, "maxbytes=1KB"), HasError, validator.ErrUnsupported)
+
+	// functions set in place of the builtin ones get sizes as written
+	var params []string
+	v.SetValidationFunc("max", func(_ interface{}, param string) error {
+		params = append(params, param)
+		return nil
+	})
+	c.Assert(v.Valid("abc", "max=2B"), IsNil)
+	c.Assert(params, DeepEquals, []string{"2B"})
+
+	// tags are parsed again once aliases change
+	v.SetAlias("small", "maxbytes=1KiB")
+	c.Assert(v.Valid(make([]byte, 2000), "small"), HasError, validator.ErrMaxBytes)
+	v.SetAlias("small", "maxbytes=2KiB")
+	c.Assert(v.Valid(make([]byte, 2000), "small"), IsNil)
+}
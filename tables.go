@@ -0,0 +1,36 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !validator_minimal
+
+package validator
+
+import (
+	countrydata "gopkg.in/validator.v2/countries"
+	"gopkg.in/validator.v2/locales"
+)
+
+// The tables of countries and locales are registered unless the
+// validator_minimal build tag is set, which keeps them out of small
+// builds such as TinyGo or WASM ones.
+func init() {
+	for code, b := range countrydata.Bounds {
+		countries[code] = BoundingBox(b)
+	}
+	for locale, nf := range locales.NumberFormats {
+		numberFormats[locale] = NumberFormat(nf)
+	}
+}
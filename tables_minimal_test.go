@@ -0,0 +1,35 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build validator_minimal
+
+package validator_test
+
+import (
+	"gopkg.in/validator.v2"
+	"gopkg.in/validator.v2/countries"
+	"gopkg.in/validator.v2/locales"
+)
+
+// Minimal builds register the tables they need themselves.
+func init() {
+	for code, b := range countries.Bounds {
+		validator.RegisterCountry(code, validator.BoundingBox(b))
+	}
+	for locale, nf := range locales.NumberFormats {
+		validator.RegisterNumberFormat(locale, validator.NumberFormat(nf))
+	}
+}
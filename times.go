@@ -0,0 +1,115 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrBefore is the error returned when a time is not before the
+	// limit given.
+	ErrBefore = TextErr{errors.New("not before limit")}
+	// ErrAfter is the error returned when a time is not after the
+	// limit given.
+	ErrAfter = TextErr{errors.New("not after limit")}
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// before is the builtin validation function that checks whether a
+// time.Time is before the time given.
+func before(v interface{}, param string) error {
+	t, limit, ok, err := timeParams(v, param)
+	if !ok || err != nil {
+		return err
+	}
+	if !t.Before(limit) {
+		return ErrBefore
+	}
+	return nil
+}
+
+// after is the builtin validation function that checks whether a
+// time.Time is after the time given.
+func after(v interface{}, param string) error {
+	t, limit, ok, err := timeParams(v, param)
+	if !ok || err != nil {
+		return err
+	}
+	if !t.After(limit) {
+		return ErrAfter
+	}
+	return nil
+}
+
+// timeParams returns the time.Time v and the limit given by param: a
+// time in RFC 3339 format, a date such as 2006-01-02, or now followed by
+// an optional duration such as now+24h. It returns false for nil values,
+// nil pointers and zero times, which are left to nonzero.
+func timeParams(v interface{}, param string) (time.Time, time.Time, bool, error) {
+	st := reflect.ValueOf(v)
+	if !st.IsValid() {
+		// nil values are left to nonnil
+		return time.Time{}, time.Time{}, false, nil
+	}
+	if st.Kind() == reflect.Ptr {
+		if st.IsNil() {
+			return time.Time{}, time.Time{}, false, nil
+		}
+		st = st.Elem()
+	}
+	if st.Type() != timeType {
+		return time.Time{}, time.Time{}, false, ErrUnsupported
+	}
+	t := st.Interface().(time.Time)
+	if t.IsZero() {
+		return t, time.Time{}, false, nil
+	}
+	limit, err := timeParam(param)
+	if err != nil {
+		return t, limit, false, err
+	}
+	return t, limit, true, nil
+}
+
+// timeParam parses the limit of before and after.
+func timeParam(param string) (time.Time, error) {
+	if strings.HasPrefix(param, "now") {
+		now := time.Now()
+		if param == "now" {
+			return now, nil
+		}
+		d, err := time.ParseDuration(strings.TrimPrefix(param[3:], "+"))
+		if err != nil {
+			return time.Time{}, ErrBadParameter
+		}
+		return now.Add(d), nil
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		if t, err := time.Parse(layout, param); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrBadParameter
+}
@@ -0,0 +1,82 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"reflect"
+)
+
+// Element is an element of a container, such as an entry of an ordered
+// map or the value held by an option type.
+type Element struct {
+	// Key is the key errors of the element are reported under, relative
+	// to the container, such as "[2]" for the third item of a list or
+	// "[eu]" for an entry of a map. Errors of elements with an empty key
+	// are reported under the key of the container itself.
+	Key string
+	// Value is the element.
+	Value reflect.Value
+}
+
+// TraverseFunc returns the elements of a container, which are then
+// validated like the items of slices and maps.
+type TraverseFunc func(v reflect.Value) []Element
+
+// SetTraverseFunc sets the function returning the elements of containers
+// of the same type as typ, such as ordered maps, sets or generic option
+// types, whose elements are then validated and keyed correctly instead
+// of the container being validated as an opaque struct. typ may be a
+// pointer to the type. Calling this function with nil fn removes the
+// function for the type.
+func SetTraverseFunc(typ interface{}, fn TraverseFunc) error {
+	return setDefault(func() error {
+		return defaultValidator.SetTraverseFunc(typ, fn)
+	})
+}
+
+// SetTraverseFunc sets the function returning the elements of containers
+// of the same type as typ, such as ordered maps, sets or generic option
+// types, whose elements are then validated and keyed correctly instead
+// of the container being validated as an opaque struct. typ may be a
+// pointer to the type. Calling this function with nil fn removes the
+// function for the type.
+func (mv *Validator) SetTraverseFunc(typ interface{}, fn TraverseFunc) error {
+	t := reflect.TypeOf(typ)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return errors.New("type cannot be nil")
+	}
+	mv.own()
+	if fn == nil {
+		delete(mv.traverseFuncs, t)
+		return nil
+	}
+	mv.traverseFuncs[t] = fn
+	return nil
+}
+
+// traverseFunc returns the traverse function set for the type of v.
+func (mv *Validator) traverseFunc(v reflect.Value) (TraverseFunc, bool) {
+	if len(mv.traverseFuncs) == 0 || !v.IsValid() {
+		return nil, false
+	}
+	fn, ok := mv.traverseFuncs[v.Type()]
+	return fn, ok
+}
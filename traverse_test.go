@@ -0,0 +1,89 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"reflect"
+	"strconv"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+// orderedMap is a map keeping the order its keys were set in.
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (om *orderedMap) Set(key string, value interface{}) {
+	if om.values == nil {
+		om.values = map[string]interface{}{}
+	}
+	om.keys = append(om.keys, key)
+	om.values[key] = value
+}
+
+// option is a generic option type.
+type option[T any] struct {
+	value T
+	ok    bool
+}
+
+type traverseItem struct {
+	Name string `validate:"nonzero"`
+}
+
+func (ms *MySuite) TestSetTraverseFunc(c *C) {
+	type order struct {
+		Items   orderedMap
+		Gift    option[traverseItem]
+		Missing option[traverseItem]
+	}
+	o := order{Gift: option[traverseItem]{ok: true}}
+	o.Items.Set("a", traverseItem{Name: "apple"})
+	o.Items.Set("b", &traverseItem{})
+
+	mv := validator.NewValidator()
+	c.Assert(mv.Validate(o), IsNil)
+
+	c.Assert(mv.SetTraverseFunc(&orderedMap{}, func(v reflect.Value) []validator.Element {
+		om := v.Addr().Interface().(*orderedMap)
+		var es []validator.Element
+		for _, key := range om.keys {
+			es = append(es, validator.Element{Key: "[" + strconv.Quote(key) + "]", Value: reflect.ValueOf(om.values[key])})
+		}
+		return es
+	}), IsNil)
+	c.Assert(mv.SetTraverseFunc(option[traverseItem]{}, func(v reflect.Value) []validator.Element {
+		opt := v.Interface().(option[traverseItem])
+		if !opt.ok {
+			return nil
+		}
+		return []validator.Element{{Value: reflect.ValueOf(opt.value)}}
+	}), IsNil)
+	c.Assert(mv.Validate(&o), DeepEquals, validator.ErrorMap{
+		`Items["b"].Name`: {validator.ErrZeroValue},
+		"Gift.Name":       {validator.ErrZeroValue},
+	})
+
+	c.Assert(mv.SetTraverseFunc(option[traverseItem]{}, nil), IsNil)
+	c.Assert(mv.Validate(&o), DeepEquals, validator.ErrorMap{
+		`Items["b"].Name`: {validator.ErrZeroValue},
+	})
+	c.Assert(mv.SetTraverseFunc(nil, nil), NotNil)
+}
@@ -0,0 +1,175 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sync"
+)
+
+var (
+	// ErrImplements is the error returned when a value does not
+	// implement the required interface
+	ErrImplements = TextErr{errors.New("does not implement interface")}
+	// ErrType is the error returned when the type of a value is not
+	// one of the allowed types
+	ErrType = TextErr{errors.New("type not allowed")}
+	// ErrPayload is the error returned when a payload cannot be
+	// decoded into the type selected by its discriminator
+	ErrPayload = TextErr{errors.New("invalid payload")}
+)
+
+var (
+	typesMu sync.RWMutex
+	types   = map[string]reflect.Type{}
+)
+
+// RegisterType registers the type T under name so that it can be used
+// in tags, such as implements=name for interface types and
+// typeoneof=name for the variants of a union.
+func RegisterType[T any](name string) error {
+	if name == "" {
+		return errors.New("name cannot be empty")
+	}
+	typesMu.Lock()
+	types[name] = reflect.TypeOf((*T)(nil)).Elem()
+	typesMu.Unlock()
+	return nil
+}
+
+// registeredType returns the type registered under name.
+func registeredType(name string) (reflect.Type, bool) {
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+	t, ok := types[name]
+	return t, ok
+}
+
+// implements is the builtin validation function that checks whether the
+// dynamic value of a field, or a pointer to it, implements the interface
+// registered under the name given as parameter. Nil values are valid.
+func implements(v interface{}, param string) error {
+	it, ok := registeredType(param)
+	if !ok || it.Kind() != reflect.Interface {
+		return ErrBadParameter
+	}
+	if v == nil {
+		return nil
+	}
+	t := reflect.TypeOf(v)
+	if t.Implements(it) || t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(it) {
+		return nil
+	}
+	return ErrImplements
+}
+
+// typeoneof is the builtin validation function that checks whether the
+// dynamic value of a field, or the value it points to, has one of the
+// types registered under the names given as parameter, or implements
+// one of them for interface types. Nil values are valid.
+func typeoneof(v interface{}, param string) error {
+	names := splitParams(param)
+	if len(names) == 0 {
+		return ErrBadParameter
+	}
+	allowed := make([]reflect.Type, len(names))
+	for i, name := range names {
+		t, ok := registeredType(name)
+		if !ok {
+			return ErrBadParameter
+		}
+		allowed[i] = t
+	}
+	if v == nil {
+		return nil
+	}
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for _, a := range allowed {
+		switch {
+		case a.Kind() == reflect.Interface:
+			if t.Implements(a) || reflect.PtrTo(t).Implements(a) {
+				return nil
+			}
+		case a == t || a.Kind() == reflect.Ptr && a.Elem() == t:
+			return nil
+		}
+	}
+	return ErrType
+}
+
+// discriminates is the builtin validation function for string fields
+// naming the type of the payload held in the field given as parameter,
+// such as the type of an event and its data. The payload, JSON held in a
+// json.RawMessage, []byte or string field or a map such as one decoded
+// from JSON, is decoded into the type registered under the value of the
+// field and validated, with errors keyed by the payload field.
+func discriminates(v interface{}, f Field, param string) error {
+	if param == "" {
+		return ErrBadParameter
+	}
+	payload, ok := f.Sibling(param)
+	if !ok {
+		return ErrBadParameter
+	}
+	s, ok, err := stringValue(v)
+	if !ok {
+		return err
+	}
+	t, ok := registeredType(s)
+	if !ok || t.Kind() == reflect.Interface {
+		return ErrType
+	}
+
+	var data []byte
+	switch {
+	case payload.Kind() == reflect.Ptr || payload.Kind() == reflect.Interface:
+		// nil payload
+		return nil
+	case payload.Kind() == reflect.String:
+		data = []byte(payload.String())
+	case payload.Kind() == reflect.Slice && payload.Type().Elem().Kind() == reflect.Uint8:
+		data = payload.Bytes()
+	case payload.Kind() == reflect.Map:
+		if data, err = json.Marshal(payload.Interface()); err != nil {
+			return ErrorMap{param: ErrorArray{ErrPayload}}
+		}
+	default:
+		return ErrBadParameter
+	}
+	if len(data) == 0 {
+		return nil
+	}
+	pv := reflect.New(t)
+	if err := json.Unmarshal(data, pv.Interface()); err != nil {
+		return ErrorMap{param: ErrorArray{ErrPayload}}
+	}
+	err = f.Validate(pv.Interface())
+	errs, ok := err.(ErrorMap)
+	if !ok {
+		return err
+	}
+	m := ErrorMap{}
+	for key, errarr := range errs {
+		m[joinKey(param, key)] = errarr
+	}
+	return m
+}
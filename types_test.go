@@ -0,0 +1,180 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"encoding/json"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type pluginConfig interface {
+	PluginName() string
+}
+
+type webhookPlugin struct {
+	URL string `validate:"nonzero"`
+}
+
+func (p *webhookPlugin) PluginName() string { return "webhook" }
+
+type cronPlugin struct {
+	Schedule string `validate:"nonzero"`
+}
+
+func (cronPlugin) PluginName() string { return "cron" }
+
+func init() {
+	validator.RegisterType[pluginConfig]("plugin")
+	validator.RegisterType[webhookPlugin]("webhook")
+}
+
+func (ms *MySuite) TestImplements(c *C) {
+	type envelope struct {
+		Config interface{} `validate:"implements=plugin"`
+	}
+	for _, cfg := range []interface{}{nil, &webhookPlugin{URL: "https://example.com"}, webhookPlugin{URL: "x"}, cronPlugin{Schedule: "@daily"}, &cronPlugin{Schedule: "@daily"}} {
+		c.Assert(validator.Validate(envelope{cfg}), IsNil, Commentf("%T", cfg))
+	}
+
+	err := validator.Validate(envelope{"webhook"})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Config"], HasError, validator.ErrImplements)
+
+	// the value held is validated as well
+	err = validator.Validate(envelope{&webhookPlugin{}})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Config.URL"], HasError, validator.ErrZeroValue)
+
+	err = validator.Valid(cronPlugin{}, "implements=webhook")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+	err = validator.Valid(cronPlugin{}, "implements=unknown")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+	c.Assert(validator.RegisterType[pluginConfig](""), NotNil)
+}
+
+type paymentCard struct {
+	Number string `validate:"len=16"`
+}
+
+type paymentWallet struct {
+	Provider string `validate:"nonzero"`
+}
+
+type paymentCash struct{}
+
+type paymentVoucher struct {
+	Code string
+}
+
+func init() {
+	validator.RegisterType[paymentCard]("Card")
+	validator.RegisterType[*paymentWallet]("Wallet")
+	validator.RegisterType[paymentCash]("Cash")
+}
+
+func (ms *MySuite) TestTypeOneOf(c *C) {
+	type payment struct {
+		Method interface{} `validate:"nonnil,typeoneof=Card Wallet Cash"`
+	}
+	for _, m := range []interface{}{paymentCard{Number: "4111111111111111"}, &paymentCard{Number: "4111111111111111"}, paymentWallet{Provider: "pay"}, &paymentWallet{Provider: "pay"}, paymentCash{}} {
+		c.Assert(validator.Validate(payment{m}), IsNil, Commentf("%T", m))
+	}
+
+	err := validator.Validate(payment{paymentVoucher{Code: "X"}})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Method"], HasError, validator.ErrType)
+
+	err = validator.Validate(payment{&paymentCard{Number: "4111"}})
+	c.Assert(err, NotNil)
+	errs := err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Method.Number"], HasError, validator.ErrLen)
+
+	err = validator.Validate(payment{})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Method"], HasError, validator.ErrZeroValue)
+
+	err = validator.Valid(cronPlugin{}, "typeoneof=plugin Card")
+	c.Assert(err, IsNil)
+	err = validator.Valid(paymentCash{}, "typeoneof=Card Unknown")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+	err = validator.Valid(paymentCash{}, "typeoneof")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+}
+
+type orderCreated struct {
+	OrderID string `json:"order_id" validate:"nonzero"`
+	Total   int    `json:"total" validate:"min=1"`
+}
+
+type orderCancelled struct {
+	OrderID string `json:"order_id" validate:"nonzero"`
+	Reason  string `json:"reason" validate:"max=20"`
+}
+
+func init() {
+	validator.RegisterType[orderCreated]("order.created")
+	validator.RegisterType[orderCancelled]("order.cancelled")
+}
+
+func (ms *MySuite) TestDiscriminates(c *C) {
+	type event struct {
+		Type string          `json:"type" validate:"discriminates=Data"`
+		Data json.RawMessage `json:"data"`
+	}
+	err := validator.Validate(event{Type: "order.created", Data: json.RawMessage(`{"order_id":"o1","total":3}`)})
+	c.Assert(err, IsNil)
+	err = validator.Validate(event{Type: "order.cancelled", Data: json.RawMessage(`{"order_id":"o1","reason":"changed my mind"}`)})
+	c.Assert(err, IsNil)
+
+	err = validator.Validate(event{Type: "order.created", Data: json.RawMessage(`{"total":0}`)})
+	c.Assert(err, NotNil)
+	errs := err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 2, Commentf("%v", errs))
+	c.Assert(errs["Data.OrderID"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["Data.Total"], HasError, validator.ErrMin)
+
+	err = validator.WithPrintJSON(true).Validate(event{Type: "order.cancelled", Data: json.RawMessage(`{"reason":"a very long reason indeed"}`)})
+	c.Assert(err, NotNil)
+	errs = err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 2, Commentf("%v", errs))
+	c.Assert(errs["data.order_id"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["data.reason"], HasError, validator.ErrMax)
+
+	err = validator.Validate(event{Type: "order.created", Data: json.RawMessage(`[1]`)})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Data"], HasError, validator.ErrPayload)
+
+	err = validator.Validate(event{Type: "order.shipped", Data: json.RawMessage(`{}`)})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Type"], HasError, validator.ErrType)
+
+	err = validator.Valid("order.created", "discriminates=Payload")
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrBadParameter)
+
+	type tagged struct {
+		Kind    string                 `validate:"discriminates=Payload"`
+		Payload map[string]interface{} `validate:"nonzero"`
+	}
+	err = validator.Validate(tagged{Kind: "order.created", Payload: map[string]interface{}{"order_id": "o1", "total": 0}})
+	c.Assert(err, NotNil)
+	errs = err.(validator.ErrorMap)
+	c.Assert(errs, HasLen, 1, Commentf("%v", errs))
+	c.Assert(errs["Payload.Total"], HasError, validator.ErrMin)
+}
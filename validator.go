@@ -18,11 +18,15 @@ package validator
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // TextErr is an error that also implements the TextMarshaller interface for
@@ -92,6 +96,23 @@ func (err ErrorMap) Error() string {
 	return strings.TrimSuffix(b.String(), ", ")
 }
 
+// Unwrap returns the errors of the map as FieldErrors ordered by field,
+// so that errors.Is and errors.As find individual errors.
+func (err ErrorMap) Unwrap() []error {
+	fields := make([]string, 0, len(err))
+	for field := range err {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	var errs []error
+	for _, field := range fields {
+		for _, e := range err[field] {
+			errs = append(errs, FieldError{Field: field, Err: e})
+		}
+	}
+	return errs
+}
+
 // ErrorArray is a slice of errors returned by the Validate function.
 type ErrorArray []error
 
@@ -108,29 +129,209 @@ func (err ErrorArray) Error() string {
 	return strings.TrimSuffix(errs, ", ")
 }
 
+// Unwrap returns the errors of the array, so that errors.Is and
+// errors.As find individual errors, including errors joined by
+// validation functions.
+func (err ErrorArray) Unwrap() []error {
+	return err
+}
+
 // ValidationFunc is a function that receives the value of a
 // field and a parameter used for the respective validation tag.
 type ValidationFunc func(v interface{}, param string) error
 
+// FieldValidationFunc is like ValidationFunc but it also receives
+// information about the struct field being validated, which allows
+// rules that depend on the values of other fields.
+type FieldValidationFunc func(v interface{}, f Field, param string) error
+
+// Field describes the struct field being validated.
+type Field struct {
+	// Name is the name of the field within its struct.
+	Name string
+	// Parent is the struct the field belongs to, or the map validated
+	// with a RuleSet. It is the zero Value when the value is validated
+	// with Valid.
+	Parent reflect.Value
+
+	ctx context.Context
+	mv  *Validator
+	// keys holds the keys of the fields of a map validated with a
+	// RuleSet, by Go name.
+	keys map[string]string
+	// memo holds the results of pure validation functions within the
+	// validation, if any are set.
+	memo *memo
+}
+
+// Context returns the context passed to ValidateContext or ValidContext,
+// or the background context.
+func (f Field) Context() context.Context {
+	if f.ctx == nil {
+		return context.Background()
+	}
+	return f.ctx
+}
+
+// Validate validates v, such as a value decoded from the field, with the
+// validator and context the field is validated with, or a new Validator
+// for a Field built by hand.
+func (f Field) Validate(v interface{}) error {
+	mv := f.mv
+	if mv == nil {
+		mv = NewValidator()
+	}
+	return mv.ValidateContext(f.Context(), v)
+}
+
+// Sibling returns the value of the exported field called name in the
+// struct the validated field belongs to, or of the field called name in
+// a map validated with a RuleSet, following pointers and interfaces.
+// It returns false if there is no such field.
+func (f Field) Sibling(name string) (reflect.Value, bool) {
+	var sv reflect.Value
+	switch {
+	case !f.Parent.IsValid():
+		return reflect.Value{}, false
+	case f.Parent.Kind() == reflect.Struct:
+		sf, ok := f.Parent.Type().FieldByName(name)
+		if !ok || sf.PkgPath != "" {
+			return reflect.Value{}, false
+		}
+		sv = f.Parent.FieldByIndex(sf.Index)
+	case f.Parent.Kind() == reflect.Map && f.Parent.Type().Key().Kind() == reflect.String:
+		if key, ok := f.keys[name]; ok {
+			name = key
+		}
+		sv = f.Parent.MapIndex(reflect.ValueOf(name).Convert(f.Parent.Type().Key()))
+		if !sv.IsValid() {
+			return reflect.Value{}, false
+		}
+	default:
+		return reflect.Value{}, false
+	}
+	for (sv.Kind() == reflect.Ptr || sv.Kind() == reflect.Interface) && !sv.IsNil() {
+		sv = sv.Elem()
+	}
+	return sv, true
+}
+
+// StructValidationFunc is a function that receives a struct value
+// and validates it as a whole. It may return an ErrorMap to report
+// errors under the names of the struct's fields, such as
+// "Lines[0].ProductID".
+type StructValidationFunc func(v interface{}) error
+
 // Validator implements a validator
 type Validator struct {
 	// validationFuncs is a map of ValidationFuncs indexed
 	// by their name.
 	validationFuncs map[string]ValidationFunc
+	// fieldValidationFuncs is a map of FieldValidationFuncs
+	// indexed by their name.
+	fieldValidationFuncs map[string]FieldValidationFunc
+	// structValidationFuncs is a map of StructValidationFuncs
+	// indexed by the struct type they validate.
+	structValidationFuncs map[reflect.Type]StructValidationFunc
+	// traverseFuncs is a map of TraverseFuncs indexed
+	// by the container type they traverse.
+	traverseFuncs map[reflect.Type]TraverseFunc
+	// wrappers is a map of UnwrapFuncs indexed by the
+	// package path and name of the type they unwrap.
+	wrappers map[string]UnwrapFunc
+	// fieldRules holds the rules set with SetFieldRules, indexed
+	// by struct type and field name.
+	fieldRules map[reflect.Type]map[string]string
+	// labels holds the labels set with SetFieldLabel, indexed
+	// by key or field name.
+	labels map[string]string
+	// loaded holds the *ruleFile read with Reload, if any. It is
+	// shared with the validators derived with WithOptions.
+	loaded *atomic.Value
+	// aliases is a map of the tags an alias stands for
+	// indexed by the alias name.
+	aliases map[string]string
+	// custom holds the names of the validation functions and aliases
+	// set since the validator was created, which Merge takes over those
+	// of the other validator.
+	custom map[string]bool
 	// Tag name being used.
 	tagName string
-	// printJSON set to true will make errors print with the
-	// name of their json field instead of their struct tag.
-	// If no json tag is present the name of the struct field is used.
-	printJSON bool
+	// nameTag is the struct tag, such as json, errors are keyed by
+	// instead of the name of the struct field. If the tag is not
+	// present the name of the struct field is used.
+	nameTag string
+	// nameFunc names the fields errors are keyed by instead of
+	// nameTag, if set.
+	nameFunc func(sf reflect.StructField) string
+	// translate translates the messages of the errors returned by
+	// Check, if set.
+	translate TranslateFunc
+	// middleware holds the middleware set with Use, outermost first.
+	middleware []Middleware
+	// flags tells whether the constraints gated by iff are enforced.
+	flags FlagProvider
+	// shadow holds the candidate rules set with SetShadow.
+	shadow *shadow
+	// limits returns the limits named by constraint parameters.
+	limits LimitProvider
+	// locale is the locale numbers are read in when the context has
+	// none, and failFast stops validations at the first error.
+	locale   string
+	failFast bool
+	// coverage records the constraints evaluated, if set.
+	coverage *Coverage
+	// concurrent holds the names of the rules whose fields are
+	// validated concurrently. It is replaced, never changed.
+	concurrent map[string]bool
+	// pure holds the names of the validation functions whose results
+	// are memoized within a validation. It is replaced, never changed.
+	pure map[string]bool
+	// soft holds the names of the rules whose errors are reported as
+	// warnings. It is replaced, never changed.
+	soft map[string]bool
+	// audit is called with the records of validations, if set.
+	audit *auditor
+	// versions caches the versions of the rules mv enforces, by
+	// rulesKey. It is shared with the validators derived with
+	// WithOptions until either changes its functions or rules.
+	versions *sync.Map
+	// compiled caches the tags parsed by parseTags. It is shared like
+	// versions.
+	compiled *tagCache
+	// keyFormat formats the keys of map elements in paths, if set.
+	keyFormat KeyFormatFunc
+	// isDefault is set on the default validator, whose configuration
+	// is locked by its first validation.
+	isDefault bool
+	// shared is set, for the validator a validator is derived from
+	// with WithOptions and for all those derived from it, once they
+	// share their functions and aliases, which each copies before
+	// changing them. It is set atomically, as validators are derived
+	// concurrently with their use.
+	shared *atomic.Bool
 }
 
 // Helper validator so users can use the
 // functions directly from the package
 var defaultValidator = NewValidator()
 
-// NewValidator creates a new Validator
+// NewValidator creates a new Validator with all of the builtin
+// validation functions, those of NewCoreValidator and of the builtin
+// bundles.
 func NewValidator() *Validator {
+	mv := NewCoreValidator()
+	for _, b := range []Bundle{Network, Payments, Geo, Strings} {
+		b.Register(mv)
+	}
+	mv.custom = map[string]bool{}
+	return mv
+}
+
+// NewCoreValidator creates a new Validator with the core validation
+// functions only, such as nonzero, len, min, max and regexp, to which
+// the bundles needed are added with Register.
+func NewCoreValidator() *Validator {
 	return &Validator{
 		tagName: "validate",
 		validationFuncs: map[string]ValidationFunc{
@@ -140,14 +341,49 @@ func NewValidator() *Validator {
 			"max":     max,
 			"regexp":  regex,
 			"nonnil":  nonnil,
+
+			"enum":       enum,
+			"flags":      flagmask,
+			"acyclic":    acyclic,
+			"filter":     filter,
+			"implements": implements,
+			"typeoneof":  typeoneof,
+			"minbytes":   minbytes,
+			"maxbytes":   maxbytes,
+			"before":     before,
+			"after":      after,
+
+			"eq_canonical": eqCanonical,
+		},
+		fieldValidationFuncs: map[string]FieldValidationFunc{
+			"discriminates": discriminates,
+			"exclusive":     exclusive,
+			"iff":           iff,
+			"sample":        sample,
+		},
+		structValidationFuncs: map[reflect.Type]StructValidationFunc{},
+		traverseFuncs:         map[reflect.Type]TraverseFunc{},
+		wrappers:              map[string]UnwrapFunc{},
+		fieldRules:            map[reflect.Type]map[string]string{},
+		labels:                map[string]string{},
+		loaded:                newLoaded(nil),
+		aliases: map[string]string{
+			"pagelimit": "min=0,max=100",
 		},
-		printJSON: false,
+		custom:   map[string]bool{},
+		versions: new(sync.Map),
+		compiled: new(tagCache),
+		shared:   new(atomic.Bool),
+		nameTag:  "",
 	}
 }
 
 // SetTag allows you to change the tag name used in structs
 func SetTag(tag string) {
-	defaultValidator.SetTag(tag)
+	setDefault(func() error {
+		defaultValidator.SetTag(tag)
+		return nil
+	})
 }
 
 // SetTag allows you to change the tag name used in structs
@@ -171,14 +407,50 @@ func (mv *Validator) WithTag(tag string) *Validator {
 	return v
 }
 
+// SetNameTag allows you to print errors with the names given by another
+// struct tag, such as json or xml. An empty tag restores field names.
+func SetNameTag(tag string) {
+	setDefault(func() error {
+		defaultValidator.SetNameTag(tag)
+		return nil
+	})
+}
+
+// SetNameTag allows you to print errors with the names given by another
+// struct tag, such as json or xml. An empty tag restores field names.
+func (mv *Validator) SetNameTag(tag string) {
+	mv.nameTag = tag
+}
+
+// WithNameTag creates a new Validator printing errors with the names
+// given by another struct tag: validator.WithNameTag("xml").Validate(t)
+func WithNameTag(tag string) *Validator {
+	return defaultValidator.WithNameTag(tag)
+}
+
+// WithNameTag creates a new Validator printing errors with the names
+// given by another struct tag: validator.WithNameTag("xml").Validate(t)
+func (mv *Validator) WithNameTag(tag string) *Validator {
+	v := mv.copy()
+	v.SetNameTag(tag)
+	return v
+}
+
 // SetPrintJSON allows you to print errors with json tag names present in struct tags
 func SetPrintJSON(printJSON bool) {
-	defaultValidator.SetPrintJSON(printJSON)
+	setDefault(func() error {
+		defaultValidator.SetPrintJSON(printJSON)
+		return nil
+	})
 }
 
 // SetPrintJSON allows you to print errors with json tag names present in struct tags
 func (mv *Validator) SetPrintJSON(printJSON bool) {
-	mv.printJSON = printJSON
+	if printJSON {
+		mv.nameTag = "json"
+	} else {
+		mv.nameTag = ""
+	}
 }
 
 // WithPrintJSON creates a new Validator with printJSON set to new value. It is
@@ -203,10 +475,68 @@ func (mv *Validator) copy() *Validator {
 	for k, f := range mv.validationFuncs {
 		newFuncs[k] = f
 	}
+	newFieldFuncs := map[string]FieldValidationFunc{}
+	for k, f := range mv.fieldValidationFuncs {
+		newFieldFuncs[k] = f
+	}
+	newStructFuncs := map[reflect.Type]StructValidationFunc{}
+	for k, f := range mv.structValidationFuncs {
+		newStructFuncs[k] = f
+	}
+	newTraverseFuncs := map[reflect.Type]TraverseFunc{}
+	for k, f := range mv.traverseFuncs {
+		newTraverseFuncs[k] = f
+	}
+	newWrappers := map[string]UnwrapFunc{}
+	for k, f := range mv.wrappers {
+		newWrappers[k] = f
+	}
+	newFieldRules := map[reflect.Type]map[string]string{}
+	for k, r := range mv.fieldRules {
+		newFieldRules[k] = r
+	}
+	newLabels := map[string]string{}
+	for k, l := range mv.labels {
+		newLabels[k] = l
+	}
+	newAliases := map[string]string{}
+	for k, a := range mv.aliases {
+		newAliases[k] = a
+	}
+	newCustom := map[string]bool{}
+	for k := range mv.custom {
+		newCustom[k] = true
+	}
 	return &Validator{
-		tagName:         mv.tagName,
-		validationFuncs: newFuncs,
-		printJSON:       mv.printJSON,
+		tagName:               mv.tagName,
+		validationFuncs:       newFuncs,
+		fieldValidationFuncs:  newFieldFuncs,
+		structValidationFuncs: newStructFuncs,
+		traverseFuncs:         newTraverseFuncs,
+		wrappers:              newWrappers,
+		fieldRules:            newFieldRules,
+		labels:                newLabels,
+		loaded:                newLoaded(mv.ruleFile()),
+		aliases:               newAliases,
+		custom:                newCustom,
+		nameTag:               mv.nameTag,
+		nameFunc:              mv.nameFunc,
+		translate:             mv.translate,
+		middleware:            append([]Middleware(nil), mv.middleware...),
+		flags:                 mv.flags,
+		shadow:                mv.shadow,
+		limits:                mv.limits,
+		locale:                mv.locale,
+		failFast:              mv.failFast,
+		coverage:              mv.coverage,
+		concurrent:            mv.concurrent,
+		pure:                  mv.pure,
+		soft:                  mv.soft,
+		keyFormat:             mv.keyFormat,
+		audit:                 mv.audit,
+		versions:              new(sync.Map),
+		compiled:              new(tagCache),
+		shared:                new(atomic.Bool),
 	}
 }
 
@@ -214,7 +544,9 @@ func (mv *Validator) copy() *Validator {
 // validation constraint. Calling this function with nil vf
 // is the same as removing the constraint function from the list.
 func SetValidationFunc(name string, vf ValidationFunc) error {
-	return defaultValidator.SetValidationFunc(name, vf)
+	return setDefault(func() error {
+		return defaultValidator.SetValidationFunc(name, vf)
+	})
 }
 
 // SetValidationFunc sets the function to be used for a given
@@ -224,6 +556,9 @@ func (mv *Validator) SetValidationFunc(name string, vf ValidationFunc) error {
 	if name == "" {
 		return errors.New("name cannot be empty")
 	}
+	mv.own()
+	mv.custom[name] = true
+	delete(mv.fieldValidationFuncs, name)
 	if vf == nil {
 		delete(mv.validationFuncs, name)
 		return nil
@@ -232,16 +567,151 @@ func (mv *Validator) SetValidationFunc(name string, vf ValidationFunc) error {
 	return nil
 }
 
+// SetFieldValidationFunc sets the field-aware function to be used for
+// a given validation constraint. It replaces any ValidationFunc with the
+// same name. Calling this function with nil vf is the same as removing
+// the constraint function from the list.
+func SetFieldValidationFunc(name string, vf FieldValidationFunc) error {
+	return setDefault(func() error {
+		return defaultValidator.SetFieldValidationFunc(name, vf)
+	})
+}
+
+// SetFieldValidationFunc sets the field-aware function to be used for
+// a given validation constraint. It replaces any ValidationFunc with the
+// same name. Calling this function with nil vf is the same as removing
+// the constraint function from the list.
+func (mv *Validator) SetFieldValidationFunc(name string, vf FieldValidationFunc) error {
+	if name == "" {
+		return errors.New("name cannot be empty")
+	}
+	mv.own()
+	mv.custom[name] = true
+	delete(mv.validationFuncs, name)
+	if vf == nil {
+		delete(mv.fieldValidationFuncs, name)
+		return nil
+	}
+	mv.fieldValidationFuncs[name] = vf
+	return nil
+}
+
+// SetAlias sets a name standing for the given tags, so that a rule set
+// used in many places can be defined once: SetAlias("username",
+// "min=3,max=40,regexp=^[a-z]+$"). Validation functions take precedence
+// over aliases with the same name. Calling this function with empty tags
+// removes the alias.
+func SetAlias(alias, tags string) error {
+	return setDefault(func() error {
+		return defaultValidator.SetAlias(alias, tags)
+	})
+}
+
+// SetAlias sets a name standing for the given tags, so that a rule set
+// used in many places can be defined once: SetAlias("username",
+// "min=3,max=40,regexp=^[a-z]+$"). Validation functions take precedence
+// over aliases with the same name. Calling this function with empty tags
+// removes the alias.
+func (mv *Validator) SetAlias(alias, tags string) error {
+	if alias == "" {
+		return errors.New("alias cannot be empty")
+	}
+	mv.own()
+	mv.custom[alias] = true
+	if tags == "" {
+		delete(mv.aliases, alias)
+		return nil
+	}
+	mv.aliases[alias] = tags
+	return nil
+}
+
+// SetStructValidationFunc sets the function used to validate structs
+// of the same type as typ as a whole, once their fields have been
+// validated. It gives access to all of the struct's fields at once, such
+// as when the items of one slice must refer to the items of another.
+// Calling this function with nil fn removes the function for the type.
+func SetStructValidationFunc(typ interface{}, fn StructValidationFunc) error {
+	return setDefault(func() error {
+		return defaultValidator.SetStructValidationFunc(typ, fn)
+	})
+}
+
+// SetStructValidationFunc sets the function used to validate structs
+// of the same type as typ as a whole, once their fields have been
+// validated. It gives access to all of the struct's fields at once, such
+// as when the items of one slice must refer to the items of another.
+// Calling this function with nil fn removes the function for the type.
+func (mv *Validator) SetStructValidationFunc(typ interface{}, fn StructValidationFunc) error {
+	t := reflect.TypeOf(typ)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return errors.New("type must be a struct")
+	}
+	mv.own()
+	if fn == nil {
+		delete(mv.structValidationFuncs, t)
+		return nil
+	}
+	mv.structValidationFuncs[t] = fn
+	return nil
+}
+
 // Validate calls the Validate method on the default validator.
 func Validate(v interface{}) error {
 	return defaultValidator.Validate(v)
 }
 
 // Validate validates the fields of structs (included embedded structs) based on
-// 'validator' tags and returns errors found indexed by the field name.
+// 'validator' tags and returns errors found indexed by the field name. Slices,
+// arrays and maps are walked, with the errors of their items indexed as in
+// "[0].Field" or "[key](value).Field".
 func (mv *Validator) Validate(v interface{}) error {
+	return mv.ValidateContext(context.Background(), v)
+}
+
+// ValidateContext calls the ValidateContext method on the default validator.
+func ValidateContext(ctx context.Context, v interface{}) error {
+	return defaultValidator.ValidateContext(ctx, v)
+}
+
+// ValidateContext is like Validate but makes ctx available to field
+// validation functions, which may use it to cancel remote calls or to
+// read request-scoped values.
+//
+// Validate and ValidateContext accept values of any type without
+// panicking, as long as validation functions do not panic. Nil values,
+// including nil pointers, return ErrInvalid. Structs, slices, arrays
+// and maps are validated along with the values they hold, while other
+// values have no rules to break. Unexported fields are not validated
+// and values referring to themselves are only validated once.
+func (mv *Validator) ValidateContext(ctx context.Context, v interface{}) error {
+	mv.lockDefault()
+	vctx := mv.context(ctx)
+	err := mv.validate(vctx, v)
+	if mv.shadow != nil {
+		mv.shadow.compare(ctx, v, err)
+	}
+	if mv.audit != nil {
+		mv.record(vctx, v, err)
+	}
+	return err
+}
+
+// validate is ValidateContext without the shadow set with SetShadow.
+func (mv *Validator) validate(ctx context.Context, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || (rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface) && rv.IsNil() {
+		return ErrInvalid
+	}
 	m := make(ErrorMap)
-	mv.deepValidateCollection(reflect.ValueOf(v), m, func() string {
+	w := &walk{ctx: ctx, path: map[visit]bool{}, rules: mv.ruleFile()}
+	if len(mv.pure) > 0 {
+		w.memo = &memo{results: map[memoKey]error{}}
+	}
+	mv.deepValidateCollection(w, rv, m, func() string {
 		return ""
 	})
 	if len(m) > 0 {
@@ -250,31 +720,201 @@ func (mv *Validator) Validate(v interface{}) error {
 	return nil
 }
 
-func (mv *Validator) validateStruct(sv reflect.Value, m ErrorMap) error {
+// walk holds the state of a call to ValidateContext.
+type walk struct {
+	ctx context.Context
+	// depth is the number of structs being validated and prefix
+	// the key of the innermost one, when middleware is used.
+	depth  int
+	prefix string
+	// path holds the values being validated, to stop at values
+	// that refer to themselves.
+	path map[visit]bool
+	// stop is set once an error is found by a fail fast validator.
+	stop bool
+	// pending holds the fields of the innermost struct being validated
+	// concurrently.
+	pending []*pendingField
+	// memo holds the results of pure validation functions.
+	memo *memo
+	// rules holds the rules read with Reload when the walk started,
+	// and owner the named type of the innermost struct of such a type
+	// being validated, whose rules apply to the anonymous structs it
+	// holds.
+	rules *ruleFile
+	owner reflect.Type
+}
+
+// visit identifies a struct, slice or map by its address and type.
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// enter adds the value at f to the path, unless it is already there.
+// It returns false if the value refers to itself.
+func (w *walk) enter(f reflect.Value) (visit, bool) {
+	var v visit
+	switch {
+	case f.Kind() == reflect.Struct && f.CanAddr():
+		v = visit{f.UnsafeAddr(), f.Type()}
+	case f.Kind() == reflect.Slice || f.Kind() == reflect.Map:
+		v = visit{f.Pointer(), f.Type()}
+	}
+	if v.ptr == 0 {
+		return v, true
+	}
+	if w.path[v] {
+		return v, false
+	}
+	w.path[v] = true
+	return v, true
+}
+
+// leave removes v from the path.
+func (w *walk) leave(v visit) {
+	if v.ptr != 0 {
+		delete(w.path, v)
+	}
+}
+
+func (mv *Validator) validateStruct(w *walk, sv reflect.Value, m ErrorMap) error {
 	kind := sv.Kind()
 	if (kind == reflect.Ptr || kind == reflect.Interface) && !sv.IsNil() {
-		return mv.validateStruct(sv.Elem(), m)
+		return mv.validateStruct(w, sv.Elem(), m)
 	}
 	if kind != reflect.Struct && kind != reflect.Interface {
 		return ErrUnsupported
 	}
 
 	st := sv.Type()
+	outer, owner := w.pending, w.owner
+	w.pending, w.owner = nil, ownerOf(w.owner, st)
+	defer func() { w.pending, w.owner = outer, owner }()
+
 	nfields := st.NumField()
-	for i := 0; i < nfields; i++ {
-		if err := mv.validateField(st.Field(i), sv.Field(i), m); err != nil {
+	start := len(m)
+	for i := 0; i < nfields && !w.stop; i++ {
+		n := len(m)
+		if st.Field(i).Name == "_" {
+			mv.validateStructLevel(w, st.Field(i), sv, m)
+		} else if err := mv.validateField(w, st.Field(i), sv.Field(i), sv, m); err != nil {
+			mv.awaitFields(w, sv, m)
 			return err
 		}
+		w.stop = mv.failFast && len(m) > n
+	}
+	if len(w.pending) > 0 {
+		mv.awaitFields(w, sv, m)
+		w.stop = mv.failFast && len(m) > start
+	}
+	if !w.stop {
+		mv.runStructValidationFunc(sv, m)
 	}
 
 	return nil
 }
 
+// validateStructLevel validates sv against the tag of fieldDef, a blank
+// (_) field used to hold rules that apply to the struct as a whole.
+// Errors are reported under the empty name, that is under the name of
+// the struct itself.
+func (mv *Validator) validateStructLevel(w *walk, fieldDef reflect.StructField, sv reflect.Value, m ErrorMap) {
+	tag := mv.ruleTag(w.rules, w.owner, sv.Type(), fieldDef)
+	if tag == "" || tag == "-" {
+		return
+	}
+	if !sv.CanInterface() {
+		m.add("", ErrCannotValidate)
+		return
+	}
+	err := mv.validateVar(sv.Interface(), Field{Name: fieldDef.Name, Parent: sv, ctx: w.ctx, mv: mv, memo: w.memo}, tag)
+	m.add("", mv.moveErrorMaps(sv, err, m))
+}
+
+// moveErrorMaps moves the errors of the ErrorMaps returned by field
+// validation functions, which are keyed relative to the struct sv, to m.
+// Keys starting with the name of a field of sv are renamed like the
+// field. It returns the other errors of err.
+func (mv *Validator) moveErrorMaps(sv reflect.Value, err error, m ErrorMap) error {
+	errs, ok := err.(ErrorArray)
+	if !ok {
+		return err
+	}
+	var rest ErrorArray
+	for _, e := range errs {
+		em, ok := e.(ErrorMap)
+		if !ok {
+			rest = append(rest, e)
+			continue
+		}
+		for key, errarr := range em {
+			name := key
+			if i := strings.IndexAny(key, ".["); i >= 0 {
+				name = key[:i]
+			}
+			if sf, ok := sv.Type().FieldByName(name); ok && name != "" {
+				key = mv.fieldName(sf) + key[len(name):]
+			}
+			m.add(key, errarr)
+		}
+	}
+	if len(rest) == 0 {
+		return nil
+	}
+	return rest
+}
+
+// runStructValidationFunc runs the StructValidationFunc set for the
+// type of sv, if any. Errors returned in an ErrorMap are reported under
+// their names within the struct, others under the name of the struct.
+func (mv *Validator) runStructValidationFunc(sv reflect.Value, m ErrorMap) {
+	fn, ok := mv.structValidationFuncs[sv.Type()]
+	if !ok || !sv.CanInterface() {
+		return
+	}
+	err := fn(sv.Interface())
+	if errs, ok := err.(ErrorMap); ok {
+		for name, errarr := range errs {
+			m.add(name, errarr)
+		}
+		return
+	}
+	m.add("", err)
+}
+
+// add appends err, or the errors of err if it is an ErrorArray,
+// to the errors found for the given name.
+func (err ErrorMap) add(name string, e error) {
+	if errarr, ok := e.(ErrorArray); ok {
+		if len(errarr) > 0 {
+			err[name] = append(err[name], errarr...)
+		}
+	} else if e != nil {
+		err[name] = append(err[name], e)
+	}
+}
+
+// joinKey returns the key of the error key within the value keyed by
+// prefix, as in "Lines[2].Price".
+func joinKey(prefix, key string) string {
+	switch {
+	case prefix == "":
+		return key
+	case key == "":
+		return prefix
+	case key[0] == '[':
+		return prefix + key
+	default:
+		return prefix + "." + key
+	}
+}
+
 // validateField validates the field of fieldVal referred to by fieldDef.
 // If fieldDef refers to an anonymous/embedded field,
 // validateField will walk all of the embedded type's fields and validate them on sv.
-func (mv *Validator) validateField(fieldDef reflect.StructField, fieldVal reflect.Value, m ErrorMap) error {
-	tag := fieldDef.Tag.Get(mv.tagName)
+func (mv *Validator) validateField(w *walk, fieldDef reflect.StructField, fieldVal, sv reflect.Value, m ErrorMap) error {
+	tag := mv.ruleTag(w.rules, w.owner, sv.Type(), fieldDef)
 	if tag == "-" {
 		return nil
 	}
@@ -287,14 +927,22 @@ func (mv *Validator) validateField(fieldDef reflect.StructField, fieldVal reflec
 	if !fieldDef.Anonymous && fieldDef.PkgPath != "" {
 		return nil
 	}
+	if inner, set, ok := mv.unwrap(fieldVal); ok && tag != "" {
+		fieldVal, tag = inner, mv.wrappedTag(tag, set)
+	}
 
 	var errs ErrorArray
 	if tag != "" {
 		var err error
-		if fieldDef.PkgPath != "" {
+		f := Field{Name: fieldDef.Name, Parent: sv, ctx: w.ctx, mv: mv, memo: w.memo}
+		switch {
+		case fieldDef.PkgPath != "":
 			err = ErrCannotValidate
-		} else {
-			err = mv.validValue(fieldVal, tag)
+		case mv.concurrently(tag):
+			w.pending = append(w.pending, mv.startField(fieldVal, f, tag, mv.fieldName(fieldDef)))
+		default:
+			err = mv.validValue(fieldVal, f, tag)
+			err = mv.moveErrorMaps(sv, err, m)
 		}
 		if errarr, ok := err.(ErrorArray); ok {
 			errs = errarr
@@ -305,42 +953,92 @@ func (mv *Validator) validateField(fieldDef reflect.StructField, fieldVal reflec
 
 	// no-op if field is not a struct, interface, array, slice or map
 	fn := mv.fieldName(fieldDef)
-	mv.deepValidateCollection(fieldVal, m, func() string {
+	mv.deepValidateCollection(w, fieldVal, m, func() string {
 		return fn
 	})
 
 	if len(errs) > 0 {
-		m[fn] = errs
+		m[fn] = append(errs, m[fn]...)
 	}
 	return nil
 }
 
 func (mv *Validator) fieldName(fieldDef reflect.StructField) string {
-	if mv.printJSON {
-		if jsonTagValue, ok := fieldDef.Tag.Lookup("json"); ok {
-			return parseName(jsonTagValue)
+	if mv.nameFunc != nil {
+		return mv.nameFunc(fieldDef)
+	}
+	if mv.nameTag == "" {
+		return fieldDef.Name
+	}
+	tagValue, ok := fieldDef.Tag.Lookup(mv.nameTag)
+	switch mv.nameTag {
+	case "yaml":
+		return parseYAMLName(tagValue, fieldDef.Name)
+	case "protobuf":
+		if _, oneof := fieldDef.Tag.Lookup("protobuf_oneof"); oneof {
+			return ""
+		}
+		if name := parseProtobufName(tagValue); name != "" {
+			return name
+		}
+		return fieldDef.Name
+	}
+	if !ok {
+		return fieldDef.Name
+	}
+	if mv.nameTag == "xml" {
+		if name := parseXMLName(tagValue); name != "" {
+			return name
 		}
+		return fieldDef.Name
 	}
-	return fieldDef.Name
+	return parseName(tagValue)
 }
 
-func (mv *Validator) deepValidateCollection(f reflect.Value, m ErrorMap, fnameFn func() string) {
+func (mv *Validator) deepValidateCollection(w *walk, f reflect.Value, m ErrorMap, fnameFn func() string) {
+	v, ok := w.enter(f)
+	if !ok {
+		return
+	}
+	defer w.leave(v)
+
+	if inner, set, ok := mv.unwrap(f); ok {
+		if set {
+			mv.deepValidateCollection(w, inner, m, fnameFn)
+		}
+		return
+	}
+	if fn, ok := mv.traverseFunc(f); ok {
+		for _, e := range fn(f) {
+			if w.stop {
+				break
+			}
+			e := e
+			mv.deepValidateCollection(w, e.Value, m, func() string {
+				return joinKey(fnameFn(), e.Key)
+			})
+		}
+		return
+	}
+
 	switch f.Kind() {
 	case reflect.Interface, reflect.Ptr:
 		if f.IsNil() {
 			return
 		}
-		mv.deepValidateCollection(f.Elem(), m, fnameFn)
+		mv.deepValidateCollection(w, f.Elem(), m, fnameFn)
 	case reflect.Struct:
 		subm := make(ErrorMap)
-		err := mv.validateStruct(f, subm)
 		parentName := fnameFn()
+		err := mv.runStruct(w, f, parentName, subm)
 		if err != nil {
 			m[parentName] = ErrorArray{err}
 		}
 		for j, k := range subm {
 			keyName := j
-			if parentName != "" {
+			if j == "" {
+				keyName = parentName
+			} else if parentName != "" {
 				keyName = parentName + "." + keyName
 			}
 			m[keyName] = k
@@ -350,20 +1048,23 @@ func (mv *Validator) deepValidateCollection(f reflect.Value, m ErrorMap, fnameFn
 		// looping when the kind is something we care about
 		switch f.Type().Elem().Kind() {
 		case reflect.Struct, reflect.Interface, reflect.Ptr, reflect.Map, reflect.Array, reflect.Slice:
-			for i := 0; i < f.Len(); i++ {
-				mv.deepValidateCollection(f.Index(i), m, func() string {
+			for i := 0; i < f.Len() && !w.stop; i++ {
+				mv.deepValidateCollection(w, f.Index(i), m, func() string {
 					return fmt.Sprintf("%s[%d]", fnameFn(), i)
 				})
 			}
 		}
 	case reflect.Map:
 		for _, key := range f.MapKeys() {
-			mv.deepValidateCollection(key, m, func() string {
-				return fmt.Sprintf("%s[%+v](key)", fnameFn(), key.Interface())
+			if w.stop {
+				break
+			}
+			mv.deepValidateCollection(w, key, m, func() string {
+				return fnameFn() + "[" + mv.formatKey(key) + "](key)"
 			}) // validate the map key
 			value := f.MapIndex(key)
-			mv.deepValidateCollection(value, m, func() string {
-				return fmt.Sprintf("%s[%+v](value)", fnameFn(), key.Interface())
+			mv.deepValidateCollection(w, value, m, func() string {
+				return fnameFn() + "[" + mv.formatKey(key) + "](value)"
 			})
 		}
 	}
@@ -378,29 +1079,43 @@ func Valid(val interface{}, tags string) error {
 // Valid validates a value based on the provided
 // tags and returns errors found or nil.
 func (mv *Validator) Valid(val interface{}, tags string) error {
+	return mv.ValidContext(context.Background(), val, tags)
+}
+
+// ValidContext calls the ValidContext method on the default validator.
+func ValidContext(ctx context.Context, val interface{}, tags string) error {
+	return defaultValidator.ValidContext(ctx, val, tags)
+}
+
+// ValidContext is like Valid but makes ctx available
+// to field validation functions.
+func (mv *Validator) ValidContext(ctx context.Context, val interface{}, tags string) error {
+	mv.lockDefault()
+	ctx = mv.context(ctx)
 	if tags == "-" {
 		return nil
 	}
+	f := Field{ctx: ctx, mv: mv}
 	v := reflect.ValueOf(val)
 	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && !v.IsNil() {
-		return mv.validValue(v.Elem(), tags)
+		return mv.validValue(v.Elem(), f, tags)
 	}
 	if v.Kind() == reflect.Invalid {
-		return mv.validateVar(nil, tags)
+		return mv.validateVar(nil, f, tags)
 	}
-	return mv.validateVar(val, tags)
+	return mv.validateVar(val, f, tags)
 }
 
 // validValue is like Valid but takes a Value instead of an interface
-func (mv *Validator) validValue(v reflect.Value, tags string) error {
+func (mv *Validator) validValue(v reflect.Value, f Field, tags string) error {
 	if v.Kind() == reflect.Invalid {
-		return mv.validateVar(nil, tags)
+		return mv.validateVar(nil, f, tags)
 	}
-	return mv.validateVar(v.Interface(), tags)
+	return mv.validateVar(v.Interface(), f, tags)
 }
 
 // validateVar validates one single variable
-func (mv *Validator) validateVar(v interface{}, tag string) error {
+func (mv *Validator) validateVar(v interface{}, f Field, tag string) error {
 	tags, err := mv.parseTags(tag)
 	if err != nil {
 		// unknown tag found, give up.
@@ -408,9 +1123,32 @@ func (mv *Validator) validateVar(v interface{}, tag string) error {
 	}
 	errs := make(ErrorArray, 0, len(tags))
 	for _, t := range tags {
-		if err := t.Fn(v, t.Param); err != nil {
+		param, ok, err := mv.limit(f, t)
+		switch {
+		case err != nil:
+		case !ok:
+			continue
+		case t.FieldFn != nil:
+			err = t.FieldFn(v, f, param)
+		case f.memo != nil && mv.pure[t.Name]:
+			err = f.memo.call(t, v, param)
+		default:
+			err = t.Fn(v, param)
+		}
+		if err != nil && mv.soft[t.Name] {
+			err = soften(err)
+		}
+		if mv.coverage != nil {
+			mv.coverage.record(mv, f, t, err)
+		}
+		if arr, ok := err.(ErrorArray); ok {
+			errs = append(errs, arr...)
+		} else if err != nil {
 			errs = append(errs, err)
 		}
+		if mv.failFast && len(errs) > 0 {
+			break
+		}
 	}
 	if len(errs) > 0 {
 		return errs
@@ -420,9 +1158,11 @@ func (mv *Validator) validateVar(v interface{}, tag string) error {
 
 // tag represents one of the tag items
 type tag struct {
-	Name  string         // name of the tag
-	Fn    ValidationFunc // validation function to call
-	Param string         // parameter to send to the validation function
+	Name    string              // name of the tag
+	Fn      ValidationFunc      // validation function to call
+	FieldFn FieldValidationFunc // field-aware validation function to call
+	Param   string              // parameter to send to the validation function
+	Alias   string              // alias the tag was expanded from, if any
 }
 
 // separate by no escaped commas
@@ -440,8 +1180,45 @@ func splitUnescapedComma(str string) []string {
 	return ret
 }
 
-// parseTags parses all individual tags found within a struct tag.
+// maxCompiledTags is the number of tags cached by a validator, so that
+// rules built while a program runs, such as those given to Valid, do not
+// grow the cache without bound.
+const maxCompiledTags = 4096
+
+// tagCache holds the tags parsed by parseTags, by the string they were
+// parsed from.
+type tagCache struct {
+	tags sync.Map
+	n    int64
+}
+
+// compiledTags are the tags parsed from a string, or the error parsing
+// them, when the rules of validators were at generation gen.
+type compiledTags struct {
+	gen  uint64
+	tags []tag
+	err  error
+}
+
+// parseTags parses all individual tags found within a struct tag. The
+// tags are parsed once, along with the sizes given to the builtin length
+// constraints, and again only once the functions or aliases of a
+// validator have changed. They must not be modified.
 func (mv *Validator) parseTags(t string) ([]tag, error) {
+	gen := atomic.LoadUint64(&rulesGen)
+	if c, ok := mv.compiled.tags.Load(t); ok && c.(compiledTags).gen == gen {
+		return c.(compiledTags).tags, c.(compiledTags).err
+	}
+	tags, err := mv.parseAliasedTags(t, map[string]bool{})
+	if _, ok := mv.compiled.tags.Load(t); ok || atomic.AddInt64(&mv.compiled.n, 1) <= maxCompiledTags {
+		mv.compiled.tags.Store(t, compiledTags{gen, tags, err})
+	}
+	return tags, err
+}
+
+// parseAliasedTags is like parseTags but takes the set of aliases being
+// expanded, which cannot be used again within their own expansion.
+func (mv *Validator) parseAliasedTags(t string, expanding map[string]bool) ([]tag, error) {
 	tl := splitUnescapedComma(t)
 	tags := make([]tag, 0, len(tl))
 	for _, i := range tl {
@@ -457,7 +1234,32 @@ func (mv *Validator) parseTags(t string) ([]tag, error) {
 		}
 		var found bool
 		if tg.Fn, found = mv.validationFuncs[tg.Name]; !found {
-			return []tag{}, ErrUnknownTag
+			if tg.FieldFn, found = mv.fieldValidationFuncs[tg.Name]; !found {
+				alias, ok := mv.aliases[tg.Name]
+				if !ok || expanding[tg.Name] {
+					return []tag{}, ErrUnknownTag
+				}
+				if len(v) > 1 {
+					return []tag{}, ErrBadParameter
+				}
+				expanding[tg.Name] = true
+				aliased, err := mv.parseAliasedTags(alias, expanding)
+				delete(expanding, tg.Name)
+				if err != nil {
+					return []tag{}, err
+				}
+				for _, a := range aliased {
+					a.Alias = tg.Name
+					tags = append(tags, a)
+				}
+				continue
+			}
+		}
+		if tg.Fn != nil && limitTags[tg.Name] && !mv.custom[tg.Name] {
+			// sizes are parsed once, with the tags
+			if fn := sizeFunc(tg.Name, tg.Param); fn != nil {
+				tg.Fn = fn
+			}
 		}
 		tags = append(tags, tg)
 
@@ -465,6 +1267,14 @@ func (mv *Validator) parseTags(t string) ([]tag, error) {
 	return tags, nil
 }
 
+// splitParams splits a parameter holding a list of values separated
+// by spaces or escaped commas.
+func splitParams(param string) []string {
+	return strings.FieldsFunc(param, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+}
+
 func parseName(tag string) string {
 	if tag == "" {
 		return ""
@@ -478,3 +1288,44 @@ func parseName(tag string) string {
 	}
 	return name
 }
+
+// parseXMLName returns the name given by an xml tag, without namespace.
+// Parent elements, as in "a>b", are separated by dots like nested fields.
+func parseXMLName(tag string) string {
+	name := parseName(tag)
+	if i := strings.LastIndexByte(name, ' '); i >= 0 {
+		name = name[i+1:]
+	}
+	return strings.Replace(name, ">", ".", -1)
+}
+
+// parseProtobufName returns the JSON name of a protobuf field, its
+// json_name option or else its name, from a generated protobuf tag such
+// as "bytes,1,opt,name=display_name,json=displayName,proto3".
+func parseProtobufName(tag string) string {
+	var name string
+	for _, opt := range strings.Split(tag, ",") {
+		if strings.HasPrefix(opt, "json=") {
+			return opt[len("json="):]
+		}
+		if strings.HasPrefix(opt, "name=") {
+			name = opt[len("name="):]
+		}
+	}
+	return name
+}
+
+// parseYAMLName returns the name given by a yaml tag, which defaults to
+// the lower case field name. Inlined fields have no name of their own.
+func parseYAMLName(tag, field string) string {
+	opts := strings.Split(tag, ",")
+	for _, opt := range opts[1:] {
+		if opt == "inline" {
+			return ""
+		}
+	}
+	if opts[0] == "" {
+		return strings.ToLower(field)
+	}
+	return parseName(tag)
+}
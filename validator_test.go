@@ -511,6 +511,105 @@ func (ms *MySuite) TestCopy(c *C) {
 	c.Assert(errs["A"], HasError, validator.ErrUnknownTag)
 }
 
+func (ms *MySuite) TestFieldValidationFunc(c *C) {
+	v := validator.NewValidator()
+	v.SetFieldValidationFunc("eqfield", func(val interface{}, f validator.Field, param string) error {
+		other, ok := f.Sibling(param)
+		if !ok {
+			return validator.ErrBadParameter
+		}
+		if other.Interface() != val {
+			return fmt.Errorf("%s must equal %s", f.Name, param)
+		}
+		return nil
+	})
+	type test struct {
+		Password string
+		Confirm  string `validate:"eqfield=Password"`
+		Other    string `validate:"eqfield=password"`
+	}
+	err := v.Validate(test{Password: "a", Confirm: "b"})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Confirm"][0].Error(), Equals, "Confirm must equal Password")
+	c.Assert(errs["Other"], HasError, validator.ErrBadParameter)
+
+	// field funcs and plain funcs share the same namespace
+	v.SetValidationFunc("eqfield", func(interface{}, string) error { return nil })
+	err = v.Validate(test{Password: "a", Confirm: "b"})
+	c.Assert(err, IsNil)
+	v.SetValidationFunc("eqfield", nil)
+	err = v.Validate(test{})
+	c.Assert(err, NotNil)
+}
+
+func (ms *MySuite) TestStructValidationFunc(c *C) {
+	type product struct {
+		ID string `validate:"nonzero"`
+	}
+	type line struct {
+		ProductID string
+		Quantity  int `validate:"min=1"`
+	}
+	type order struct {
+		Products []product
+		Lines    []line
+	}
+	type request struct {
+		Order order
+	}
+	v := validator.NewValidator()
+	err := v.SetStructValidationFunc(order{}, func(val interface{}) error {
+		o := val.(order)
+		ids := map[string]bool{}
+		for _, p := range o.Products {
+			ids[p.ID] = true
+		}
+		errs := validator.ErrorMap{}
+		for i, l := range o.Lines {
+			if !ids[l.ProductID] {
+				errs[fmt.Sprintf("Lines[%d].ProductID", i)] = validator.ErrorArray{validator.ErrInvalid}
+			}
+		}
+		if len(o.Lines) == 0 {
+			return validator.ErrZeroValue
+		}
+		return errs
+	})
+	c.Assert(err, IsNil)
+
+	o := order{
+		Products: []product{{"a"}, {"b"}},
+		Lines:    []line{{"a", 1}, {"c", 0}, {"b", 2}},
+	}
+	err = v.Validate(request{o})
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 2)
+	c.Assert(errs["Order.Lines[1].ProductID"], HasError, validator.ErrInvalid)
+	c.Assert(errs["Order.Lines[1].Quantity"], HasError, validator.ErrMin)
+
+	err = v.Validate(&order{})
+	c.Assert(err, NotNil)
+	errs, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs[""], HasError, validator.ErrZeroValue)
+
+	// other validators are not affected
+	err = validator.Validate(request{o})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap), HasLen, 1)
+
+	c.Assert(v.SetStructValidationFunc(order{}, nil), IsNil)
+	err = v.Validate(&order{})
+	c.Assert(err, IsNil)
+	c.Assert(v.SetStructValidationFunc(1, nil), NotNil)
+}
+
 func (ms *MySuite) TestTagEscape(c *C) {
 	type test struct {
 		A string `validate:"min=0,regexp=^a{3\\,10}"`
@@ -749,6 +848,33 @@ func (ms *MySuite) TestValidateSlice(c *C) {
 	c.Assert(errs["[1].String"], IsNil) // sanity check
 }
 
+func (ms *MySuite) TestValidateNestedCollections(c *C) {
+	type item struct {
+		A    string   `validate:"nonzero"`
+		B    string   `validate:"exclusive=A"`
+		Tags []string `validate:"max=1"`
+	}
+	ok := item{A: "a"}
+	err := validator.Validate([2][]*item{
+		{&ok, {B: "b", Tags: []string{"x", "y"}}},
+		{nil, {A: "a", B: "b"}},
+	})
+	c.Assert(err, NotNil)
+	errs, isMap := err.(validator.ErrorMap)
+	c.Assert(isMap, Equals, true)
+	c.Assert(errs, HasLen, 3, Commentf("%v", errs))
+	c.Assert(errs["[0][1].A"], HasError, validator.ErrZeroValue)
+	c.Assert(errs["[0][1].Tags"], HasError, validator.ErrMax)
+	c.Assert(errs["[1][1].B"], HasError, validator.ErrExclusive)
+
+	err = validator.Validate(map[string][]item{"batch": {ok, {}}})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["[batch](value)[1].A"], HasError, validator.ErrZeroValue)
+
+	c.Assert(validator.Validate(&[]item{ok}), IsNil)
+	c.Assert(validator.Validate([]item{}), IsNil)
+}
+
 func (ms *MySuite) TestValidateMap(c *C) {
 	type test2 struct {
 		Num    int    `validate:"max=2"`
@@ -844,6 +970,62 @@ func (ms *MySuite) TestTypeAliases(c *C) {
 	c.Assert(errs["B2"], HasError, validator.ErrMax)
 }
 
+func (ms *MySuite) TestValidateInputs(c *C) {
+	type node struct {
+		Name     string `validate:"nonzero"`
+		Parent   *node
+		Children []*node
+	}
+	type private struct {
+		a int `validate:"min=1"`
+	}
+	root := &node{Name: "root"}
+	loop := &node{Parent: root}
+	root.Children = []*node{loop}
+	loop.Children = []*node{root, loop}
+	self := map[string]interface{}{}
+	self["self"] = self
+	list := []interface{}{nil}
+	list[0] = list
+	var nilNode *node
+	var nilIface interface{} = nilNode
+	ppRoot := &root
+
+	for _, t := range []struct {
+		in  interface{}
+		err error
+	}{
+		{nil, validator.ErrInvalid},
+		{nilNode, validator.ErrInvalid},
+		{nilIface, validator.ErrInvalid},
+		{42, nil},
+		{"str", nil},
+		{new(int), nil},
+		{make(chan int), nil},
+		{func() {}, nil},
+		{[]int{1, 2}, nil},
+		{private{}, nil},
+		{struct{}{}, nil},
+		{self, nil},
+		{list, nil},
+		{[]node{{Name: "a"}}, nil},
+	} {
+		err := validator.Validate(t.in)
+		c.Assert(err, Equals, t.err, Commentf("%T", t.in))
+	}
+
+	err := validator.Validate(ppRoot)
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true)
+	c.Assert(errs, HasLen, 1)
+	c.Assert(errs["Children[0].Name"], HasError, validator.ErrZeroValue)
+
+	err = validator.Valid(nil, "incountry=US")
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorArray), HasError, validator.ErrUnsupported)
+}
+
 type hasErrorChecker struct {
 	*CheckerInfo
 }
@@ -0,0 +1,510 @@
+// Package validatortest provides helpers for testing the validation
+// rules of types declared with package validator.
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validatortest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"gopkg.in/validator.v2"
+)
+
+// maxTries is the number of values tried when looking for one meeting
+// all the constraints of a field.
+const maxTries = 100
+
+// Check generates values of the type of v, a struct or a pointer to one,
+// that satisfy its constraints and values that violate each of them in
+// turn, and reports through t every value mv, or the default validator
+// if mv is nil, disagrees about. It catches rules that contradict each
+// other, such as min=5,max=3 or len=3,regexp=^a{5}$, and rules that do
+// not check what they are meant to.
+//
+// Only fields whose constraints are all among nonzero, len, min, max,
+// regexp and enum are generated, keyed as Describe keys them; the other
+// fields keep their value in v, which should satisfy their constraints.
+// config sets the number of values tried and the source of randomness;
+// a nil config uses the defaults of package testing/quick.
+func Check(t testing.TB, mv *validator.Validator, v interface{}, config *quick.Config) {
+	t.Helper()
+	if mv == nil {
+		mv = validator.WithNameTag("")
+	} else {
+		mv = mv.WithNameTag("")
+	}
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		t.Fatalf("validatortest: cannot check %T", v)
+		return
+	}
+	ds, err := mv.Describe(rv.Interface())
+	if err != nil {
+		t.Fatalf("validatortest: %v", err)
+		return
+	}
+	var fields []*field
+	for _, d := range ds {
+		if f := newField(d, rv.Type()); f != nil {
+			fields = append(fields, f)
+		}
+	}
+
+	r, count := rand.New(rand.NewSource(time.Now().UnixNano())), 100
+	if config != nil {
+		if config.Rand != nil {
+			r = config.Rand
+		}
+		if config.MaxCount > 0 {
+			count = config.MaxCount
+		} else if config.MaxCountScale > 0 {
+			count = int(float64(count) * config.MaxCountScale)
+		}
+	}
+	for i := 0; i < count; i++ {
+		value := reflect.New(rv.Type()).Elem()
+		value.Set(rv)
+		for _, f := range fields {
+			f.own(value)
+		}
+		for j := 0; j < len(fields); j++ {
+			f := fields[j]
+			if !f.generate(r, value) {
+				t.Errorf("%s: no value satisfies %s", f.name, f.rules)
+				fields = append(fields[:j], fields[j+1:]...)
+				j--
+			}
+		}
+		errs := validate(t, mv, value)
+		for _, f := range fields {
+			if err := errs[f.name]; err != nil {
+				t.Errorf("%s: value %s satisfying %s rejected: %v", f.name, show(f.get(value)), f.rules, err)
+			}
+		}
+		for _, f := range fields {
+			for _, c := range f.constraints {
+				saved := reflect.New(f.typ).Elem()
+				saved.Set(f.get(value))
+				if !f.violate(r, value, c) {
+					continue
+				}
+				if errs := validate(t, mv, value); errs[f.name] == nil {
+					t.Errorf("%s: value %s violating %s accepted", f.name, show(f.get(value)), ruleString(c))
+				}
+				f.set(value, saved)
+			}
+		}
+	}
+}
+
+// validate validates value with mv and returns its errors by key.
+func validate(t testing.TB, mv *validator.Validator, value reflect.Value) validator.ErrorMap {
+	t.Helper()
+	err := mv.Validate(value.Interface())
+	if err == nil {
+		return nil
+	}
+	errs, ok := err.(validator.ErrorMap)
+	if !ok {
+		t.Fatalf("validatortest: %v", err)
+	}
+	return errs
+}
+
+// show formats a generated value for a report.
+func show(v reflect.Value) string {
+	if v.Kind() == reflect.String {
+		return fmt.Sprintf("%q", v.String())
+	}
+	if v.Kind() == reflect.Slice || v.Kind() == reflect.Map {
+		return fmt.Sprintf("of length %d", v.Len())
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// field is a field whose values can be generated from its constraints.
+type field struct {
+	name  string
+	path  []int
+	typ   reflect.Type
+	rules string
+	// constraints holds the rules of the field that can be violated.
+	constraints []validator.Rule
+
+	required bool
+	// lo and hi bound the length of strings, slices and maps, or the
+	// value of numbers; hi is +Inf if there is no bound.
+	lo, hi  float64
+	pattern *pattern
+	enum    []string
+}
+
+// newField returns the field of the struct type t described by d, or nil
+// if its values cannot be generated.
+func newField(d validator.FieldDescription, t reflect.Type) *field {
+	f := &field{name: d.Name, lo: math.Inf(-1), hi: math.Inf(1)}
+	for _, part := range strings.Split(d.Name, ".") {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+		sf, ok := t.FieldByName(part)
+		if !ok || len(sf.Index) != 1 {
+			return nil
+		}
+		f.path = append(f.path, sf.Index[0])
+		t = sf.Type
+	}
+	f.typ = t
+	rules := make([]string, len(d.Rules))
+	for i, r := range d.Rules {
+		rules[i] = ruleString(r)
+	}
+	f.rules = strings.Join(rules, ",")
+
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		f.lo = 0
+		if d.MinLength != nil {
+			f.lo = float64(*d.MinLength)
+		}
+		if d.MaxLength != nil {
+			f.hi = float64(*d.MaxLength)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f.lo, f.hi = -math.Pow(2, float64(t.Bits()-1)), math.Pow(2, float64(t.Bits()-1))-1
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f.lo, f.hi = 0, math.Pow(2, float64(t.Bits()))-1
+	case reflect.Float32, reflect.Float64:
+	default:
+		return nil
+	}
+	if d.Min != nil {
+		f.lo = math.Max(f.lo, *d.Min)
+	}
+	if d.Max != nil {
+		f.hi = math.Min(f.hi, *d.Max)
+	}
+	for _, r := range d.Rules {
+		switch r.Name {
+		case "nonzero":
+			f.required = true
+		case "len", "min", "max":
+			if d.MinLength == nil && d.MaxLength == nil && d.Min == nil && d.Max == nil {
+				// sizes and limits cannot be generated
+				return nil
+			}
+		case "regexp":
+			if t.Kind() != reflect.String {
+				return nil
+			}
+			p, err := parsePattern(r.Param)
+			if err != nil {
+				return nil
+			}
+			f.pattern = p
+		case "enum":
+			if len(d.Enum) == 0 || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+				return nil
+			}
+			f.enum = d.Enum
+		default:
+			return nil
+		}
+		f.constraints = append(f.constraints, r)
+	}
+	return f
+}
+
+// ruleString returns r the way it is written in tags.
+func ruleString(r validator.Rule) string {
+	if r.Param == "" {
+		return r.Name
+	}
+	return r.Name + "=" + r.Param
+}
+
+// own replaces the pointers on the path to the field in the struct value
+// with pointers to copies of the values they point to, or to new values
+// if they are nil, so that the values generated are not written to the
+// value checked.
+func (f *field) own(value reflect.Value) {
+	for _, i := range f.path {
+		for value.Kind() == reflect.Ptr {
+			p := reflect.New(value.Type().Elem())
+			if !value.IsNil() {
+				p.Elem().Set(value.Elem())
+			}
+			value.Set(p)
+			value = p.Elem()
+		}
+		value = value.Field(i)
+	}
+}
+
+// get returns the field of the struct value.
+func (f *field) get(value reflect.Value) reflect.Value {
+	for _, i := range f.path {
+		for value.Kind() == reflect.Ptr {
+			if value.IsNil() {
+				value.Set(reflect.New(value.Type().Elem()))
+			}
+			value = value.Elem()
+		}
+		value = value.Field(i)
+	}
+	return value
+}
+
+// set sets the field of the struct value to a copy of v.
+func (f *field) set(value, v reflect.Value) {
+	f.get(value).Set(v)
+}
+
+// generate sets the field of the struct value to a random value meeting
+// all its constraints. It returns false if it finds none.
+func (f *field) generate(r *rand.Rand, value reflect.Value) bool {
+	for i := 0; i < maxTries; i++ {
+		v, ok := f.candidate(r)
+		if ok && f.satisfies(v) {
+			f.get(value).Set(v)
+			return true
+		}
+	}
+	return false
+}
+
+// candidate returns a random value likely to meet the constraints of f.
+func (f *field) candidate(r *rand.Rand) (reflect.Value, bool) {
+	v := reflect.New(f.typ).Elem()
+	switch {
+	case f.enum != nil:
+		return f.parse(f.enum[r.Intn(len(f.enum))])
+	case f.pattern != nil:
+		v.SetString(f.pattern.generate(r))
+		return v, true
+	}
+	switch f.typ.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		lo := f.lo
+		if f.required && lo < 1 {
+			lo = 1
+		}
+		hi := math.Min(f.hi, lo+10)
+		if hi < lo {
+			return v, false
+		}
+		n := int(lo) + r.Intn(int(hi-lo)+1)
+		return f.sized(r, n), true
+	}
+	lo, hi := math.Max(f.lo, -1e6), math.Min(f.hi, 1e6)
+	if lo > hi {
+		lo, hi = f.lo, f.hi
+	}
+	x := lo + r.Float64()*(hi-lo)
+	if f.typ.Kind() != reflect.Float32 && f.typ.Kind() != reflect.Float64 {
+		x = math.Floor(x)
+		if x < f.lo {
+			x++
+		}
+	}
+	return f.number(x)
+}
+
+// sized returns a string, slice or map of n items.
+func (f *field) sized(r *rand.Rand, n int) reflect.Value {
+	v := reflect.New(f.typ).Elem()
+	switch f.typ.Kind() {
+	case reflect.String:
+		b := make([]byte, n)
+		for i := range b {
+			b[i] = byte('a' + r.Intn(26))
+		}
+		v.SetString(string(b))
+	case reflect.Slice:
+		v.Set(reflect.MakeSlice(f.typ, n, n))
+	case reflect.Map:
+		v.Set(reflect.MakeMap(f.typ))
+		for i := 0; i < n && v.Len() < n; i++ {
+			k, ok := quick.Value(f.typ.Key(), r)
+			if !ok {
+				break
+			}
+			v.SetMapIndex(k, reflect.New(f.typ.Elem()).Elem())
+		}
+	}
+	return v
+}
+
+// number returns the number x as a value of the type of f, if it can
+// hold it.
+func (f *field) number(x float64) (reflect.Value, bool) {
+	v := reflect.New(f.typ).Elem()
+	switch f.typ.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if x != math.Trunc(x) || v.OverflowInt(int64(x)) || math.Abs(x) >= math.MaxInt64 {
+			return v, false
+		}
+		v.SetInt(int64(x))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if x < 0 || x != math.Trunc(x) || x >= math.MaxUint64 || v.OverflowUint(uint64(x)) {
+			return v, false
+		}
+		v.SetUint(uint64(x))
+	default:
+		if math.IsInf(x, 0) || v.OverflowFloat(x) {
+			return v, false
+		}
+		v.SetFloat(x)
+	}
+	return v, true
+}
+
+// parse returns the value of the type of f whose string form is s.
+func (f *field) parse(s string) (reflect.Value, bool) {
+	if f.typ.Kind() == reflect.String {
+		v := reflect.New(f.typ).Elem()
+		v.SetString(s)
+		return v, true
+	}
+	var x float64
+	if _, err := fmt.Sscan(s, &x); err != nil {
+		return reflect.Value{}, false
+	}
+	return f.number(x)
+}
+
+// size returns the number of characters of a string or the number of
+// items of a slice or map, or the value of a number.
+func (f *field) size(v reflect.Value) float64 {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(len([]rune(v.String())))
+	case reflect.Slice, reflect.Map:
+		return float64(v.Len())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	}
+	return v.Float()
+}
+
+// satisfies reports whether v meets all the constraints of f.
+func (f *field) satisfies(v reflect.Value) bool {
+	if f.required && (f.isSized() && v.Len() == 0 || !f.isSized() && v.IsZero()) {
+		return false
+	}
+	if n := f.size(v); n < f.lo || n > f.hi {
+		return false
+	}
+	if f.pattern != nil && !f.pattern.re.MatchString(v.String()) {
+		return false
+	}
+	return f.enum == nil || f.inEnum(v)
+}
+
+// violate sets the field of the struct value to a random value violating
+// the constraint c of f. It returns false if no value can.
+func (f *field) violate(r *rand.Rand, value reflect.Value, c validator.Rule) bool {
+	var v reflect.Value
+	ok := false
+	switch c.Name {
+	case "nonzero":
+		v, ok = reflect.New(f.typ).Elem(), true
+	case "len", "min", "max":
+		v, ok = f.outside(r, c)
+	case "regexp":
+		for i := 0; i < maxTries && !ok; i++ {
+			v = f.sized(r, r.Intn(20))
+			ok = !f.pattern.re.MatchString(v.String())
+		}
+	case "enum":
+		for i := 0; i < maxTries && !ok; i++ {
+			v, ok = f.unconstrained(r)
+			ok = ok && !f.inEnum(v)
+		}
+	}
+	if ok {
+		f.get(value).Set(v)
+	}
+	return ok
+}
+
+// outside returns a value out of the bounds set by the len, min or max
+// constraint c.
+func (f *field) outside(r *rand.Rand, c validator.Rule) (reflect.Value, bool) {
+	var p float64
+	if _, err := fmt.Sscan(c.Param, &p); err != nil {
+		return reflect.Value{}, false
+	}
+	below := c.Name == "min" || c.Name == "len" && r.Intn(2) == 0
+	x := p + 1 + float64(r.Intn(3))
+	if below {
+		x = p - 1 - float64(r.Intn(3))
+	}
+	if !f.isSized() {
+		return f.number(x)
+	}
+	if below && x < 0 {
+		if c.Name == "min" || p > 0 {
+			x = p - 1
+		} else {
+			x = p + 1
+		}
+	}
+	if x < 0 || x > 1<<16 {
+		return reflect.Value{}, false
+	}
+	return f.sized(r, int(x)), true
+}
+
+// unconstrained returns a random value of the type of f.
+func (f *field) unconstrained(r *rand.Rand) (reflect.Value, bool) {
+	if f.typ.Kind() == reflect.String {
+		return f.sized(r, 1+r.Intn(10)), true
+	}
+	return f.number(math.Floor(r.Float64()*2000) - 1000)
+}
+
+// inEnum reports whether v is one of the values of the enum of f.
+func (f *field) inEnum(v reflect.Value) bool {
+	s := fmt.Sprint(v.Interface())
+	for _, e := range f.enum {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
+
+// isSized reports whether the constraints of f bound its length.
+func (f *field) isSized() bool {
+	k := f.typ.Kind()
+	return k == reflect.String || k == reflect.Slice || k == reflect.Map
+}
@@ -0,0 +1,122 @@
+// Package validatortest provides helpers for testing the validation
+// rules of types declared with package validator.
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package validatortest_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"testing/quick"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+	"gopkg.in/validator.v2/validatortest"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+type MySuite struct{}
+
+var _ = Suite(&MySuite{})
+
+// recorder is a testing.TB recording the errors reported.
+type recorder struct {
+	testing.TB
+	name   string
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Name() string {
+	return r.name
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.Errorf(format, args...)
+}
+
+type checkAddress struct {
+	City string `validate:"nonzero,max=40"`
+}
+
+type checkSignup struct {
+	Username string   `validate:"nonzero,min=3,max=20,regexp=^[a-z]+$"`
+	Code     string   `validate:"len=6,regexp=^[0-9]+$"`
+	Age      int      `validate:"min=13,max=120"`
+	Score    float64  `validate:"max=1"`
+	Tags     []string `validate:"nonzero,max=5"`
+	Level    uint8    `validate:"nonzero"`
+	Cursor   string   `validate:"base64url"`
+	Address  *checkAddress
+}
+
+type checkBroken struct {
+	Name string `validate:"min=5,max=3"`
+	Pin  string `validate:"len=3,regexp=^a{5}$"`
+	Ok   int    `validate:"max=10"`
+}
+
+func (ms *MySuite) TestCheck(c *C) {
+	config := &quick.Config{MaxCount: 50, Rand: rand.New(rand.NewSource(1))}
+	r := &recorder{}
+	validatortest.Check(r, nil, checkSignup{}, config)
+	c.Assert(r.errors, HasLen, 0)
+
+	r = &recorder{}
+	validatortest.Check(r, nil, &checkBroken{}, config)
+	c.Assert(r.errors, DeepEquals, []string{
+		"Name: no value satisfies min=5,max=3",
+		"Pin: no value satisfies len=3,regexp=^a{5}$",
+	})
+
+	// a rule that does not check what its tag says
+	mv := validator.NewValidator()
+	c.Assert(mv.SetValidationFunc("max", func(interface{}, string) error { return nil }), IsNil)
+	r = &recorder{}
+	validatortest.Check(r, mv, checkAddress{}, &quick.Config{MaxCount: 1, Rand: rand.New(rand.NewSource(1))})
+	c.Assert(r.errors, HasLen, 1)
+	c.Assert(r.errors[0], Matches, `City: value "[a-z]{41,43}" violating max=40 accepted`)
+
+	r = &recorder{}
+	validatortest.Check(r, nil, 1, nil)
+	c.Assert(r.errors, DeepEquals, []string{"validatortest: cannot check int"})
+}
+
+func (ms *MySuite) TestCheckLeavesValueUnchanged(c *C) {
+	type in struct {
+		Name string `validate:"min=3,max=5"`
+	}
+	type out struct {
+		P  *in
+		PP **in
+	}
+	inner := &in{Name: "abcd"}
+	o := &out{P: &in{Name: "abcd"}, PP: &inner}
+	r := &recorder{}
+	validatortest.Check(r, nil, o, &quick.Config{MaxCount: 20, Rand: rand.New(rand.NewSource(1))})
+	c.Assert(r.errors, HasLen, 0)
+	c.Assert(o.P.Name, Equals, "abcd")
+	c.Assert(*o.PP, Equals, inner)
+	c.Assert(inner.Name, Equals, "abcd")
+}
@@ -0,0 +1,126 @@
+// Package validatortest provides helpers for testing the validation
+// rules of types declared with package validator.
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package validatortest
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/validator.v2"
+)
+
+var update = flag.Bool("validatortest.update", false, "update the golden files of validatortest.Golden")
+
+// Golden compares the canonical form of err, as given by Format, to the
+// golden file of the test, testdata/<test name>.golden, and reports any
+// difference through t, so that changes to the errors reported are
+// reviewed like any other change. Running the tests with
+// -validatortest.update writes the golden files instead.
+func Golden(t testing.TB, err error) {
+	t.Helper()
+	name := strings.NewReplacer("/", "_", " ", "_", ":", "_").Replace(t.Name())
+	path := filepath.Join("testdata", name+".golden")
+	got := []byte(Format(err))
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatalf("validatortest: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("validatortest: %v", err)
+		}
+		return
+	}
+	want, rerr := os.ReadFile(path)
+	if rerr != nil {
+		t.Fatalf("validatortest: %v (run with -validatortest.update to create it)", rerr)
+		return
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("validatortest: errors differ from %s\n--- got\n%s--- want\n%s", path, got, want)
+	}
+}
+
+// Format returns the canonical form of the errors of err: a line per
+// error, "key: message", sorted by key and then in the order reported.
+// Errors of nested ErrorMaps, such as those of field validation
+// functions, are keyed under the field holding them, errors of ErrorArrays
+// are listed one by one and errors reported for no key are keyed "-".
+// Format returns the empty string for a nil error.
+func Format(err error) string {
+	var lines []string
+	formatError("", err, &lines)
+	// the order of the errors of a key is kept
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lineKey(lines[i]) < lineKey(lines[j])
+	})
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// formatError appends the lines of the errors of err, keyed under key,
+// to lines.
+func formatError(key string, err error, lines *[]string) {
+	switch e := err.(type) {
+	case nil:
+	case validator.ErrorMap:
+		keys := make([]string, 0, len(e))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			formatError(joinKey(key, k), e[k], lines)
+		}
+	case validator.ErrorArray:
+		for _, err := range e {
+			formatError(key, err, lines)
+		}
+	default:
+		if key == "" {
+			key = "-"
+		}
+		msg := strings.Replace(err.Error(), "\n", `\n`, -1)
+		*lines = append(*lines, key+": "+msg)
+	}
+}
+
+// lineKey returns the key of a line of Format.
+func lineKey(line string) string {
+	return line[:strings.Index(line, ": ")]
+}
+
+// joinKey returns the key of the error key within the value keyed by
+// prefix, as in "Lines[2].Price".
+func joinKey(prefix, key string) string {
+	switch {
+	case prefix == "":
+		return key
+	case key == "":
+		return prefix
+	case key[0] == '[':
+		return prefix + key
+	default:
+		return prefix + "." + key
+	}
+}
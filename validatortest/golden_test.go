@@ -0,0 +1,66 @@
+// Package validatortest provides helpers for testing the validation
+// rules of types declared with package validator.
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+package validatortest_test
+
+import (
+	"errors"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+	"gopkg.in/validator.v2/validatortest"
+)
+
+type goldenLine struct {
+	SKU string `validate:"nonzero"`
+	Qty int    `validate:"min=1"`
+}
+
+type goldenOrder struct {
+	ID    string       `validate:"nonzero,min=3"`
+	Lines []goldenLine `validate:"nonzero"`
+}
+
+func (ms *MySuite) TestFormat(c *C) {
+	err := validator.Validate(goldenOrder{ID: "", Lines: []goldenLine{{SKU: "a"}, {Qty: 2}}})
+	c.Assert(validatortest.Format(err), Equals, ""+
+		"ID: zero value\n"+
+		"ID: less than min\n"+
+		"Lines[0].Qty: less than min\n"+
+		"Lines[1].SKU: zero value\n")
+
+	err = validator.ErrorArray{
+		validator.ErrorMap{"B": {validator.ErrMin}, "A": {errors.New("two\nlines")}},
+		validator.ErrUnsupported,
+	}
+	c.Assert(validatortest.Format(err), Equals, "-: unsupported type\nA: two\\nlines\nB: less than min\n")
+	c.Assert(validatortest.Format(nil), Equals, "")
+}
+
+func (ms *MySuite) TestGolden(c *C) {
+	r := &recorder{name: "TestGolden"}
+	validatortest.Golden(r, validator.Validate(goldenOrder{ID: "ab"}))
+	c.Assert(r.errors, HasLen, 0)
+
+	validatortest.Golden(r, validator.ErrorMap{"ID": {validator.ErrZeroValue}})
+	c.Assert(r.errors, DeepEquals, []string{"validatortest: errors differ from testdata/TestGolden.golden\n" +
+		"--- got\nID: zero value\n--- want\nID: less than min\nLines: zero value\n"})
+
+	r = &recorder{name: "TestMissing"}
+	validatortest.Golden(r, nil)
+	c.Assert(r.errors, HasLen, 1)
+	c.Assert(r.errors[0], Matches, "validatortest: .*TestMissing.golden.* \\(run with -validatortest.update to create it\\)")
+}
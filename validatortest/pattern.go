@@ -0,0 +1,113 @@
+// Package validatortest provides helpers for testing the validation
+// rules of types declared with package validator.
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validatortest
+
+import (
+	"math/rand"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// maxRepeat is the number of extra repetitions generated for unbounded
+// repeats such as * and +.
+const maxRepeat = 5
+
+// pattern generates strings matching a regular expression.
+type pattern struct {
+	re   *regexp.Regexp
+	prog *syntax.Regexp
+}
+
+// parsePattern parses the pattern of a regexp constraint.
+func parsePattern(expr string) (*pattern, error) {
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, err
+	}
+	prog, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return nil, err
+	}
+	return &pattern{re: re, prog: prog.Simplify()}, nil
+}
+
+// generate returns a random string likely to match p. Strings matched
+// only thanks to anchors or word boundaries may not match.
+func (p *pattern) generate(r *rand.Rand) string {
+	var b strings.Builder
+	generateRegexp(r, p.prog, &b)
+	return b.String()
+}
+
+// generateRegexp appends a random string matching re to b.
+func generateRegexp(r *rand.Rand, re *syntax.Regexp, b *strings.Builder) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		// pairs of ranges, preferring printable ASCII ones
+		var ranges [][2]rune
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			lo, hi := re.Rune[i], re.Rune[i+1]
+			if lo < ' ' {
+				lo = ' '
+			}
+			if hi > '~' {
+				hi = '~'
+			}
+			if lo <= hi {
+				ranges = append(ranges, [2]rune{lo, hi})
+			}
+		}
+		if len(ranges) == 0 {
+			if len(re.Rune) == 0 {
+				return
+			}
+			ranges = append(ranges, [2]rune{re.Rune[0], re.Rune[0]})
+		}
+		rg := ranges[r.Intn(len(ranges))]
+		b.WriteRune(rg[0] + rune(r.Intn(int(rg[1]-rg[0])+1)))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune(rune('a' + r.Intn(26)))
+	case syntax.OpCapture:
+		generateRegexp(r, re.Sub[0], b)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			generateRegexp(r, sub, b)
+		}
+	case syntax.OpAlternate:
+		generateRegexp(r, re.Sub[r.Intn(len(re.Sub))], b)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := 0, maxRepeat
+		switch re.Op {
+		case syntax.OpPlus:
+			lo, hi = 1, 1+maxRepeat
+		case syntax.OpQuest:
+			hi = 1
+		case syntax.OpRepeat:
+			lo, hi = re.Min, re.Max
+			if hi < 0 {
+				hi = lo + maxRepeat
+			}
+		}
+		for n := lo + r.Intn(hi-lo+1); n > 0; n-- {
+			generateRegexp(r, re.Sub[0], b)
+		}
+	}
+}
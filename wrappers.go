@@ -0,0 +1,131 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+)
+
+// UnwrapFunc returns the value held by a wrapper, such as an Optional[T]
+// or Nullable[T] value or an sql.NullString, and whether it holds one.
+type UnwrapFunc func(v reflect.Value) (reflect.Value, bool)
+
+// SetWrapper calls the SetWrapper method on the default validator.
+func SetWrapper(typ interface{}, fn UnwrapFunc) error {
+	return setDefault(func() error {
+		return defaultValidator.SetWrapper(typ, fn)
+	})
+}
+
+// SetWrapper sets the function unwrapping the values of the type of typ,
+// or of the generic type typ is an instantiation of, in all of its
+// instantiations: setting it for Optional[int]{} also covers
+// Optional[string]. For fields holding such values nonzero and nonnil
+// mean the field holds a value, the other constraints apply to the value
+// held, if any, and the errors of the value held are keyed under the
+// field. The protobuf well-known types are unwrapped by default. Calling
+// this function with nil fn removes the function for the type.
+func (mv *Validator) SetWrapper(typ interface{}, fn UnwrapFunc) error {
+	t := reflect.TypeOf(typ)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	key := wrapperKey(t)
+	if key == "" {
+		return errors.New("type must be a named type")
+	}
+	mv.own()
+	if fn == nil {
+		delete(mv.wrappers, key)
+		return nil
+	}
+	mv.wrappers[key] = fn
+	return nil
+}
+
+// wrapperKey returns the key of the wrappers of type t, which is the
+// same for all the instantiations of a generic type, or the empty string
+// if t is not a named type.
+func wrapperKey(t reflect.Type) string {
+	if t == nil || t.Name() == "" {
+		return ""
+	}
+	name := t.Name()
+	if i := strings.IndexByte(name, '['); i >= 0 {
+		name = name[:i]
+	}
+	return t.PkgPath() + "." + name
+}
+
+// unwrap returns the value held by v, whether it holds one and whether v
+// is a wrapper at all. Nil pointers to wrappers hold no value.
+func (mv *Validator) unwrap(v reflect.Value) (reflect.Value, bool, bool) {
+	if !v.IsValid() {
+		return reflect.Value{}, false, false
+	}
+	t := v.Type()
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return reflect.Value{}, false, false
+	}
+	fn, ok := mv.wrappers[wrapperKey(t)]
+	if !ok {
+		if fn, ok = protoWrappers[protoName(t)]; !ok {
+			return reflect.Value{}, false, false
+		}
+	}
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}, false, true
+		}
+		v = v.Elem()
+	}
+	inner, set := fn(v)
+	if !set {
+		return reflect.Value{}, false, true
+	}
+	for (inner.Kind() == reflect.Ptr || inner.Kind() == reflect.Interface) && !inner.IsNil() {
+		inner = inner.Elem()
+	}
+	return inner, true, true
+}
+
+// wrappedTag returns the constraints of tag that apply to a wrapper: only
+// nonzero and nonnil if it holds no value, and all the others if it does.
+func (mv *Validator) wrappedTag(tag string, set bool) string {
+	tags, err := mv.parseTags(tag)
+	if err != nil {
+		// reported by the validation
+		return tag
+	}
+	var kept []string
+	for _, t := range tags {
+		if (t.Name == "nonzero" || t.Name == "nonnil") == set {
+			continue
+		}
+		s := t.Name
+		if t.Param != "" {
+			s += "=" + strings.Replace(t.Param, ",", `\,`, -1)
+		}
+		kept = append(kept, s)
+	}
+	return strings.Join(kept, ",")
+}
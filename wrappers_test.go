@@ -0,0 +1,83 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package validator_test
+
+import (
+	"database/sql"
+	"reflect"
+
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+// Optional is a generic wrapper holding a value or nothing.
+type Optional[T any] struct {
+	Value T
+	Set   bool
+}
+
+type wrappedAddress struct {
+	City string `validate:"nonzero"`
+}
+
+func (ms *MySuite) TestSetWrapper(c *C) {
+	type profile struct {
+		Nickname Optional[string] `validate:"nonzero,min=3"`
+		Age      Optional[int]    `validate:"max=120"`
+		Score    Optional[int]    `validate:"nonzero"`
+		Address  Optional[wrappedAddress]
+		Previous []Optional[wrappedAddress]
+		Email    sql.NullString `validate:"regexp=@"`
+	}
+	mv := validator.NewValidator()
+	c.Assert(mv.SetWrapper(Optional[int]{}, func(v reflect.Value) (reflect.Value, bool) {
+		return v.FieldByName("Value"), v.FieldByName("Set").Bool()
+	}), IsNil)
+	c.Assert(mv.SetWrapper(sql.NullString{}, func(v reflect.Value) (reflect.Value, bool) {
+		ns := v.Interface().(sql.NullString)
+		return reflect.ValueOf(ns.String), ns.Valid
+	}), IsNil)
+
+	// zero values held count as set
+	p := profile{
+		Nickname: Optional[string]{Value: "bob", Set: true},
+		Score:    Optional[int]{Set: true},
+	}
+	c.Assert(mv.Validate(p), IsNil)
+
+	p = profile{
+		Age:      Optional[int]{Value: 130, Set: true},
+		Address:  Optional[wrappedAddress]{Set: true},
+		Previous: []Optional[wrappedAddress]{{}, {Set: true}},
+		Email:    sql.NullString{String: "bob", Valid: true},
+	}
+	c.Assert(mv.Validate(p), DeepEquals, validator.ErrorMap{
+		"Nickname":         {validator.ErrZeroValue},
+		"Age":              {validator.ErrMax},
+		"Score":            {validator.ErrZeroValue},
+		"Address.City":     {validator.ErrZeroValue},
+		"Previous[1].City": {validator.ErrZeroValue},
+		"Email":            {validator.ErrRegexp},
+	})
+
+	// removing the wrapper of one instantiation removes that of all
+	c.Assert(mv.SetWrapper(Optional[bool]{}, nil), IsNil)
+	err := mv.Validate(profile{})
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["Address.Value.City"], NotNil)
+	c.Assert(mv.SetWrapper(struct{}{}, nil), NotNil)
+}
@@ -0,0 +1,133 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !validator_minimal
+
+package validator
+
+import (
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ValidateYAML decodes a YAML document into v and validates it using
+// the default validator. See Validator.ValidateYAML.
+func ValidateYAML(data []byte, v interface{}) error {
+	return defaultValidator.ValidateYAML(data, v)
+}
+
+// ValidateYAML decodes a YAML document into v, which must be a pointer
+// as for yaml.Unmarshal, and validates it. Errors are keyed by the names
+// given in yaml struct tags, or the lower case field names yaml uses by
+// default, and wrapped in a PositionErr giving the line and column of
+// the node that failed or, for missing values, of their parent node.
+// Decoding errors are returned as is.
+func (mv *Validator) ValidateYAML(data []byte, v interface{}) error {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	if err := doc.Decode(v); err != nil {
+		return err
+	}
+	err := mv.WithNameTag("yaml").Validate(v)
+	errs, ok := err.(ErrorMap)
+	if !ok {
+		return err
+	}
+	for key, arr := range errs {
+		n := yamlNode(&doc, key)
+		if n == nil {
+			continue
+		}
+		for i, e := range arr {
+			arr[i] = PositionErr{Err: e, Line: n.Line, Column: n.Column}
+		}
+	}
+	return errs
+}
+
+// yamlNode returns the node of doc at the error key path, such as
+// "steps[0].name", or the deepest node found along it.
+func yamlNode(doc *yaml.Node, path string) *yaml.Node {
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return nil
+	}
+	n := doc.Content[0]
+	for path != "" {
+		for n.Kind == yaml.AliasNode && n.Alias != nil {
+			n = n.Alias
+		}
+		var next *yaml.Node
+		switch path[0] {
+		case '.':
+			path = path[1:]
+			continue
+		case '[':
+			end := strings.IndexByte(path, ']')
+			if end < 0 {
+				return n
+			}
+			index, suffix := path[1:end], ""
+			path = path[end+1:]
+			for _, s := range []string{"(key)", "(value)"} {
+				if strings.HasPrefix(path, s) {
+					suffix, path = s, path[len(s):]
+				}
+			}
+			switch {
+			case n.Kind == yaml.SequenceNode && suffix == "":
+				if i, err := strconv.Atoi(index); err == nil && i >= 0 && i < len(n.Content) {
+					next = n.Content[i]
+				}
+			case n.Kind == yaml.MappingNode:
+				key, value := yamlMapping(n, index)
+				if suffix == "(key)" {
+					next = key
+				} else {
+					next = value
+				}
+			}
+		default:
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			name := path[:end]
+			path = path[end:]
+			if n.Kind == yaml.MappingNode {
+				_, next = yamlMapping(n, name)
+			}
+		}
+		if next == nil {
+			return n
+		}
+		n = next
+	}
+	return n
+}
+
+// yamlMapping returns the key and value nodes of a mapping node's key.
+func yamlMapping(n *yaml.Node, key string) (*yaml.Node, *yaml.Node) {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i], n.Content[i+1]
+		}
+	}
+	return nil, nil
+}
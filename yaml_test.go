@@ -0,0 +1,91 @@
+// Package validator implements value validations
+//
+// Copyright 2014 Roberto Teixeira <robteix@robteix.com>
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !validator_minimal
+
+package validator_test
+
+import (
+	. "gopkg.in/check.v1"
+	"gopkg.in/validator.v2"
+)
+
+type pipeline struct {
+	Name    string            `validate:"nonzero"`
+	Timeout int               `yaml:"timeout_minutes,omitempty" validate:"max=60"`
+	Steps   []pipelineStep    `yaml:"steps" validate:"min=1"`
+	Env     map[string]envVar `yaml:"env"`
+}
+
+type pipelineStep struct {
+	Run   string `yaml:"run" validate:"nonzero"`
+	Image string `yaml:"image" validate:"regexp=^[a-z0-9./:-]+$"`
+}
+
+type envVar struct {
+	Value string `yaml:"value" validate:"max=8"`
+}
+
+func (ms *MySuite) TestValidateYAML(c *C) {
+	var p pipeline
+	err := validator.ValidateYAML([]byte(`
+name: build
+steps:
+  - run: make
+    image: golang:1.22
+`), &p)
+	c.Assert(err, IsNil)
+	c.Assert(p.Steps, HasLen, 1)
+
+	p = pipeline{}
+	err = validator.ValidateYAML([]byte(`
+timeout_minutes: 90
+steps:
+  - {run: make, image: alpine}
+  - image: Not An Image
+env:
+  TOKEN:
+    value: far too long
+`), &p)
+	c.Assert(err, NotNil)
+	errs, ok := err.(validator.ErrorMap)
+	c.Assert(ok, Equals, true, Commentf("%v", err))
+	c.Assert(errs, HasLen, 5, Commentf("%v", errs))
+	for key, pos := range map[string][2]int{
+		"name":                    {2, 1},
+		"timeout_minutes":         {2, 18},
+		"steps[1].run":            {5, 5},
+		"steps[1].image":          {5, 12},
+		"env[TOKEN](value).value": {8, 12},
+	} {
+		c.Assert(errs[key], HasLen, 1, Commentf(key))
+		perr, ok := errs[key][0].(validator.PositionErr)
+		c.Assert(ok, Equals, true, Commentf(key))
+		c.Assert([2]int{perr.Line, perr.Column}, Equals, pos, Commentf(key))
+	}
+	c.Assert(errs["name"][0].(validator.PositionErr).Err, Equals, validator.ErrZeroValue)
+	c.Assert(errs["timeout_minutes"][0].(validator.PositionErr).Err, Equals, validator.ErrMax)
+
+	p = pipeline{}
+	err = validator.ValidateYAML(nil, &p)
+	c.Assert(err, NotNil)
+	c.Assert(err.(validator.ErrorMap)["name"], HasError, validator.ErrZeroValue)
+
+	err = validator.ValidateYAML([]byte("steps: {"), &p)
+	c.Assert(err, NotNil)
+	_, ok = err.(validator.ErrorMap)
+	c.Assert(ok, Equals, false)
+}